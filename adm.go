@@ -0,0 +1,123 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Identifiers of ADM chunks.
+var (
+	chnaID = [4]byte{'c', 'h', 'n', 'a'}
+	axmlID = [4]byte{'a', 'x', 'm', 'l'}
+)
+
+// chnaEntrySize is the size of a single entry of chna chunk.
+const chnaEntrySize = 40
+
+// ADMTrack is the entry of chna chunk that maps the track of the stream to
+// Audio Definition Model, as defined by ITU-R BS.2076.
+type ADMTrack struct {
+	// TrackIndex is the number of the track starting from 1.
+	TrackIndex uint16
+	// UID is the audioTrackUID, e.g. "ATU_00000001".
+	UID string
+	// TrackRef is the audioTrackFormatID, e.g. "AT_00031001_01".
+	TrackRef string
+	// PackRef is the audioPackFormatID, e.g. "AP_00031001".
+	PackRef string
+}
+
+// ReadChna returns the tracks of chna chunk. Nil is returned if the stream
+// doesn't have chna chunk. Unused entries are skipped. The stream is
+// rewinded to the start afterwards.
+func ReadChna(rs io.ReadSeeker) ([]ADMTrack, error) {
+	payload, ok, err := ReadChunk(rs, chnaID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return decodeChna(payload)
+}
+
+// decodeChna returns the tracks of chna chunk payload. Unused entries are
+// skipped.
+func decodeChna(p []byte) ([]ADMTrack, error) {
+	if len(p) < 4 {
+		return nil, fmt.Errorf("chna chunk is too short: %d bytes", len(p))
+	}
+	tracks := []ADMTrack{}
+	for e := p[4:]; len(e) >= chnaEntrySize; e = e[chnaEntrySize:] {
+		index := binary.LittleEndian.Uint16(e)
+		if index == 0 {
+			continue
+		}
+		tracks = append(tracks, ADMTrack{
+			TrackIndex: index,
+			UID:        bextString(e[2:14]),
+			TrackRef:   bextString(e[14:28]),
+			PackRef:    bextString(e[28:39]),
+		})
+	}
+	return tracks, nil
+}
+
+// ReadAxml returns the ADM XML document stored in axml chunk. Nil is
+// returned if the stream doesn't have axml chunk. The stream is rewinded
+// to the start afterwards.
+func ReadAxml(rs io.ReadSeeker) ([]byte, error) {
+	payload, _, err := ReadChunk(rs, axmlID)
+	return payload, err
+}
+
+// WithADM makes Sink write chna chunk with provided tracks and axml chunk
+// with provided ADM XML document before data. The counts of chna chunk
+// are computed from the tracks. Any of the chunks is omitted if the value
+// is nil.
+func WithADM(tracks []ADMTrack, axml []byte) Option {
+	return func(o *options) {
+		o.admTracks = tracks
+		o.axml = axml
+	}
+}
+
+// admChunks returns the ADM chunks written by Sink.
+func (o *options) admChunks() []rawChunk {
+	var chunks []rawChunk
+	if o.admTracks != nil {
+		chunks = append(chunks, rawChunk{ID: chnaID, Payload: encodeChna(o.admTracks)})
+	}
+	if o.axml != nil {
+		chunks = append(chunks, rawChunk{ID: axmlID, Payload: o.axml})
+	}
+	return chunks
+}
+
+// validateChna checks if the tracks can be encoded in chna chunk.
+func validateChna(tracks []ADMTrack) error {
+	for _, t := range tracks {
+		if t.TrackIndex == 0 {
+			return fmt.Errorf("invalid ADM track index: %d", t.TrackIndex)
+		}
+		if len(t.UID) > 12 || len(t.TrackRef) > 14 || len(t.PackRef) > 11 {
+			return fmt.Errorf("ADM track %d references are too long", t.TrackIndex)
+		}
+	}
+	return nil
+}
+
+// encodeChna returns the payload of chna chunk with provided tracks.
+func encodeChna(tracks []ADMTrack) []byte {
+	indices := map[uint16]bool{}
+	p := make([]byte, 4+chnaEntrySize*len(tracks))
+	for i, t := range tracks {
+		indices[t.TrackIndex] = true
+		e := p[4+i*chnaEntrySize:]
+		binary.LittleEndian.PutUint16(e, t.TrackIndex)
+		copy(e[2:14], t.UID)
+		copy(e[14:28], t.TrackRef)
+		copy(e[28:39], t.PackRef)
+	}
+	binary.LittleEndian.PutUint16(p[0:], uint16(len(indices)))
+	binary.LittleEndian.PutUint16(p[2:], uint16(len(tracks)))
+	return p
+}
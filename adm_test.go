@@ -0,0 +1,108 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+// chnaPayload returns chna chunk payload with provided tracks and one
+// unused entry.
+func chnaPayload(tracks ...wav.ADMTrack) []byte {
+	p := make([]byte, 4, 4+40*(len(tracks)+1))
+	binary.LittleEndian.PutUint16(p[0:], uint16(len(tracks)))
+	binary.LittleEndian.PutUint16(p[2:], uint16(len(tracks)))
+	for _, t := range tracks {
+		e := make([]byte, 40)
+		binary.LittleEndian.PutUint16(e, t.TrackIndex)
+		copy(e[2:14], t.UID)
+		copy(e[14:28], t.TrackRef)
+		copy(e[28:39], t.PackRef)
+		p = append(p, e...)
+	}
+	return append(p, make([]byte, 40)...)
+}
+
+func TestReadADM(t *testing.T) {
+	tracks := []wav.ADMTrack{
+		{TrackIndex: 1, UID: "ATU_00000001", TrackRef: "AT_00010001_01", PackRef: "AP_00010002"},
+		{TrackIndex: 2, UID: "ATU_00000002", TrackRef: "AT_00010002_01", PackRef: "AP_00010002"},
+	}
+	axml := []byte(`<?xml version="1.0"?><ebuCoreMain/>`)
+	data := riff(
+		chunk("fmt ", fmtPayload(2, 16, 4, 48000)),
+		chunk("chna", chnaPayload(tracks...)),
+		chunk("axml", axml),
+		chunk("data", make([]byte, 8)),
+	)
+	result, err := wav.ReadChna(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tracks, result) {
+		t.Errorf("expected %+v got %+v", tracks, result)
+	}
+	xml, err := wav.ReadAxml(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(axml, xml) {
+		t.Errorf("expected %s got %s", axml, xml)
+	}
+
+	plain := riff(chunk("fmt ", fmtPayload(2, 16, 4, 48000)), chunk("data", make([]byte, 8)))
+	if result, err := wav.ReadChna(bytes.NewReader(plain)); err != nil || result != nil {
+		t.Errorf("expected no chna got %v %v", result, err)
+	}
+	if xml, err := wav.ReadAxml(bytes.NewReader(plain)); err != nil || xml != nil {
+		t.Errorf("expected no axml got %s %v", xml, err)
+	}
+}
+
+func TestWithADM(t *testing.T) {
+	tracks := []wav.ADMTrack{
+		{TrackIndex: 1, UID: "ATU_00000001", TrackRef: "AT_00010001_01", PackRef: "AP_00010002"},
+		{TrackIndex: 2, UID: "ATU_00000002", TrackRef: "AT_00010002_01", PackRef: "AP_00010002"},
+		{TrackIndex: 2, UID: "ATU_00000003", TrackRef: "AT_00031001_01", PackRef: "AP_00031001"},
+	}
+	// odd size is padded.
+	axml := []byte(`<ebuCoreMain/>x`)
+	var out buffer
+	transcode(t, floatSource(48000, 2, []float64{0.1, 0.2}), wav.Sink(&out, signal.BitDepth16, wav.WithADM(tracks, axml)))
+	if ids := chunkIDs(out.data); !reflect.DeepEqual(ids, []string{"fmt ", "chna", "axml", "data"}) {
+		t.Fatalf("unexpected chunks: %v", ids)
+	}
+
+	payload, _, err := wav.ReadChunk(bytes.NewReader(out.data), [4]byte{'c', 'h', 'n', 'a'})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if numTracks, numUIDs := binary.LittleEndian.Uint16(payload), binary.LittleEndian.Uint16(payload[2:]); numTracks != 2 || numUIDs != 3 {
+		t.Errorf("expected 2 tracks and 3 UIDs got %d and %d", numTracks, numUIDs)
+	}
+	result, err := wav.ReadChna(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tracks, result) {
+		t.Errorf("expected %+v got %+v", tracks, result)
+	}
+	if xml, err := wav.ReadAxml(bytes.NewReader(out.data)); err != nil || !bytes.Equal(axml, xml) {
+		t.Errorf("expected %s got %s %v", axml, xml, err)
+	}
+
+	invalid := []wav.ADMTrack{{TrackIndex: 1, UID: "ATU_000000001"}}
+	_, err = pipe.New(bufferSize, pipe.Line{
+		Source: floatSource(48000, 1, []float64{0}),
+		Sink:   wav.Sink(&buffer{}, signal.BitDepth16, wav.WithADM(invalid, nil)),
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid UID")
+	}
+}
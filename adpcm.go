@@ -0,0 +1,117 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// formatIMAADPCM is the audio format code of IMA ADPCM.
+const formatIMAADPCM = 0x11
+
+// imaStepTable is the quantizer step size for every step index.
+var imaStepTable = [89]int{
+	7, 8, 9, 10, 11, 12, 13, 14, 16, 17,
+	19, 21, 23, 25, 28, 31, 34, 37, 41, 45,
+	50, 55, 60, 66, 73, 80, 88, 97, 107, 118,
+	130, 143, 157, 173, 190, 209, 230, 253, 279, 307,
+	337, 371, 408, 449, 494, 544, 598, 658, 724, 796,
+	876, 963, 1060, 1166, 1282, 1411, 1552, 1707, 1878, 2066,
+	2272, 2499, 2749, 3024, 3327, 3660, 4026, 4428, 4871, 5358,
+	5894, 6484, 7132, 7845, 8630, 9493, 10442, 11487, 12635, 13899,
+	15289, 16818, 18500, 20350, 22385, 24623, 27086, 29794, 32767,
+}
+
+// imaIndexTable is the step index adjustment for every nibble.
+var imaIndexTable = [16]int{
+	-1, -1, -1, -1, 2, 4, 6, 8,
+	-1, -1, -1, -1, 2, 4, 6, 8,
+}
+
+// newIMADecoder returns the decoder of IMA ADPCM blocks.
+func newIMADecoder(f format) (*blockDecoder, error) {
+	channels, blockAlign := int(f.Channels), int(f.BlockAlign)
+	if channels == 0 || f.BitsPerSample != 4 || blockAlign <= 4*channels || (blockAlign-4*channels)%(4*channels) != 0 {
+		return nil, fmt.Errorf("invalid IMA ADPCM format: %d channels, %d bits, %d bytes block", channels, f.BitsPerSample, blockAlign)
+	}
+	// declared samples per block can only reduce the size of block.
+	samplesPerBlock := imaSamplesPerBlock(blockAlign, channels)
+	if declared := f.samplesPerBlock(); declared > 0 && declared < samplesPerBlock {
+		samplesPerBlock = declared
+	}
+	return &blockDecoder{
+		channels: channels,
+		block:    make([]byte, blockAlign),
+		minBlock: 4 * channels,
+		decode: func(block []byte, samples []int) int {
+			if n := imaDecodeBlock(block, samples, channels); n < samplesPerBlock {
+				return n
+			}
+			return samplesPerBlock
+		},
+		samples: make([]int, imaSamplesPerBlock(blockAlign, channels)*channels),
+	}, nil
+}
+
+// imaSamplesPerBlock returns the number of samples per channel in the
+// block of provided size. Every channel has a header with the first
+// sample and every byte has two samples.
+func imaSamplesPerBlock(blockAlign, channels int) int {
+	return (blockAlign-4*channels)*2/channels + 1
+}
+
+// imaDecodeBlock decodes the block into interleaved samples. Returns the
+// number of decoded frames.
+func imaDecodeBlock(block []byte, samples []int, channels int) int {
+	// every channel has 4 bytes per 8 samples.
+	length := (len(block)-4*channels)/(4*channels)*8 + 1
+	for ch := 0; ch < channels; ch++ {
+		header := block[4*ch:]
+		predictor := int(int16(binary.LittleEndian.Uint16(header)))
+		index := int(header[2])
+		if index > 88 {
+			index = 88
+		}
+		samples[ch] = predictor
+		for i := 1; i < length; i++ {
+			// group of 8 samples of the channel.
+			group := (i - 1) / 8
+			offset := 4*channels + group*4*channels + 4*ch + (i-1)%8/2
+			nibble := block[offset] >> (4 * uint((i-1)%2)) & 0x0F
+			predictor, index = imaDecodeNibble(nibble, predictor, index)
+			samples[i*channels+ch] = predictor
+		}
+	}
+	return length
+}
+
+// imaDecodeNibble returns the sample and step index after the nibble.
+func imaDecodeNibble(nibble byte, predictor, index int) (int, int) {
+	step := imaStepTable[index]
+	diff := step >> 3
+	if nibble&1 != 0 {
+		diff += step >> 2
+	}
+	if nibble&2 != 0 {
+		diff += step >> 1
+	}
+	if nibble&4 != 0 {
+		diff += step
+	}
+	if nibble&8 != 0 {
+		predictor -= diff
+	} else {
+		predictor += diff
+	}
+	if predictor > 32767 {
+		predictor = 32767
+	} else if predictor < -32768 {
+		predictor = -32768
+	}
+	index += imaIndexTable[nibble]
+	if index < 0 {
+		index = 0
+	} else if index > 88 {
+		index = 88
+	}
+	return predictor, index
+}
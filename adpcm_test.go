@@ -0,0 +1,117 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// imaFmtPayload returns payload of IMA ADPCM fmt chunk.
+func imaFmtPayload(channels, blockAlign uint16, sampleRate uint32) []byte {
+	p := fmtPayload(channels, 4, blockAlign, sampleRate)
+	binary.LittleEndian.PutUint16(p[0:], 0x11)
+	samplesPerBlock := (blockAlign-4*channels)*2/channels + 1
+	return append(p, 2, 0, byte(samplesPerBlock), byte(samplesPerBlock>>8))
+}
+
+func TestIMAADPCM(t *testing.T) {
+	block := []byte{
+		// predictor 1000, step index 0.
+		0xE8, 0x03, 0, 0,
+		0x77, 0x00, 0x08, 0x00,
+	}
+	// last block has header only.
+	last := []byte{0x18, 0xFC, 0, 0}
+	data := riff(
+		chunk("fmt ", imaFmtPayload(1, 8, 8000)),
+		chunk("fact", []byte{10, 0, 0, 0}),
+		chunk("data", append(block, last...)),
+	)
+
+	r, err := wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedFormat := wav.Format{SampleRate: 8000, Channels: 1, BitDepth: signal.BitDepth16}
+	if format := r.Format(); format != expectedFormat {
+		t.Errorf("expected format %+v got %+v", expectedFormat, format)
+	}
+	buf := signal.Allocator{Channels: 1, Length: 4, Capacity: 4}.Float64()
+	var result []int
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			v := buf.Sample(i)
+			if v > 0 {
+				result = append(result, int(math.Round(v*32767)))
+			} else {
+				result = append(result, int(math.Round(v*32768)))
+			}
+		}
+	}
+	expected := []int{1000, 1011, 1041, 1045, 1048, 1045, 1048, 1050, 1052, -1000}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected %v got %v", expected, result)
+	}
+
+	// fact chunk declares partial last block.
+	partial := riff(
+		chunk("fmt ", imaFmtPayload(1, 8, 8000)),
+		chunk("fact", []byte{12, 0, 0, 0}),
+		chunk("data", append(block, block...)),
+	)
+	r, err = wav.NewReader(bytes.NewReader(partial))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if samples := readAll(t, r); len(samples) != 12 || math.Round(samples[11]*32767) != 1041 {
+		t.Errorf("expected 12 samples bounded by fact chunk got %v", samples)
+	}
+
+	// stereo block has 4 bytes of every channel after headers.
+	stereo := riff(
+		chunk("fmt ", imaFmtPayload(2, 16, 8000)),
+		chunk("data", []byte{
+			0xE8, 0x03, 0, 0,
+			0, 0, 0, 0,
+			0x77, 0x00, 0x08, 0x00,
+			0, 0, 0, 0,
+		}),
+	)
+	r, err = wav.NewReader(bytes.NewReader(stereo))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf = signal.Allocator{Channels: 2, Length: 16, Capacity: 16}.Float64()
+	n, err := r.Read(buf)
+	if err != nil || n != 9 {
+		t.Fatalf("expected 9 frames got %d: %v", n, err)
+	}
+	for i := 0; i < n; i++ {
+		if left, right := int(math.Round(buf.Sample(2*i)*32767)), buf.Sample(2*i+1); left != expected[i] || right != 0 {
+			t.Errorf("frame %d: expected [%d 0] got [%d %v]", i, expected[i], left, right)
+		}
+	}
+
+	// block align doesn't fit channel headers.
+	invalid := riff(
+		chunk("fmt ", imaFmtPayload(2, 8, 8000)),
+		chunk("data", block),
+	)
+	if _, err := wav.NewReader(bytes.NewReader(invalid)); err == nil {
+		t.Errorf("expected error for invalid block align")
+	}
+}
@@ -0,0 +1,147 @@
+package wav
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ixmlID is the identifier of iXML chunk.
+var ixmlID = [4]byte{'i', 'X', 'M', 'L'}
+
+// ambisonicBFormat is the prefix of extensible sub-format GUID of
+// ambisonic B-format, the rest of GUID is the same for PCM and float.
+var ambisonicBFormat = []byte{0x21, 0x07, 0xD3, 0x11, 0x86, 0x44, 0xC8, 0xC1, 0xCA, 0x00, 0x00, 0x00}
+
+// AmbisonicOrdering is the convention of ambisonic channel order.
+type AmbisonicOrdering int
+
+const (
+	// OrderingUnknown means the stream doesn't declare the ordering.
+	OrderingUnknown AmbisonicOrdering = iota
+	// OrderingACN is Ambisonic Channel Number ordering.
+	OrderingACN
+	// OrderingFuMa is Furse-Malham ordering.
+	OrderingFuMa
+)
+
+// String returns the name of the ordering.
+func (o AmbisonicOrdering) String() string {
+	switch o {
+	case OrderingACN:
+		return "ACN"
+	case OrderingFuMa:
+		return "FuMa"
+	}
+	return "unknown"
+}
+
+// AmbisonicNormalization is the convention of ambisonic channel
+// normalization.
+type AmbisonicNormalization int
+
+const (
+	// NormalizationUnknown means the stream doesn't declare the
+	// normalization.
+	NormalizationUnknown AmbisonicNormalization = iota
+	// NormalizationSN3D is Schmidt semi-normalization.
+	NormalizationSN3D
+	// NormalizationN3D is full 3D normalization.
+	NormalizationN3D
+	// NormalizationFuMa is Furse-Malham normalization.
+	NormalizationFuMa
+)
+
+// String returns the name of the normalization.
+func (n AmbisonicNormalization) String() string {
+	switch n {
+	case NormalizationSN3D:
+		return "SN3D"
+	case NormalizationN3D:
+		return "N3D"
+	case NormalizationFuMa:
+		return "FuMa"
+	}
+	return "unknown"
+}
+
+// Ambisonics describes how ambisonic channels of the stream are
+// interpreted.
+type Ambisonics struct {
+	Ordering      AmbisonicOrdering
+	Normalization AmbisonicNormalization
+}
+
+// ReadAmbisonics returns ambisonic conventions declared by the stream.
+// The conventions are recognized in:
+//   - extensible format with ambisonic B-format sub-type, which implies
+//     FuMa ordering and normalization.
+//   - iXML chunk and bext description with "ACN", "FuMa", "SN3D", "N3D"
+//     words or "AmbiX", which implies ACN ordering and SN3D
+//     normalization.
+//
+// Explicit words take precedence over implied conventions. Unknown values
+// are returned if the stream doesn't declare the conventions. The stream
+// is rewinded to the start afterwards.
+func ReadAmbisonics(rs io.ReadSeeker) (Ambisonics, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return Ambisonics{}, err
+	}
+	var a, implied Ambisonics
+	if f, err := readFormat(rs, c); err == nil && f.ambisonicBFormat() {
+		implied = Ambisonics{Ordering: OrderingFuMa, Normalization: NormalizationFuMa}
+	}
+	for _, id := range [][4]byte{ixmlID, bextID} {
+		h, ok := c.find(id)
+		if !ok {
+			continue
+		}
+		payload, err := readPayload(rs, h)
+		if err != nil {
+			return Ambisonics{}, err
+		}
+		// only description of bext chunk is scanned.
+		if id == bextID && len(payload) > 256 {
+			payload = payload[:256]
+		}
+		a.scan(payload, &implied)
+	}
+	if a.Ordering == OrderingUnknown {
+		a.Ordering = implied.Ordering
+	}
+	if a.Normalization == NormalizationUnknown {
+		a.Normalization = implied.Normalization
+	}
+	return a, nil
+}
+
+// ambisonicBFormat returns true if extensible format has ambisonic
+// B-format sub-type.
+func (f format) ambisonicBFormat() bool {
+	// sub-format GUID follows valid bits and channel mask.
+	return f.extended() && bytes.Equal(f.Extension[10:22], ambisonicBFormat)
+}
+
+// scan looks for the words of ambisonic conventions in the text.
+func (a *Ambisonics) scan(text []byte, implied *Ambisonics) {
+	words := strings.FieldsFunc(strings.ToUpper(string(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		switch w {
+		case "ACN":
+			a.Ordering = OrderingACN
+		case "FUMA":
+			a.Ordering = OrderingFuMa
+			implied.Normalization = NormalizationFuMa
+		case "SN3D":
+			a.Normalization = NormalizationSN3D
+		case "N3D":
+			a.Normalization = NormalizationN3D
+		case "AMBIX":
+			*implied = Ambisonics{Ordering: OrderingACN, Normalization: NormalizationSN3D}
+		}
+	}
+}
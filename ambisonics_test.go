@@ -0,0 +1,65 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestReadAmbisonics(t *testing.T) {
+	pcm := make([]byte, 16)
+	bFormat := make([]byte, 40)
+	copy(bFormat, fmtPayload(4, 16, 8, 48000))
+	binary.LittleEndian.PutUint16(bFormat[0:], 0xFFFE)
+	binary.LittleEndian.PutUint16(bFormat[16:], 22)
+	binary.LittleEndian.PutUint16(bFormat[18:], 16)
+	copy(bFormat[24:], []byte{0x01, 0x00, 0x00, 0x00, 0x21, 0x07, 0xD3, 0x11, 0x86, 0x44, 0xC8, 0xC1, 0xCA, 0x00, 0x00, 0x00})
+	bext := make([]byte, 602)
+	copy(bext, "AmbiX first order recording")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected wav.Ambisonics
+	}{
+		{
+			name:     "none",
+			data:     riff(chunk("fmt ", fmtPayload(4, 16, 8, 48000)), chunk("data", pcm)),
+			expected: wav.Ambisonics{},
+		},
+		{
+			name:     "b-format",
+			data:     riff(chunk("fmt ", bFormat), chunk("data", pcm)),
+			expected: wav.Ambisonics{Ordering: wav.OrderingFuMa, Normalization: wav.NormalizationFuMa},
+		},
+		{
+			name:     "b-format with iXML",
+			data:     riff(chunk("fmt ", bFormat), chunk("iXML", []byte("<AMBISONICS>ACN/N3D</AMBISONICS>")), chunk("data", pcm)),
+			expected: wav.Ambisonics{Ordering: wav.OrderingACN, Normalization: wav.NormalizationN3D},
+		},
+		{
+			name:     "iXML",
+			data:     riff(chunk("fmt ", fmtPayload(4, 16, 8, 48000)), chunk("iXML", []byte("<NOTE>ordering: acn, norm: sn3d</NOTE>")), chunk("data", pcm)),
+			expected: wav.Ambisonics{Ordering: wav.OrderingACN, Normalization: wav.NormalizationSN3D},
+		},
+		{
+			name:     "bext",
+			data:     riff(chunk("fmt ", fmtPayload(4, 16, 8, 48000)), chunk("bext", bext), chunk("data", pcm)),
+			expected: wav.Ambisonics{Ordering: wav.OrderingACN, Normalization: wav.NormalizationSN3D},
+		},
+	}
+	for _, test := range tests {
+		result, err := wav.ReadAmbisonics(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if result != test.expected {
+			t.Errorf("%s: expected %v/%v got %v/%v", test.name, test.expected.Ordering, test.expected.Normalization, result.Ordering, result.Normalization)
+		}
+	}
+	if s := (wav.Ambisonics{}).Ordering.String(); s != "unknown" {
+		t.Errorf("expected unknown got %s", s)
+	}
+}
@@ -0,0 +1,149 @@
+package wav
+
+import (
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// Default thresholds of Analysis.
+const (
+	defaultDCThreshold        = 0.01
+	defaultInversionThreshold = -0.9
+)
+
+// Analysis collects the statistics of samples decoded by Source to detect
+// channels with DC offset and channel pairs with inverted polarity. Zero
+// value is ready to use. The results must be read after the pipe is done.
+type Analysis struct {
+	// DCThreshold is the absolute mean value above which the channel is
+	// reported to have DC offset. Default is 0.01.
+	DCThreshold float64
+	// InversionThreshold is the correlation below which the pair of
+	// channels is reported as inverted. Default is -0.9.
+	InversionThreshold float64
+
+	frames int
+	sum    []float64
+	sumSq  []float64
+	// sums of products of every pair of channels.
+	sumProducts []float64
+}
+
+// ChannelAnalysis is the result of Analysis for a single channel.
+type ChannelAnalysis struct {
+	Channel int
+	// DC is the mean value of the channel.
+	DC float64
+	// DCOffset is true if DC exceeds the threshold.
+	DCOffset bool
+}
+
+// PairAnalysis is the result of Analysis for a pair of channels.
+type PairAnalysis struct {
+	A, B int
+	// Correlation is the Pearson correlation of the channels. It's zero if
+	// any of the channels is constant.
+	Correlation float64
+	// Inverted is true if the correlation is below the threshold.
+	Inverted bool
+}
+
+// WithAnalysis makes Source collect the statistics of decoded samples into
+// provided Analysis. The analysis is reset when Source is allocated.
+func WithAnalysis(a *Analysis) Option {
+	return func(o *options) {
+		o.analysis = a
+	}
+}
+
+// reset prepares the analysis for provided number of channels.
+func (a *Analysis) reset(channels int) {
+	a.frames = 0
+	a.sum = make([]float64, channels)
+	a.sumSq = make([]float64, channels)
+	a.sumProducts = make([]float64, channels*(channels-1)/2)
+}
+
+// update accumulates provided number of frames.
+func (a *Analysis) update(floats signal.Floating, frames int) {
+	channels := len(a.sum)
+	for i := 0; i < frames; i++ {
+		pair := 0
+		for c := 0; c < channels; c++ {
+			v := floats.Sample(i*channels + c)
+			a.sum[c] += v
+			a.sumSq[c] += v * v
+			for d := c + 1; d < channels; d++ {
+				a.sumProducts[pair] += v * floats.Sample(i*channels+d)
+				pair++
+			}
+		}
+	}
+	a.frames += frames
+}
+
+// Channels returns the results of every channel.
+func (a *Analysis) Channels() []ChannelAnalysis {
+	threshold := a.DCThreshold
+	if threshold == 0 {
+		threshold = defaultDCThreshold
+	}
+	results := make([]ChannelAnalysis, len(a.sum))
+	for c := range results {
+		dc := a.mean(c)
+		results[c] = ChannelAnalysis{
+			Channel:  c,
+			DC:       dc,
+			DCOffset: math.Abs(dc) > threshold,
+		}
+	}
+	return results
+}
+
+// Pairs returns the results of every pair of channels.
+func (a *Analysis) Pairs() []PairAnalysis {
+	threshold := a.InversionThreshold
+	if threshold == 0 {
+		threshold = defaultInversionThreshold
+	}
+	channels := len(a.sum)
+	results := make([]PairAnalysis, 0, len(a.sumProducts))
+	pair := 0
+	for c := 0; c < channels; c++ {
+		for d := c + 1; d < channels; d++ {
+			correlation := a.correlation(c, d, pair)
+			results = append(results, PairAnalysis{
+				A:           c,
+				B:           d,
+				Correlation: correlation,
+				Inverted:    correlation < threshold,
+			})
+			pair++
+		}
+	}
+	return results
+}
+
+// mean returns the mean value of the channel.
+func (a *Analysis) mean(c int) float64 {
+	if a.frames == 0 {
+		return 0
+	}
+	return a.sum[c] / float64(a.frames)
+}
+
+// correlation returns the Pearson correlation of two channels.
+func (a *Analysis) correlation(c, d, pair int) float64 {
+	if a.frames == 0 {
+		return 0
+	}
+	n := float64(a.frames)
+	covariance := a.sumProducts[pair]/n - a.mean(c)*a.mean(d)
+	varianceC := a.sumSq[c]/n - a.mean(c)*a.mean(c)
+	varianceD := a.sumSq[d]/n - a.mean(d)*a.mean(d)
+	if varianceC <= 0 || varianceD <= 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceC*varianceD)
+}
@@ -0,0 +1,52 @@
+package wav_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestAnalysis(t *testing.T) {
+	// first two channels are inverted, third has DC offset.
+	var samples []float64
+	for i := 0; i < 1000; i++ {
+		v := 0.5 * math.Sin(2*math.Pi*float64(i)/100)
+		samples = append(samples, v, -v, 0.25+0.05*math.Cos(2*math.Pi*float64(i)/100))
+	}
+	var encoded buffer
+	transcode(t, floatSource(44100, 3, samples), wav.Sink(&encoded, signal.BitDepth16))
+
+	var a wav.Analysis
+	transcode(t, wav.Source(bytes.NewReader(encoded.data), wav.WithAnalysis(&a)), wav.Sink(&buffer{}, signal.BitDepth16))
+
+	channels := a.Channels()
+	if len(channels) != 3 {
+		t.Fatalf("expected 3 channels got %d", len(channels))
+	}
+	for i, expected := range []bool{false, false, true} {
+		if channels[i].DCOffset != expected {
+			t.Errorf("channel %d: expected DC offset %v got %v (%f)", i, expected, channels[i].DCOffset, channels[i].DC)
+		}
+	}
+	if dc := channels[2].DC; math.Abs(dc-0.25) > 0.001 {
+		t.Errorf("expected DC 0.25 got %f", dc)
+	}
+
+	pairs := a.Pairs()
+	if len(pairs) != 3 {
+		t.Fatalf("expected 3 pairs got %d", len(pairs))
+	}
+	for _, p := range pairs {
+		inverted := p.A == 0 && p.B == 1
+		if p.Inverted != inverted {
+			t.Errorf("pair %d-%d: expected inverted %v got %v (%f)", p.A, p.B, inverted, p.Inverted, p.Correlation)
+		}
+	}
+	if c := pairs[0].Correlation; c > -0.999 {
+		t.Errorf("expected correlation -1 got %f", c)
+	}
+}
@@ -0,0 +1,190 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// bextID is the identifier of broadcast extension chunk.
+var bextID = [4]byte{'b', 'e', 'x', 't'}
+
+// bextFixedSize is the size of bext chunk without coding history.
+const bextFixedSize = 602
+
+// loudnessNotSet is the value of loudness field that wasn't measured.
+const loudnessNotSet = 0x7FFF
+
+// Bext is the broadcast extension chunk of Broadcast Wave Format file, as
+// defined by EBU Tech 3285.
+type Bext struct {
+	Description         string
+	Originator          string
+	OriginatorReference string
+	// OriginationDate in "yyyy-mm-dd" format.
+	OriginationDate string
+	// OriginationTime in "hh:mm:ss" format.
+	OriginationTime string
+	// TimeReference is the first sample count since midnight.
+	TimeReference uint64
+	// UMID is the unique material identifier as defined by SMPTE 330M:
+	// 32 bytes of basic UMID or 64 bytes of extended UMID. Basic UMID is
+	// padded with zeros. Nil if not available.
+	UMID []byte
+	// Version of the chunk. When loudness is written, version 2 is used,
+	// when UMID is written, at least version 1 is used.
+	Version uint16
+	// Loudness metadata of version 2, nil if not available.
+	Loudness      *Loudness
+	CodingHistory string
+}
+
+// Loudness is the loudness metadata of bext chunk version 2. NaN values
+// are stored as not available.
+type Loudness struct {
+	// Integrated loudness in LUFS.
+	Integrated float64
+	// Range of loudness in LU.
+	Range float64
+	// MaxTruePeak level in dBTP.
+	MaxTruePeak float64
+	// MaxMomentary loudness in LUFS.
+	MaxMomentary float64
+	// MaxShortTerm loudness in LUFS.
+	MaxShortTerm float64
+}
+
+// WithBext makes Sink write the bext chunk before data. Coding history is
+// written with the first buffer, all other fields are written again on
+// flush. This allows to set the values that are known only after all
+// buffers are processed, for example measured loudness. Such updates must
+// be synchronized with the sink, e.g. done in the same mutable line.
+func WithBext(b *Bext) Option {
+	return func(o *options) {
+		o.bext = b
+	}
+}
+
+// ReadBext returns the bext chunk of the stream. Nil is returned if the
+// stream doesn't have bext chunk. The stream is rewinded to the start
+// afterwards.
+func ReadBext(rs io.ReadSeeker) (*Bext, error) {
+	payload, ok, err := ReadChunk(rs, bextID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return decodeBext(payload)
+}
+
+func decodeBext(p []byte) (*Bext, error) {
+	if len(p) < bextFixedSize {
+		return nil, fmt.Errorf("bext chunk is too short: %d bytes", len(p))
+	}
+	b := Bext{
+		Description:         bextString(p[0:256]),
+		Originator:          bextString(p[256:288]),
+		OriginatorReference: bextString(p[288:320]),
+		OriginationDate:     bextString(p[320:330]),
+		OriginationTime:     bextString(p[330:338]),
+		TimeReference:       binary.LittleEndian.Uint64(p[338:346]),
+		Version:             binary.LittleEndian.Uint16(p[346:348]),
+		CodingHistory:       bextString(p[bextFixedSize:]),
+		UMID:                bextUMID(p[348:412]),
+	}
+	if b.Version >= 2 {
+		b.Loudness = &Loudness{
+			Integrated:   loudnessValue(p[412:]),
+			Range:        loudnessValue(p[414:]),
+			MaxTruePeak:  loudnessValue(p[416:]),
+			MaxMomentary: loudnessValue(p[418:]),
+			MaxShortTerm: loudnessValue(p[420:]),
+		}
+	}
+	return &b, nil
+}
+
+// encode returns the payload of bext chunk.
+func (b *Bext) encode() []byte {
+	p := make([]byte, bextFixedSize, bextFixedSize+len(b.CodingHistory))
+	copy(p[0:256], b.Description)
+	copy(p[256:288], b.Originator)
+	copy(p[288:320], b.OriginatorReference)
+	copy(p[320:330], b.OriginationDate)
+	copy(p[330:338], b.OriginationTime)
+	binary.LittleEndian.PutUint64(p[338:], b.TimeReference)
+	version := b.Version
+	if b.UMID != nil {
+		if version < 1 {
+			version = 1
+		}
+		copy(p[348:412], b.UMID)
+	}
+	if b.Loudness != nil {
+		version = 2
+		putLoudness(p[412:], b.Loudness.Integrated)
+		putLoudness(p[414:], b.Loudness.Range)
+		putLoudness(p[416:], b.Loudness.MaxTruePeak)
+		putLoudness(p[418:], b.Loudness.MaxMomentary)
+		putLoudness(p[420:], b.Loudness.MaxShortTerm)
+	}
+	binary.LittleEndian.PutUint16(p[346:], version)
+	return append(p, b.CodingHistory...)
+}
+
+// validate returns error if the fields can't be encoded.
+func (b *Bext) validate() error {
+	if l := len(b.UMID); l != 0 && l != 32 && l != 64 {
+		return fmt.Errorf("invalid UMID length: %d bytes", l)
+	}
+	return nil
+}
+
+// bextUMID returns UMID field value. Nil is returned if it's not set and
+// basic UMID is returned if extended part is empty.
+func bextUMID(b []byte) []byte {
+	if isZero(b) {
+		return nil
+	}
+	if isZero(b[32:]) {
+		b = b[:32]
+	}
+	return append([]byte(nil), b...)
+}
+
+// isZero returns true if all bytes are zero.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bextString returns ASCII field value without trailing zeros.
+func bextString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// putLoudness stores the value in hundredths of unit.
+func putLoudness(b []byte, v float64) {
+	stored := int16(loudnessNotSet)
+	if !math.IsNaN(v) {
+		stored = int16(math.Round(v * 100))
+	}
+	binary.LittleEndian.PutUint16(b, uint16(stored))
+}
+
+// loudnessValue returns the value stored in hundredths of unit.
+func loudnessValue(b []byte) float64 {
+	stored := int16(binary.LittleEndian.Uint16(b))
+	if stored == loudnessNotSet {
+		return math.NaN()
+	}
+	return float64(stored) / 100
+}
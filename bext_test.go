@@ -0,0 +1,122 @@
+package wav_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestBext(t *testing.T) {
+	bext := wav.Bext{
+		Description:     "test description",
+		Originator:      "pipelined",
+		OriginationDate: "2020-12-31",
+		OriginationTime: "23:59:59",
+		TimeReference:   48000,
+		CodingHistory:   "A=PCM,F=48000,W=16,M=mono,T=test\r\n",
+	}
+	loudness := wav.Loudness{
+		Integrated:   -23,
+		Range:        5.5,
+		MaxTruePeak:  -1.01,
+		MaxMomentary: math.NaN(),
+		MaxShortTerm: -20,
+	}
+	source := floatSource(48000, 1, []float64{0, 0.5, 1})
+	// loudness is set when source is done.
+	measuring := func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+		s, err := source(mctx, bufferSize)
+		sourceFn := s.SourceFunc
+		s.SourceFunc = func(out signal.Floating) (int, error) {
+			n, err := sourceFn(out)
+			if err != nil {
+				bext.Loudness = &loudness
+			}
+			return n, err
+		}
+		return s, err
+	}
+
+	var out buffer
+	// mutable line executes components in the same goroutine.
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Context: mutable.Mutable(),
+		Source:  measuring,
+		Sink:    wav.Sink(&out, signal.BitDepth16, wav.WithBext(&bext)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected pipe error: %v", err)
+	}
+	if err := pipe.Wait(p.Start(context.Background())); err != nil {
+		t.Fatalf("unexpected run error: %v", err)
+	}
+	if ids := chunkIDs(out.data); !reflect.DeepEqual([]string{"fmt ", "bext", "data"}, ids) {
+		t.Fatalf("unexpected chunks: %v", ids)
+	}
+
+	result, err := wav.ReadBext(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Version != 2 {
+		t.Errorf("expected version 2, got %d", result.Version)
+	}
+	if !math.IsNaN(result.Loudness.MaxMomentary) {
+		t.Errorf("expected NaN momentary loudness, got %v", result.Loudness.MaxMomentary)
+	}
+	result.Loudness.MaxMomentary = 0
+	loudness.MaxMomentary = 0
+	result.Version = 0
+	if !reflect.DeepEqual(&bext, result) {
+		t.Errorf("expected:\n%+v\ngot:\n%+v", bext, *result)
+	}
+
+	none, err := wav.ReadBext(bytes.NewReader(riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)))))
+	if err != nil || none != nil {
+		t.Errorf("expected no bext, got: %v %v", none, err)
+	}
+}
+
+func TestBextUMID(t *testing.T) {
+	basic := make([]byte, 32)
+	extended := make([]byte, 64)
+	for i := range extended {
+		extended[i] = byte(i + 1)
+	}
+	copy(basic, extended)
+	for _, umid := range [][]byte{basic, extended} {
+		var out buffer
+		bext := wav.Bext{UMID: umid}
+		transcode(t, floatSource(48000, 1, []float64{0, 0.5}), wav.Sink(&out, signal.BitDepth16, wav.WithBext(&bext)))
+		// UMID is located at offset 348 of bext payload.
+		if stored := out.data[36+8+348 : 36+8+348+len(umid)]; !bytes.Equal(umid, stored) {
+			t.Errorf("expected stored UMID %v got %v", umid, stored)
+		}
+		result, err := wav.ReadBext(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(umid, result.UMID) {
+			t.Errorf("expected UMID %v got %v", umid, result.UMID)
+		}
+		if result.Version != 1 {
+			t.Errorf("expected version 1 got %d", result.Version)
+		}
+	}
+
+	_, err := pipe.New(bufferSize, pipe.Line{
+		Source: floatSource(48000, 1, []float64{0}),
+		Sink:   wav.Sink(&buffer{}, signal.BitDepth16, wav.WithBext(&wav.Bext{UMID: make([]byte, 40)})),
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid UMID length")
+	}
+}
@@ -0,0 +1,100 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// ErrBudgetExceeded is returned by SinkBudget when the stream doesn't fit
+// the size budget even with the lowest bit depth.
+var ErrBudgetExceeded = errors.New("size budget exceeded")
+
+// budgetBitDepths are the bit depths that SinkBudget tries in order.
+var budgetBitDepths = []signal.BitDepth{
+	signal.BitDepth24,
+	signal.BitDepth16,
+	signal.BitDepth8,
+}
+
+// SinkBudget writes wav data to WriteSeeker as Sink does, but picks the
+// highest bit depth of 24, 16 and 8 bits that fits the stream of provided
+// duration in maxBytes. The size includes the header and all chunks
+// written by Sink. The size of wave list written with WithSilenceChunks
+// is estimated for the worst case, so the budget holds for any samples.
+// ErrBudgetExceeded is returned at allocation if 8-bit stream doesn't fit.
+func SinkBudget(ws io.WriteSeeker, maxBytes, durationFrames int64, options ...Option) pipe.SinkAllocatorFunc {
+	opts := newOptions(options)
+	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
+		for _, bd := range budgetBitDepths {
+			f := Format{
+				SampleRate: props.SampleRate,
+				Channels:   props.Channels,
+				BitDepth:   bd,
+			}
+			if opts.streamSize(f, durationFrames) <= maxBytes {
+				return Sink(ws, bd, options...)(mctx, bufferSize, props)
+			}
+		}
+		return pipe.Sink{}, fmt.Errorf("%w: %d frames don't fit %d bytes", ErrBudgetExceeded, durationFrames, maxBytes)
+	}
+}
+
+// streamSize returns the size in bytes of the stream that Sink writes
+// with provided number of frames.
+func (o *options) streamSize(f Format, frames int64) int64 {
+	// invalid padding is reported by Sink.
+	if padded, err := o.paddedFormat(f); err == nil {
+		f = padded
+	}
+	if o.resample != 0 && f.SampleRate != 0 {
+		// frames are written as resampler outputs them.
+		frames = (frames*int64(o.resample) + int64(f.SampleRate) - 1) / int64(f.SampleRate)
+		f.SampleRate = o.resample
+	}
+	if o.exactLength != nil {
+		frames = int64(*o.exactLength)
+	} else {
+		frames += int64(f.SampleRate.Events(o.preroll))
+	}
+	dataSize := frames * int64(o.blockAlign(f))
+	// RIFF header, fmt chunk and header of data chunk.
+	size := 12 + 8 + int64(len(o.formatPayload(f))) + 8 + dataSize + dataSize%2
+	if o.silenceChunks > 0 {
+		size += o.waveListOverhead(frames, int64(o.blockAlign(f)))
+	}
+	chunks := append(o.leadingChunks(f), o.trailingChunks()...)
+	if o.reserveRF64 {
+		chunks = append(chunks, rawChunk{ID: junkID, Payload: make([]byte, ds64Size)})
+	}
+	if o.bext != nil {
+		chunks = append(chunks, rawChunk{ID: bextID, Payload: o.bext.encode()})
+	}
+	if o.checksum {
+		chunks = append(chunks, rawChunk{ID: checksumID, Payload: make([]byte, checksumSize)})
+	}
+	for _, c := range chunks {
+		size += 8 + int64(len(c.Payload)) + int64(len(c.Payload)%2)
+	}
+	return size
+}
+
+// waveListOverhead returns the maximum number of bytes that wave list adds
+// to the stream of provided frames compared to a single data chunk.
+func (o *options) waveListOverhead(frames, frameSize int64) int64 {
+	// header and form type of LIST chunk.
+	overhead := int64(12)
+	// every slnt chunk replaces minFrames silent frames, but adds its own
+	// chunk and the header and padding of the next data chunk.
+	minFrames := int64(o.silenceChunks)
+	if growth := 12 + 8 + 1 - minFrames*frameSize; growth > 0 {
+		// slnt chunks are separated by at least one frame of data, the
+		// first data chunk might be padded too.
+		overhead += 1 + (frames+1)/(minFrames+1)*growth
+	}
+	return overhead
+}
@@ -0,0 +1,158 @@
+package wav_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestSinkBudget(t *testing.T) {
+	const frames = 1000
+	samples := make([]float64, frames)
+	tests := []struct {
+		maxBytes int64
+		expected signal.BitDepth
+	}{
+		{maxBytes: 44 + 4*frames, expected: signal.BitDepth24},
+		{maxBytes: 44 + 3*frames, expected: signal.BitDepth24},
+		{maxBytes: 44 + 3*frames - 1, expected: signal.BitDepth16},
+		{maxBytes: 44 + frames, expected: signal.BitDepth8},
+	}
+	for _, test := range tests {
+		var out buffer
+		transcode(t, floatSource(44100, 1, samples), wav.SinkBudget(&out, test.maxBytes, frames))
+		info, err := wav.Probe(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.BitDepth != test.expected {
+			t.Errorf("%d bytes: expected %d bits got %d", test.maxBytes, test.expected, info.BitDepth)
+		}
+		if size := int64(len(out.data)); size > test.maxBytes {
+			t.Errorf("%d bytes: budget exceeded with %d bytes", test.maxBytes, size)
+		}
+	}
+
+	// chunks are included in the budget.
+	var out buffer
+	transcode(t, floatSource(44100, 1, samples), wav.SinkBudget(&out, 44+3*frames, frames, wav.WithChunk([4]byte{'t', 'e', 's', 't'}, []byte{1}, wav.AfterData)))
+	if info, _ := wav.Probe(bytes.NewReader(out.data)); info.BitDepth != signal.BitDepth16 {
+		t.Errorf("expected 16 bits with chunk got %d", info.BitDepth)
+	}
+
+	// checksum chunk is included in the budget.
+	out = buffer{}
+	transcode(t, floatSource(44100, 1, samples), wav.SinkBudget(&out, 44+3*frames+15, frames, wav.WithChecksum()))
+	if info, _ := wav.Probe(bytes.NewReader(out.data)); info.BitDepth != signal.BitDepth16 {
+		t.Errorf("expected 16 bits with checksum got %d", info.BitDepth)
+	}
+	out = buffer{}
+	transcode(t, floatSource(44100, 1, samples), wav.SinkBudget(&out, 44+3*frames+16, frames, wav.WithChecksum()))
+	if info, _ := wav.Probe(bytes.NewReader(out.data)); info.BitDepth != signal.BitDepth24 || len(out.data) != 44+3*frames+16 {
+		t.Errorf("expected 24 bits with checksum got %d bits and %d bytes", info.BitDepth, len(out.data))
+	}
+
+	// padded channels are included in the budget.
+	for _, test := range []struct {
+		maxBytes int64
+		expected signal.BitDepth
+	}{
+		{maxBytes: 44 + 2*3*frames, expected: signal.BitDepth24},
+		{maxBytes: 44 + 2*3*frames - 1, expected: signal.BitDepth16},
+	} {
+		out = buffer{}
+		transcode(t, floatSource(44100, 1, samples), wav.SinkBudget(&out, test.maxBytes, frames, wav.WithPaddedChannels(2)))
+		if info, _ := wav.Probe(bytes.NewReader(out.data)); info.BitDepth != test.expected || info.Channels != 2 {
+			t.Errorf("%d bytes: expected %d bits with padded channels got %d bits and %d channels", test.maxBytes, test.expected, info.BitDepth, info.Channels)
+		}
+		if size := int64(len(out.data)); size > test.maxBytes {
+			t.Errorf("%d bytes: budget exceeded with padded channels of %d bytes", test.maxBytes, size)
+		}
+	}
+
+	// resampled frames are included in the budget.
+	for _, test := range []struct {
+		maxBytes int64
+		expected signal.BitDepth
+	}{
+		{maxBytes: 44 + 2*3*frames, expected: signal.BitDepth24},
+		{maxBytes: 44 + 2*3*frames - 1, expected: signal.BitDepth16},
+	} {
+		out = buffer{}
+		transcode(t, floatSource(44100, 1, samples), wav.SinkBudget(&out, test.maxBytes, frames, wav.WithResample(88200)))
+		info, _ := wav.Probe(bytes.NewReader(out.data))
+		if info.BitDepth != test.expected || info.Frames != 2*frames {
+			t.Errorf("%d bytes: expected %d bits with resampling got %d bits and %d frames", test.maxBytes, test.expected, info.BitDepth, info.Frames)
+		}
+		if size := int64(len(out.data)); size > test.maxBytes {
+			t.Errorf("%d bytes: budget exceeded with resampling of %d bytes", test.maxBytes, size)
+		}
+	}
+
+	// extensible fmt chunk is included in the budget.
+	extensible := wav.WithExtensible(&wav.Extensible{ChannelMask: 4, SubFormat: wav.SubFormatPCM})
+	for _, test := range []struct {
+		maxBytes int64
+		expected signal.BitDepth
+	}{
+		{maxBytes: 68 + 3*frames, expected: signal.BitDepth24},
+		{maxBytes: 68 + 3*frames - 1, expected: signal.BitDepth16},
+	} {
+		out = buffer{}
+		transcode(t, floatSource(44100, 1, samples), wav.SinkBudget(&out, test.maxBytes, frames, extensible))
+		if info, _ := wav.Probe(bytes.NewReader(out.data)); info.BitDepth != test.expected {
+			t.Errorf("%d bytes: expected %d bits with extensible format got %d", test.maxBytes, test.expected, info.BitDepth)
+		}
+		if size := int64(len(out.data)); size > test.maxBytes {
+			t.Errorf("%d bytes: budget exceeded with extensible format of %d bytes", test.maxBytes, size)
+		}
+	}
+
+	// wave list is included in the budget, silence doesn't have to shrink
+	// the stream.
+	tone := make([]float64, frames)
+	alternating := make([]float64, frames)
+	for i := range tone {
+		tone[i] = 0.5
+		if i%2 == 0 {
+			alternating[i] = 0.5
+		}
+	}
+	for _, test := range []struct {
+		samples   []float64
+		minFrames int
+		maxBytes  int64
+	}{
+		{samples: tone, minFrames: 10, maxBytes: 44 + 3*frames},
+		{samples: tone, minFrames: 10, maxBytes: 56 + 3*frames},
+		{samples: alternating, minFrames: 1, maxBytes: 44 + 12*frames},
+	} {
+		out = buffer{}
+		transcode(t, floatSource(44100, 1, test.samples), wav.SinkBudget(&out, test.maxBytes, frames, wav.WithSilenceChunks(test.minFrames)))
+		if size := int64(len(out.data)); size > test.maxBytes {
+			t.Errorf("%d bytes: budget exceeded with silence chunks of %d bytes", test.maxBytes, size)
+		}
+	}
+	out = buffer{}
+	transcode(t, floatSource(44100, 1, tone), wav.SinkBudget(&out, 56+3*frames, frames, wav.WithSilenceChunks(10)))
+	if info, _ := wav.Probe(bytes.NewReader(out.data)); info.BitDepth != signal.BitDepth24 || len(out.data) != 56+3*frames {
+		t.Errorf("expected 24 bits with silence chunks got %d bits and %d bytes", info.BitDepth, len(out.data))
+	}
+
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Source: floatSource(44100, 1, samples),
+		Sink:   wav.SinkBudget(&buffer{}, 44+frames-1, frames),
+	})
+	if err == nil {
+		err = pipe.Wait(p.Start(context.Background()))
+	}
+	if !errors.Is(err, wav.ErrBudgetExceeded) {
+		t.Errorf("expected budget error got %v", err)
+	}
+}
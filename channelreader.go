@@ -0,0 +1,86 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// channelReaderBufferSize is the number of frames decoded at once by
+// ChannelReader.
+const channelReaderBufferSize = 1024
+
+// ChannelReader returns a reader of raw PCM samples of a single channel of
+// the stream. Samples are decoded and encoded again with provided bit
+// depth and options the same way as SourcePCM does.
+func ChannelReader(rs io.ReadSeeker, channel int, bitDepth signal.BitDepth, options ...Option) (io.Reader, error) {
+	r, err := NewReader(rs, options...)
+	if err != nil {
+		return nil, err
+	}
+	if channel < 0 || channel >= r.format.Channels {
+		return nil, fmt.Errorf("invalid channel %d of %d channels", channel, r.format.Channels)
+	}
+	cr := channelReader{
+		reader:  r,
+		channel: channel,
+		frames:  signal.Allocator{Channels: r.format.Channels, Length: channelReaderBufferSize, Capacity: channelReaderBufferSize}.Float64(),
+		samples: signal.Allocator{Channels: 1, Length: channelReaderBufferSize, Capacity: channelReaderBufferSize}.Float64(),
+	}
+	opts := newOptions(options).encodingOptions()
+	f := Format{
+		SampleRate: r.format.SampleRate,
+		Channels:   1,
+		BitDepth:   bitDepth,
+	}
+	if cr.encoder, err = opts.newStreamWriter(&cr.pending, f, channelReaderBufferSize); err != nil {
+		return nil, err
+	}
+	// only samples are written.
+	cr.encoder.header = nil
+	cr.encoder.bigEndian = opts.byteOrder == binary.BigEndian
+	return &cr, nil
+}
+
+// channelReader encodes samples of a single decoded channel.
+type channelReader struct {
+	reader  *Reader
+	channel int
+	frames  signal.Floating
+	samples signal.Floating
+	encoder *streamWriter
+	// encoded bytes that weren't read yet.
+	pending bytes.Buffer
+}
+
+// Read implements io.Reader.
+func (cr *channelReader) Read(p []byte) (int, error) {
+	for cr.pending.Len() == 0 {
+		n, err := cr.reader.Read(cr.frames)
+		if err != nil {
+			return 0, err
+		}
+		channels := cr.frames.Channels()
+		for i := 0; i < n; i++ {
+			cr.samples.SetSample(i, cr.frames.Sample(i*channels+cr.channel))
+		}
+		if err := cr.encoder.write(cr.samples.Slice(0, n)); err != nil {
+			return 0, err
+		}
+	}
+	return cr.pending.Read(p)
+}
+
+// encodingOptions returns the options that apply to encoding of raw PCM
+// samples. Other options are applied by Reader to decoded frames.
+func (o options) encodingOptions() options {
+	return options{
+		rounding:  o.rounding,
+		overflow:  o.overflow,
+		signed8:   o.signed8,
+		byteOrder: o.byteOrder,
+	}
+}
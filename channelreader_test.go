@@ -0,0 +1,77 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestChannelReader(t *testing.T) {
+	const frames = 3000
+	samples := make([]float64, 3*frames)
+	for i := 0; i < frames; i++ {
+		for c := 0; c < 3; c++ {
+			samples[3*i+c] = float64((c+1)*(i%100)) / 1000
+		}
+	}
+	var in buffer
+	transcode(t, floatSource(44100, 3, samples), wav.Sink(&in, signal.BitDepth16))
+
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24} {
+		// the same channel transcoded by Sink.
+		var expected buffer
+		transcode(t, wav.Source(bytes.NewReader(in.data), wav.WithChannels(1)), wav.Sink(&expected, bitDepth))
+
+		r, err := wav.ChannelReader(bytes.NewReader(in.data), 1, bitDepth)
+		if err != nil {
+			t.Fatalf("%d bits: unexpected error: %v", bitDepth, err)
+		}
+		result, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%d bits: unexpected read error: %v", bitDepth, err)
+		}
+		if pcm := expected.data[44:]; !bytes.Equal(pcm, result) {
+			t.Errorf("%d bits: got %d bytes that differ from %d bytes of Sink", bitDepth, len(result), len(pcm))
+		}
+	}
+
+	r, err := wav.ChannelReader(bytes.NewReader(in.data), 2, signal.BitDepth16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var first [4]byte
+	if _, err := r.Read(first[:2]); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if _, err := r.Read(first[2:]); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if v := int16(binary.LittleEndian.Uint16(first[2:])); v != int16Samples(in.data)[5] {
+		t.Errorf("expected second sample %d got %d", int16Samples(in.data)[5], v)
+	}
+
+	// options of decoding aren't applied to encoding.
+	var resampled buffer
+	transcode(t, wav.Source(bytes.NewReader(in.data), wav.WithChannels(1), wav.WithResample(22050), wav.WithHeadroom(-6), wav.WithPreroll(time.Millisecond)), wav.Sink(&resampled, signal.BitDepth16))
+	r, err = wav.ChannelReader(bytes.NewReader(in.data), 1, signal.BitDepth16, wav.WithResample(22050), wav.WithHeadroom(-6), wav.WithPreroll(time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error with decoding options: %v", err)
+	}
+	result, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if pcm := resampled.data[44:]; !bytes.Equal(pcm, result) {
+		t.Errorf("got %d resampled bytes that differ from %d bytes of Sink", len(result), len(pcm))
+	}
+
+	if _, err := wav.ChannelReader(bytes.NewReader(in.data), 3, signal.BitDepth16); err == nil {
+		t.Errorf("expected error for invalid channel")
+	}
+}
@@ -0,0 +1,149 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/audio"
+)
+
+// WithChannels makes Source and Reader decode only provided channels of
+// linear PCM stream, in provided order. Bytes of other channels are read,
+// but not converted, so it's cheaper than decoding all channels and
+// dropping some of them. Compressed formats and mid/side streams can't be
+// filtered.
+func WithChannels(channels ...int) Option {
+	return func(o *options) {
+		o.channels = channels
+	}
+}
+
+// channelFilter reads samples of selected channels from the data chunk.
+type channelFilter struct {
+	rs        io.ReadSeeker
+	remaining int64
+	// sampleSize and frameSize are in bytes.
+	sampleSize int
+	frameSize  int
+	channels   []int
+	buf        []byte
+	// justification of 24-bit samples in 4-byte containers, nil if
+	// samples are packed in 3 bytes.
+	container24 *Justification
+}
+
+// filterChannels makes reader decode only provided channels.
+func (r *Reader) filterChannels(channels []int) error {
+	if r.codec != nil {
+		return errors.New("channels of compressed stream can't be filtered")
+	}
+	if r.float != nil {
+		return errors.New("channels of float stream can't be filtered")
+	}
+	if r.midSide {
+		return errors.New("channels of mid/side stream can't be filtered")
+	}
+	for _, c := range channels {
+		if c < 0 || c >= r.format.Channels {
+			return fmt.Errorf("invalid channel %d of %d channels", c, r.format.Channels)
+		}
+	}
+	c, err := readContainer(r.rs)
+	if err != nil {
+		return err
+	}
+	h, ok := c.find(dataID)
+	if !ok {
+		return ErrInvalidWav
+	}
+	if _, err := r.rs.Seek(h.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking data: %w", err)
+	}
+	remaining := int64(h.Size)
+	if available := c.Size - h.Offset; remaining > available {
+		remaining = available
+	}
+	sampleSize := (int(r.format.BitDepth) + 7) / 8
+	if r.container24 != nil {
+		sampleSize = containerSize
+	}
+	r.filter = &channelFilter{
+		rs:          r.rs,
+		remaining:   remaining,
+		sampleSize:  sampleSize,
+		frameSize:   sampleSize * r.format.Channels,
+		channels:    channels,
+		container24: r.container24,
+	}
+	r.format.Channels = len(channels)
+	r.pcm.Format = &audio.Format{
+		NumChannels: len(channels),
+		SampleRate:  int(r.format.SampleRate),
+	}
+	return nil
+}
+
+// allChannels returns the indices of provided number of channels.
+func allChannels(n int) []int {
+	channels := make([]int, n)
+	for i := range channels {
+		channels[i] = i
+	}
+	return channels
+}
+
+// readPCM reads the samples of wav stream into provided buffer. It returns
+// the number of samples read.
+func (r *Reader) readPCM(pcm *audio.IntBuffer) (int, error) {
+	if r.filter != nil {
+		return r.filter.read(pcm.Data)
+	}
+	return r.decoder.PCMBuffer(pcm)
+}
+
+// read decodes the samples of selected channels of whole frames that fit
+// provided buffer. Samples have the same values as go-audio decoder
+// returns.
+func (f *channelFilter) read(samples []int) (int, error) {
+	frames := int64(len(samples) / len(f.channels))
+	if available := f.remaining / int64(f.frameSize); frames > available {
+		frames = available
+	}
+	size := int(frames) * f.frameSize
+	if cap(f.buf) < size {
+		f.buf = make([]byte, size)
+	}
+	f.buf = f.buf[:size]
+	if _, err := io.ReadFull(f.rs, f.buf); err != nil {
+		return 0, fmt.Errorf("error reading data: %w", err)
+	}
+	f.remaining -= int64(size)
+
+	n := 0
+	for frame := 0; frame < size; frame += f.frameSize {
+		for _, c := range f.channels {
+			samples[n] = decodeSample(f.buf[frame+c*f.sampleSize:], f.sampleSize)
+			if f.container24 != nil {
+				samples[n] = unpack(samples[n], *f.container24)
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// decodeSample returns the value of little-endian sample of provided size.
+// 8-bit samples are unsigned, others are signed.
+func decodeSample(b []byte, size int) int {
+	switch size {
+	case 1:
+		return int(b[0])
+	case 2:
+		return int(int16(binary.LittleEndian.Uint16(b)))
+	case 3:
+		return int(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8)
+	}
+	return int(int32(binary.LittleEndian.Uint32(b)))
+}
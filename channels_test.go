@@ -0,0 +1,85 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// readAll decodes all frames of reader.
+func readAll(t *testing.T, r *wav.Reader) []float64 {
+	t.Helper()
+	buf := signal.Allocator{Channels: r.Format().Channels, Length: 3, Capacity: 3}.Float64()
+	var samples []float64
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			return samples
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < n*buf.Channels(); i++ {
+			samples = append(samples, buf.Sample(i))
+		}
+	}
+}
+
+func TestWithChannels(t *testing.T) {
+	const channels, frames = 8, 7
+	for _, bitDepth := range []uint16{8, 16, 24, 32} {
+		sampleSize := int(bitDepth / 8)
+		pcm := make([]byte, channels*frames*sampleSize)
+		for i := range pcm {
+			pcm[i] = byte(i*37 + 11)
+		}
+		data := riff(
+			chunk("fmt ", fmtPayload(channels, bitDepth, uint16(channels*sampleSize), 48000)),
+			chunk("data", pcm),
+		)
+		r, err := wav.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		all := readAll(t, r)
+
+		for _, selected := range [][]int{{0}, {5, 2}, {7, 7}} {
+			r, err := wav.NewReader(bytes.NewReader(data), wav.WithChannels(selected...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c := r.Format().Channels; c != len(selected) {
+				t.Errorf("%d bits %v: expected %d channels got %d", bitDepth, selected, len(selected), c)
+			}
+			filtered := readAll(t, r)
+			if len(filtered) != frames*len(selected) {
+				t.Fatalf("%d bits %v: expected %d samples got %d", bitDepth, selected, frames*len(selected), len(filtered))
+			}
+			for i, v := range filtered {
+				frame, c := i/len(selected), selected[i%len(selected)]
+				if expected := all[frame*channels+c]; v != expected {
+					t.Errorf("%d bits %v: sample %d expected %v got %v", bitDepth, selected, i, expected, v)
+				}
+			}
+		}
+	}
+
+	data := riff(
+		chunk("fmt ", fmtPayload(2, 16, 4, 48000)),
+		chunk("data", make([]byte, 40)),
+	)
+	if _, err := wav.NewReader(bytes.NewReader(data), wav.WithChannels(2)); err == nil {
+		t.Errorf("expected error for invalid channel")
+	}
+	ima := riff(
+		chunk("fmt ", imaFmtPayload(2, 256, 22050)),
+		chunk("data", make([]byte, 256)),
+	)
+	if _, err := wav.NewReader(bytes.NewReader(ima), wav.WithChannels(0)); err == nil {
+		t.Errorf("expected error for compressed stream")
+	}
+}
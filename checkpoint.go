@@ -0,0 +1,69 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WithCheckpoints makes Sink update the sizes in the header every time
+// provided duration of frames is written. If writing stops unexpectedly,
+// e.g. the process crashes, the stream stays valid up to the last
+// checkpoint. Trailing chunks are written on flush only.
+func WithCheckpoints(d time.Duration) Option {
+	return func(o *options) {
+		o.checkpoints = d
+	}
+}
+
+// Checkpoint updates the sizes in the header to cover all frames written
+// so far and continues the encoding, so the stream is valid if writing
+// stops after it. Nothing is done if no frames were written.
+func (w *Writer) Checkpoint() error {
+	w.sinceCheckpoint = 0
+	return w.encoder.checkpoint()
+}
+
+// checkpoint updates the sizes if checkpoint interval is reached.
+func (w *Writer) checkpoint(frames int) error {
+	if w.checkpointInterval == 0 {
+		return nil
+	}
+	w.sinceCheckpoint += frames
+	if w.sinceCheckpoint < w.checkpointInterval {
+		return nil
+	}
+	return w.Checkpoint()
+}
+
+// checkpoint overwrites RIFF and data sizes with the current position.
+// go-audio encoder isn't used, because it counts overwritten bytes.
+func (e *encoder) checkpoint() error {
+	if !e.dataStarted {
+		return nil
+	}
+	riffSize := uint32(e.WrittenBytes - 8)
+	dataSize := uint32(int64(e.WrittenBytes) - e.dataOffset)
+	if err := e.overwrite(4, riffSize); err != nil {
+		return fmt.Errorf("error updating RIFF size: %w", err)
+	}
+	if err := e.overwrite(e.dataOffset-4, dataSize); err != nil {
+		return fmt.Errorf("error updating data size: %w", err)
+	}
+	if _, err := e.ws.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("error seeking stream end: %w", err)
+	}
+	return nil
+}
+
+// overwrite writes the size at provided offset.
+func (e *encoder) overwrite(offset int64, size uint32) error {
+	if _, err := e.ws.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], size)
+	_, err := e.ws.Write(b[:])
+	return err
+}
@@ -0,0 +1,77 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestCheckpoint(t *testing.T) {
+	format := wav.Format{SampleRate: 1000, Channels: 2, BitDepth: signal.BitDepth16}
+	buf := signal.Allocator{Channels: 2, Length: 100, Capacity: 100}.Float64()
+	for i := 0; i < buf.Len(); i++ {
+		buf.SetSample(i, 0.5)
+	}
+	// snapshot returns the number of frames in the copy of the stream.
+	snapshot := func(data []byte) int64 {
+		t.Helper()
+		info, err := wav.Probe(bytes.NewReader(append([]byte(nil), data...)))
+		if err != nil {
+			t.Fatalf("unexpected probe error: %v", err)
+		}
+		return info.Frames
+	}
+
+	var out buffer
+	w, err := wav.NewWriter(&out, format)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Checkpoint(); err != nil {
+		t.Fatalf("unexpected checkpoint error: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if _, err := w.Write(buf); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+		if err := w.Checkpoint(); err != nil {
+			t.Fatalf("unexpected checkpoint error: %v", err)
+		}
+		if frames := snapshot(out.data); frames != int64(i*100) {
+			t.Errorf("expected %d frames got %d", i*100, frames)
+		}
+	}
+	if _, err := w.Write(buf); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if frames := snapshot(out.data); frames != 400 {
+		t.Errorf("expected 400 frames got %d", frames)
+	}
+	if size := len(out.data); size != 44+400*4 {
+		t.Errorf("expected %d bytes got %d", 44+400*4, size)
+	}
+
+	// checkpoint every 250ms, which is 250 frames.
+	var periodic buffer
+	w, err = wav.NewWriter(&periodic, format, wav.WithCheckpoints(250*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// sizes are placeholders until the first checkpoint.
+	expected := []int64{-1, -1, 300, 300, 300, 600}
+	for i, e := range expected {
+		if _, err := w.Write(buf); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+		if frames := snapshot(periodic.data); e != -1 && frames != e {
+			t.Errorf("write %d: expected %d frames got %d", i, e, frames)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// checksumID is the identifier of the chunk with the checksum of data.
+//
+// The payload of the chunk has the size of data chunk payload and its
+// CRC-32 checksum with IEEE polynomial, both little-endian uint32. The pad
+// byte of data chunk isn't included.
+var checksumID = [4]byte{'c', 'k', 'r', 'c'}
+
+// checksumSize is the size of checksum chunk payload.
+const checksumSize = 8
+
+// ErrChecksumNotFound is returned by VerifyChecksum when the stream
+// doesn't have checksum chunk.
+var ErrChecksumNotFound = errors.New("checksum chunk not found")
+
+// WithChecksum makes Sink compute CRC-32 checksum of written samples and
+// write it into "ckrc" chunk after data when it's flushed. The chunk
+// payload is the size of data chunk payload and its checksum with IEEE
+// polynomial, both little-endian uint32. Use VerifyChecksum to check it.
+func WithChecksum() Option {
+	return func(o *options) {
+		o.checksum = true
+	}
+}
+
+// chunk returns the checksum chunk of encoded samples.
+func (v *verifier) chunk() rawChunk {
+	payload := make([]byte, checksumSize)
+	binary.LittleEndian.PutUint32(payload[0:], uint32(v.size))
+	binary.LittleEndian.PutUint32(payload[4:], v.checksum.Sum32())
+	return rawChunk{ID: checksumID, Payload: payload}
+}
+
+// VerifyChecksum recomputes the checksum of data chunk and compares it
+// with the one written by Sink with checksum. ErrChecksumNotFound is
+// returned if the stream doesn't have checksum chunk and ErrVerification
+// if the checksum doesn't match. The stream is rewinded to the start
+// afterwards. Options are applied as Sink applies them, e.g. WithFormType
+// must be provided if the stream has custom form type.
+func VerifyChecksum(rs io.ReadSeeker, options ...Option) error {
+	defer rs.Seek(0, io.SeekStart)
+	// wave list is verified as a single data chunk.
+	o := newOptions(options)
+	rs, err := o.expandWaveList(rs)
+	if err != nil {
+		return err
+	}
+	c, err := readForm(rs, o.riffFormType())
+	if err != nil {
+		return err
+	}
+	h, ok := c.find(checksumID)
+	if !ok {
+		return ErrChecksumNotFound
+	}
+	payload, err := readPayload(rs, h)
+	if err != nil {
+		return err
+	}
+	if len(payload) < checksumSize {
+		return fmt.Errorf("%w: checksum chunk is too short: %d bytes", ErrVerification, len(payload))
+	}
+	size, expected := binary.LittleEndian.Uint32(payload[0:]), binary.LittleEndian.Uint32(payload[4:])
+
+	data, ok := c.find(dataID)
+	if !ok {
+		return fmt.Errorf("%w: data chunk not found", ErrVerification)
+	}
+	if data.Size != size {
+		return fmt.Errorf("%w: data size %d instead of %d", ErrVerification, data.Size, size)
+	}
+	if _, err := rs.Seek(data.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking data chunk: %w", err)
+	}
+
+	checksum := crc32.NewIEEE()
+	if _, err := io.CopyN(checksum, rs, int64(size)); err != nil {
+		return fmt.Errorf("%w: error reading data: %v", ErrVerification, err)
+	}
+	if actual := checksum.Sum32(); actual != expected {
+		return fmt.Errorf("%w: data checksum %08x instead of %08x", ErrVerification, actual, expected)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestChecksum(t *testing.T) {
+	samples := []float64{0.1, -0.2, 0.3, -0.4, 0.5, -0.6}
+	for _, test := range []struct {
+		bitDepth signal.BitDepth
+		channels int
+		samples  []float64
+	}{
+		{bitDepth: signal.BitDepth16, channels: 2, samples: samples},
+		// odd size of data chunk, the pad byte isn't included.
+		{bitDepth: signal.BitDepth8, channels: 1, samples: samples[:5]},
+		{bitDepth: signal.BitDepth24, channels: 3, samples: samples},
+	} {
+		var out buffer
+		transcode(t, floatSource(44100, test.channels, test.samples), wav.Sink(&out, test.bitDepth, wav.WithChecksum()))
+		if ids := chunkIDs(out.data); ids[len(ids)-1] != "ckrc" {
+			t.Fatalf("%d bits: unexpected chunks %v", test.bitDepth, ids)
+		}
+		size := len(test.samples) * int(test.bitDepth) / 8
+		payload := out.data[len(out.data)-8:]
+		if written := binary.LittleEndian.Uint32(payload); written != uint32(size) {
+			t.Errorf("%d bits: expected size %d got %d", test.bitDepth, size, written)
+		}
+		if checksum := binary.LittleEndian.Uint32(payload[4:]); checksum != crc32.ChecksumIEEE(out.data[44:44+size]) {
+			t.Errorf("%d bits: unexpected checksum %08x", test.bitDepth, checksum)
+		}
+		if err := wav.VerifyChecksum(bytes.NewReader(out.data)); err != nil {
+			t.Errorf("%d bits: unexpected error: %v", test.bitDepth, err)
+		}
+
+		var warnings []wav.Warning
+		transcode(t, wav.Source(bytes.NewReader(out.data), wav.WithWarnings(&warnings)), wav.Sink(&buffer{}, test.bitDepth))
+		if len(warnings) != 0 {
+			t.Errorf("%d bits: unexpected warnings %v", test.bitDepth, warnings)
+		}
+
+		out.data[45] ^= 0xFF
+		if err := wav.VerifyChecksum(bytes.NewReader(out.data)); !errors.Is(err, wav.ErrVerification) {
+			t.Errorf("%d bits: expected verification error got %v", test.bitDepth, err)
+		}
+	}
+
+	var out buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&out, signal.BitDepth16))
+	if err := wav.VerifyChecksum(bytes.NewReader(out.data)); err != wav.ErrChecksumNotFound {
+		t.Errorf("expected checksum not found error got %v", err)
+	}
+
+	// custom form type is verified with the same option.
+	formType := wav.WithFormType([4]byte{'A', 'B', 'C', 'D'})
+	for _, options := range [][]wav.Option{
+		{wav.WithChecksum(), formType},
+		{wav.WithChecksum(), formType, wav.WithSilenceChunks(1)},
+	} {
+		out = buffer{}
+		transcode(t, floatSource(44100, 1, []float64{0.1, 0, 0, -0.1}), wav.Sink(&out, signal.BitDepth16, options...))
+		if err := wav.VerifyChecksum(bytes.NewReader(out.data), formType); err != nil {
+			t.Errorf("%d options: unexpected error: %v", len(options), err)
+		}
+		if err := wav.VerifyChecksum(bytes.NewReader(out.data)); err != wav.ErrInvalidWav {
+			t.Errorf("%d options: expected invalid wav error without form type got %v", len(options), err)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-audio/wav"
+)
+
+// RIFF chunk identifiers.
+var (
+	riffID = [4]byte{'R', 'I', 'F', 'F'}
+	waveID = [4]byte{'W', 'A', 'V', 'E'}
+	fmtID  = [4]byte{'f', 'm', 't', ' '}
+	dataID = [4]byte{'d', 'a', 't', 'a'}
+	listID = [4]byte{'L', 'I', 'S', 'T'}
+	factID = [4]byte{'f', 'a', 'c', 't'}
+	junkID = [4]byte{'J', 'U', 'N', 'K'}
+	id3ID  = [4]byte{'i', 'd', '3', ' '}
+)
+
+// knownChunks are the chunk identifiers commonly found in wav files and
+// the ones specific to this package.
+var knownChunks = map[[4]byte]bool{
+	fmtID:                true,
+	dataID:               true,
+	listID:               true,
+	factID:               true,
+	junkID:               true,
+	{'P', 'A', 'D', ' '}: true,
+	cueID:                true,
+	{'p', 'l', 's', 't'}: true,
+	{'s', 'm', 'p', 'l'}: true,
+	{'i', 'n', 's', 't'}: true,
+	{'a', 'c', 'i', 'd'}: true,
+	{'b', 'e', 'x', 't'}: true,
+	ixmlID:               true,
+	{'c', 'a', 'r', 't'}: true,
+	{'l', 'e', 'v', 'l'}: true,
+	chnaID:               true,
+	axmlID:               true,
+	{'d', 's', '6', '4'}: true,
+	dispID:               true,
+	id3ID:                true,
+	{'I', 'D', '3', ' '}: true,
+	midSideID:            true,
+	signed8ID:            true,
+	checksumID:           true,
+}
+
+// chunkHeader describes a chunk of RIFF container.
+type chunkHeader struct {
+	ID   [4]byte
+	Size uint32
+	// Offset of the chunk payload in the stream.
+	Offset int64
+}
+
+// end returns the offset of the first byte after the chunk payload and its
+// padding.
+func (h chunkHeader) end() int64 {
+	return h.Offset + int64(h.Size) + int64(h.Size%2)
+}
+
+// container is the layout of RIFF WAVE stream.
+type container struct {
+	// Size of the stream in bytes.
+	Size int64
+	// RIFFSize is the size declared in RIFF header.
+	RIFFSize uint32
+	chunks   []chunkHeader
+}
+
+// find returns the first chunk with provided identifier.
+func (c container) find(id [4]byte) (chunkHeader, bool) {
+	for _, h := range c.chunks {
+		if h.ID == id {
+			return h, true
+		}
+	}
+	return chunkHeader{}, false
+}
+
+// index returns the position of the first chunk with provided identifier
+// or -1 if there is no such chunk.
+func (c container) index(id [4]byte) int {
+	for i, h := range c.chunks {
+		if h.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// readContainer reads headers of all chunks in the RIFF WAVE stream. Only
+// chunk headers are read, payloads are skipped with Seek. The stream is
+// rewinded to the start afterwards.
+func readContainer(rs io.ReadSeeker) (container, error) {
+	return readForm(rs, waveID)
+}
+
+// readForm reads headers of all chunks in the RIFF stream with provided
+// form type.
+func readForm(rs io.ReadSeeker, formType [4]byte) (container, error) {
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return container{}, fmt.Errorf("error seeking stream end: %w", err)
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return container{}, fmt.Errorf("error seeking stream start: %w", err)
+	}
+
+	var riff struct {
+		ID       [4]byte
+		Size     uint32
+		FormType [4]byte
+	}
+	if err := binary.Read(rs, binary.LittleEndian, &riff); err != nil {
+		return container{}, ErrInvalidWav
+	}
+	if riff.ID != riffID || riff.FormType != formType {
+		return container{}, ErrInvalidWav
+	}
+
+	c := container{
+		Size:     size,
+		RIFFSize: riff.Size,
+	}
+	offset := int64(12)
+	for offset+8 <= size {
+		if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+			return container{}, fmt.Errorf("error seeking chunk: %w", err)
+		}
+		var h struct {
+			ID   [4]byte
+			Size uint32
+		}
+		if err := binary.Read(rs, binary.LittleEndian, &h); err != nil {
+			return container{}, fmt.Errorf("error reading chunk header: %w", err)
+		}
+		header := chunkHeader{
+			ID:     h.ID,
+			Size:   h.Size,
+			Offset: offset + 8,
+		}
+		c.chunks = append(c.chunks, header)
+		offset = header.end()
+	}
+
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return container{}, fmt.Errorf("error seeking stream start: %w", err)
+	}
+	return c, nil
+}
+
+// format is the content of fmt chunk.
+type format struct {
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+	// Extension is the content of the chunk after cbSize field.
+	Extension []byte
+	// offset of the chunk payload in the stream.
+	offset int64
+}
+
+// errFormatNotFound is returned when stream doesn't have fmt chunk.
+var errFormatNotFound = errors.New("fmt chunk not found")
+
+// readFormat reads the fmt chunk of the container. The stream is rewinded
+// to the start afterwards.
+func readFormat(rs io.ReadSeeker, c container) (format, error) {
+	h, ok := c.find(fmtID)
+	if !ok {
+		return format{}, errFormatNotFound
+	}
+	if h.Size < 16 {
+		return format{}, fmt.Errorf("fmt chunk is too short: %d bytes", h.Size)
+	}
+	if _, err := rs.Seek(h.Offset, io.SeekStart); err != nil {
+		return format{}, fmt.Errorf("error seeking fmt chunk: %w", err)
+	}
+	defer rs.Seek(0, io.SeekStart)
+
+	f := format{offset: h.Offset}
+	fields := []interface{}{
+		&f.AudioFormat,
+		&f.Channels,
+		&f.SampleRate,
+		&f.ByteRate,
+		&f.BlockAlign,
+		&f.BitsPerSample,
+	}
+	for _, field := range fields {
+		if err := binary.Read(rs, binary.LittleEndian, field); err != nil {
+			return format{}, fmt.Errorf("error reading fmt chunk: %w", err)
+		}
+	}
+	if h.Size < 18 {
+		return f, nil
+	}
+	var cbSize uint16
+	if err := binary.Read(rs, binary.LittleEndian, &cbSize); err != nil {
+		return format{}, fmt.Errorf("error reading fmt chunk: %w", err)
+	}
+	// extension can't exceed the chunk.
+	if max := h.Size - 18; uint32(cbSize) > max {
+		cbSize = uint16(max)
+	}
+	f.Extension = make([]byte, cbSize)
+	if _, err := io.ReadFull(rs, f.Extension); err != nil {
+		return format{}, fmt.Errorf("error reading fmt extension: %w", err)
+	}
+	return f, nil
+}
+
+// Audio format codes of fmt chunk.
+const (
+	formatPCM        = 1
+	formatFloat      = 3
+	formatExtensible = 0xFFFE
+)
+
+// linear returns true if the format stores uncompressed samples.
+func (f format) linear() bool {
+	switch f.AudioFormat {
+	case formatPCM, formatFloat, formatExtensible:
+		return true
+	}
+	return false
+}
+
+// bytesPerSample returns the number of bytes used to store single sample.
+func (f format) bytesPerSample() int {
+	return (int(f.BitsPerSample) + 7) / 8
+}
+
+// extended returns true if the format is extensible and has the whole
+// extension. Extensible formats with missing or short extension are
+// decoded as PCM of container bit depth.
+func (f format) extended() bool {
+	return f.AudioFormat == formatExtensible && len(f.Extension) >= extensibleSize
+}
+
+// validBits returns the number of meaningful bits of every sample. It's
+// declared by extensible format, other formats use the whole sample.
+func (f format) validBits() int {
+	if f.extended() {
+		if valid := binary.LittleEndian.Uint16(f.Extension); valid > 0 && valid <= f.BitsPerSample {
+			return int(valid)
+		}
+	}
+	return int(f.BitsPerSample)
+}
+
+// samplesPerBlock returns the number of frames in every block of
+// compressed format as declared by fmt extension. Zero is returned for
+// linear formats and if the extension doesn't declare it.
+func (f format) samplesPerBlock() int {
+	if f.linear() || len(f.Extension) < 2 {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint16(f.Extension))
+}
+
+// rawChunk is a chunk with its payload.
+type rawChunk struct {
+	ID      [4]byte
+	Payload []byte
+}
+
+// readPayload reads the payload of the chunk. The stream is rewinded to
+// the start afterwards.
+func readPayload(rs io.ReadSeeker, h chunkHeader) ([]byte, error) {
+	if _, err := rs.Seek(h.Offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking %q chunk: %w", h.ID[:], err)
+	}
+	defer rs.Seek(0, io.SeekStart)
+
+	payload := make([]byte, h.Size)
+	if _, err := io.ReadFull(rs, payload); err != nil {
+		return nil, fmt.Errorf("error reading %q chunk: %w", h.ID[:], err)
+	}
+	return payload, nil
+}
+
+// ReadChunk returns the payload of the first chunk with provided
+// identifier. False is returned if the stream doesn't have such chunk. The
+// stream is rewinded to the start afterwards.
+func ReadChunk(rs io.ReadSeeker, id [4]byte) ([]byte, bool, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return nil, false, err
+	}
+	h, ok := c.find(id)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, err := readPayload(rs, h)
+	if err != nil {
+		return nil, false, err
+	}
+	return payload, true, nil
+}
+
+// ChunkFunc is called for every chunk of the stream with its identifier,
+// declared size and the offset of its payload.
+type ChunkFunc func(id [4]byte, size uint32, offset int64)
+
+// WithChunkFunc makes Source call provided function for every chunk in the
+// order of the stream while the header is parsed. It's called when Source
+// is allocated, before any frames are decoded.
+func WithChunkFunc(fn ChunkFunc) Option {
+	return func(o *options) {
+		o.chunkFunc = fn
+	}
+}
+
+// notifyChunks calls the chunk function for every chunk of the container.
+func (o *options) notifyChunks(c container) {
+	if o.chunkFunc == nil {
+		return
+	}
+	for _, h := range c.chunks {
+		o.chunkFunc(h.ID, h.Size, h.Offset)
+	}
+}
+
+// ChunkPosition defines where Sink writes the chunk relative to data
+// chunk.
+type ChunkPosition int
+
+const (
+	// BeforeData places the chunk between fmt and data chunks.
+	BeforeData ChunkPosition = iota
+	// AfterData places the chunk after data chunk.
+	AfterData
+)
+
+// WithChunk makes Sink write the chunk with provided identifier and raw
+// payload at provided position. Chunks are written in the order of
+// options and padded to even size. Identifier must consist of printable
+// ASCII characters and can't be one of the chunks written by Sink itself:
+// RIFF, fmt, data, JUNK, ds64, bext, ckrc, mstr, sgn8, chna, axml, DISP,
+// id3 and slnt. LIST and cue chunks are allowed.
+func WithChunk(id [4]byte, payload []byte, position ChunkPosition) Option {
+	return func(o *options) {
+		c := rawChunk{ID: id, Payload: payload}
+		if position == AfterData {
+			o.after = append(o.after, c)
+		} else {
+			o.before = append(o.before, c)
+		}
+	}
+}
+
+// reservedChunks are the chunks written by Sink itself. LIST and cue
+// chunks aren't reserved, because streams can have many of them.
+var reservedChunks = map[[4]byte]bool{
+	riffID:               true,
+	fmtID:                true,
+	dataID:               true,
+	junkID:               true,
+	{'d', 's', '6', '4'}: true,
+	bextID:               true,
+	checksumID:           true,
+	midSideID:            true,
+	signed8ID:            true,
+	chnaID:               true,
+	axmlID:               true,
+	dispID:               true,
+	id3ID:                true,
+	slntID:               true,
+}
+
+// validateChunkID checks if the chunk with provided identifier can be
+// written.
+func validateChunkID(id [4]byte) error {
+	if !printableID(id) {
+		return fmt.Errorf("invalid chunk id %q", id[:])
+	}
+	if reservedChunks[id] {
+		return fmt.Errorf("reserved chunk id %q", id[:])
+	}
+	return nil
+}
+
+// writeChunk writes the chunk with encoder. Chunk is aligned to the word
+// boundary and padded if payload has odd size.
+func writeChunk(encoder *wav.Encoder, c rawChunk) error {
+	if encoder.WrittenBytes%2 == 1 {
+		if err := encoder.AddLE(uint8(0)); err != nil {
+			return fmt.Errorf("error writing padding: %w", err)
+		}
+	}
+	if err := encoder.AddLE(c.ID); err != nil {
+		return fmt.Errorf("error writing %q chunk id: %w", c.ID[:], err)
+	}
+	if err := encoder.AddLE(uint32(len(c.Payload))); err != nil {
+		return fmt.Errorf("error writing %q chunk size: %w", c.ID[:], err)
+	}
+	if err := encoder.AddLE(c.Payload); err != nil {
+		return fmt.Errorf("error writing %q chunk: %w", c.ID[:], err)
+	}
+	if len(c.Payload)%2 == 1 {
+		if err := encoder.AddLE(uint8(0)); err != nil {
+			return fmt.Errorf("error writing %q chunk padding: %w", c.ID[:], err)
+		}
+	}
+	return nil
+}
+
+// inferChannels returns the number of channels that matches block align.
+// Declared number is returned if block align isn't a multiple of sample
+// size.
+func (f format) inferChannels() int {
+	bytesPerSample := f.bytesPerSample()
+	if !f.linear() || bytesPerSample == 0 || f.BlockAlign == 0 || int(f.BlockAlign)%bytesPerSample != 0 {
+		return int(f.Channels)
+	}
+	return int(f.BlockAlign) / bytesPerSample
+}
@@ -0,0 +1,116 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestReadChunk(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 8000)),
+		chunk("abcd", []byte{1, 2, 3}),
+		chunk("data", []byte{0, 0}),
+		chunk("abcd", []byte{4}),
+	)
+	r := bytes.NewReader(data)
+	payload, ok, err := wav.ReadChunk(r, [4]byte{'a', 'b', 'c', 'd'})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok || !bytes.Equal(payload, []byte{1, 2, 3}) {
+		t.Errorf("unexpected payload: %v found: %v", payload, ok)
+	}
+	if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+		t.Errorf("stream isn't rewinded: %d", pos)
+	}
+
+	if _, ok, err := wav.ReadChunk(r, [4]byte{'n', 'o', 'n', 'e'}); ok || err != nil {
+		t.Errorf("unexpected result for missing chunk: %v %v", ok, err)
+	}
+	if _, _, err := wav.ReadChunk(bytes.NewReader([]byte("not wav")), [4]byte{'a', 'b', 'c', 'd'}); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %v", err)
+	}
+}
+
+func TestWithChunk(t *testing.T) {
+	var out buffer
+	transcode(t,
+		floatSource(8000, 1, []float64{0.5}),
+		wav.Sink(&out, signal.BitDepth16,
+			wav.WithChunk([4]byte{'v', 'n', 'd', 'a'}, []byte{1, 2, 3}, wav.BeforeData),
+			wav.WithChunk([4]byte{'v', 'n', 'd', 'b'}, []byte{4}, wav.AfterData),
+			wav.WithChunk([4]byte{'v', 'n', 'd', 'c'}, []byte{5, 6}, wav.BeforeData),
+		),
+	)
+	expected := []string{"fmt ", "vnda", "vndc", "data", "vndb"}
+	if ids := chunkIDs(out.data); !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected chunks %v got %v", expected, ids)
+	}
+	payload, ok, err := wav.ReadChunk(bytes.NewReader(out.data), [4]byte{'v', 'n', 'd', 'b'})
+	if err != nil || !ok || !bytes.Equal(payload, []byte{4}) {
+		t.Errorf("unexpected payload: %v found: %v error: %v", payload, ok, err)
+	}
+
+	reserved := []string{"RIFF", "fmt ", "data", "JUNK", "ds64", "bext", "ckrc", "mstr", "sgn8", "chna", "axml", "DISP", "id3 ", "slnt"}
+	ids := [][4]byte{{'a', 'b', 0, 'd'}}
+	for _, id := range reserved {
+		var b [4]byte
+		copy(b[:], id)
+		ids = append(ids, b)
+	}
+	for _, id := range ids {
+		_, err := pipe.New(bufferSize, pipe.Line{
+			Source: floatSource(8000, 1, []float64{0.5}),
+			Sink:   wav.Sink(&buffer{}, signal.BitDepth16, wav.WithChunk(id, nil, wav.AfterData)),
+		})
+		if err == nil {
+			t.Errorf("expected error for chunk id %q", id[:])
+		}
+	}
+
+	// repeated chunks aren't reserved.
+	out = buffer{}
+	transcode(t,
+		floatSource(8000, 1, []float64{0.5}),
+		wav.Sink(&out, signal.BitDepth16,
+			wav.WithChunk([4]byte{'L', 'I', 'S', 'T'}, []byte("INFO"), wav.AfterData),
+			wav.WithChunk([4]byte{'c', 'u', 'e', ' '}, []byte{0, 0, 0, 0}, wav.AfterData),
+		),
+	)
+	if ids := chunkIDs(out.data); !reflect.DeepEqual([]string{"fmt ", "data", "LIST", "cue "}, ids) {
+		t.Errorf("unexpected chunks %v", ids)
+	}
+}
+
+func TestChunkFunc(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 44100)),
+		chunk("LIST", []byte("INFOabc")),
+		chunk("data", []byte{0x00, 0x10, 0x00, 0xF0}),
+	)
+	type entry struct {
+		id     string
+		size   uint32
+		offset int64
+	}
+	var result []entry
+	fn := func(id [4]byte, size uint32, offset int64) {
+		result = append(result, entry{id: string(id[:]), size: size, offset: offset})
+	}
+	transcode(t, wav.Source(bytes.NewReader(data), wav.WithChunkFunc(fn)), wav.Sink(&buffer{}, signal.BitDepth16))
+	expected := []entry{
+		{id: "fmt ", size: 16, offset: 20},
+		{id: "LIST", size: 7, offset: 44},
+		{id: "data", size: 4, offset: 60},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected %v got %v", expected, result)
+	}
+}
@@ -0,0 +1,44 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrChunkSize is returned by Source when the declared size of a chunk
+// doesn't fit the stream, see ValidateChunkSizes.
+var ErrChunkSize = errors.New("invalid chunk size")
+
+// ValidateChunkSizes makes Source check that every chunk fits into the
+// stream and doesn't overlap the next chunk. A chunk overlaps the next one
+// if the header that follows it has an identifier that isn't printable
+// ASCII. Source allocation fails with ErrChunkSize that names the
+// offending chunk. With Lenient, the chunk that exceeds the stream is
+// clamped to the end of the stream instead, which is reported as warning.
+// The overlapping chunk can't be repaired, so it always fails.
+func ValidateChunkSizes() Option {
+	return func(o *options) {
+		o.chunkSizes = true
+	}
+}
+
+// checkChunkSizes validates the sizes of container chunks and clamps
+// them in lenient mode.
+func (o *options) checkChunkSizes(c *container) error {
+	if !o.chunkSizes {
+		return nil
+	}
+	for i, h := range c.chunks {
+		if available := c.Size - h.Offset; int64(h.Size) > available {
+			if !o.lenient {
+				return fmt.Errorf("%w: %q chunk at offset %d declares %d bytes, %d bytes remain in stream", ErrChunkSize, h.ID[:], h.Offset-8, h.Size, available)
+			}
+			o.warn(h.Offset-8, "%q chunk size %d clamped to %d bytes of stream", h.ID[:], h.Size, available)
+			c.chunks[i].Size = uint32(available)
+		}
+		if i+1 < len(c.chunks) && !printableID(c.chunks[i+1].ID) {
+			return fmt.Errorf("%w: %q chunk at offset %d with size %d overlaps the next chunk", ErrChunkSize, h.ID[:], h.Offset-8, h.Size)
+		}
+	}
+	return nil
+}
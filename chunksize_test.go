@@ -0,0 +1,70 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestValidateChunkSizes(t *testing.T) {
+	oversized := chunk("data", rampData(20))
+	binary.LittleEndian.PutUint32(oversized[4:], 1000)
+	overlapping := chunk("LIST", make([]byte, 10))
+	binary.LittleEndian.PutUint32(overlapping[4:], 14)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		expected   string
+		repairable bool
+	}{
+		{
+			name:       "exceeds stream",
+			data:       riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), oversized),
+			expected:   `"data" chunk at offset 36`,
+			repairable: true,
+		},
+		{
+			name:     "overlaps next chunk",
+			data:     riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), overlapping, chunk("data", rampData(20))),
+			expected: `"LIST" chunk at offset 36`,
+		},
+	}
+	for _, test := range tests {
+		_, err := wav.NewReader(bytes.NewReader(test.data), wav.ValidateChunkSizes())
+		if !errors.Is(err, wav.ErrChunkSize) || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected chunk size error of %s got %v", test.name, test.expected, err)
+		}
+
+		var warnings []wav.Warning
+		r, err := wav.NewReader(bytes.NewReader(test.data), wav.ValidateChunkSizes(), wav.Lenient(), wav.WithWarnings(&warnings))
+		if !test.repairable {
+			if !errors.Is(err, wav.ErrChunkSize) {
+				t.Errorf("%s: expected lenient chunk size error got %v", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected lenient error: %v", test.name, err)
+		}
+		if samples := readAll(t, r); len(samples) != 20 {
+			t.Errorf("%s: expected 20 samples got %d", test.name, len(samples))
+		}
+		found := false
+		for _, w := range warnings {
+			found = found || strings.Contains(w.Message, "chunk size 1000 clamped to 40 bytes")
+		}
+		if !found {
+			t.Errorf("%s: expected clamp warning got %v", test.name, warnings)
+		}
+	}
+
+	valid := riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("LIST", make([]byte, 10)), chunk("data", rampData(20)))
+	if _, err := wav.NewReader(bytes.NewReader(valid), wav.ValidateChunkSizes()); err != nil {
+		t.Errorf("unexpected error for valid stream: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// errDataNotFound is returned when stream doesn't have data chunk.
+var errDataNotFound = errors.New("data chunk not found")
+
+// Clean writes a minimal copy of the stream that has only valid chunks.
+// A chunk is valid if its identifier is printable ASCII and its payload
+// fits into the stream. The chunks are parsed up to the first invalid
+// one, so unstructured bytes after the data chunk are dropped along with
+// everything that follows them. The bytes after the size declared in
+// RIFF header are dropped too, unless the declared size doesn't include
+// data chunk. If data chunk exceeds the stream, e.g. recording was
+// interrupted, it's truncated to the last whole frame. Valid chunks are
+// copied exactly, including metadata. The stream is rewinded to the start
+// afterwards.
+func Clean(rs io.ReadSeeker, w io.Writer) error {
+	c, err := readContainer(rs)
+	if err != nil {
+		return err
+	}
+	f, err := readFormat(rs, c)
+	if err != nil {
+		return err
+	}
+	kept := container{chunks: c.validChunks(f)}
+	if kept.index(dataID) == -1 {
+		return errDataNotFound
+	}
+	defer rs.Seek(0, io.SeekStart)
+
+	size := uint32(4)
+	for _, h := range kept.chunks {
+		size += 8 + h.Size + h.Size%2
+	}
+	if _, err := w.Write(append(append(riffID[:], le32(size)...), waveID[:]...)); err != nil {
+		return fmt.Errorf("error writing RIFF header: %w", err)
+	}
+	for _, h := range kept.chunks {
+		if err := copyChunk(w, rs, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validChunks returns the chunks that Clean keeps.
+func (c container) validChunks(f format) []chunkHeader {
+	end := c.Size
+	if data, ok := c.find(dataID); ok {
+		if riffEnd := int64(c.RIFFSize) + 8; riffEnd < end && data.Offset+int64(data.Size) <= riffEnd {
+			end = riffEnd
+		}
+	}
+	var chunks []chunkHeader
+	for _, h := range c.chunks {
+		if !printableID(h.ID) || h.Offset > end {
+			break
+		}
+		if h.Offset+int64(h.Size) > end {
+			if h.ID == dataID {
+				// truncate the data to the last whole frame.
+				available := uint32(end - h.Offset)
+				if f.BlockAlign > 0 {
+					available -= available % uint32(f.BlockAlign)
+				}
+				h.Size = available
+				chunks = append(chunks, h)
+			}
+			break
+		}
+		chunks = append(chunks, h)
+	}
+	return chunks
+}
+
+// printableID returns true if the chunk identifier is printable ASCII.
+func printableID(id [4]byte) bool {
+	for _, b := range id {
+		if b < 0x20 || b > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// copyChunk copies the chunk from the stream and pads its odd payload.
+func copyChunk(w io.Writer, rs io.ReadSeeker, h chunkHeader) error {
+	if _, err := w.Write(append(h.ID[:], le32(h.Size)...)); err != nil {
+		return fmt.Errorf("error writing %q chunk header: %w", h.ID[:], err)
+	}
+	if _, err := rs.Seek(h.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking %q chunk: %w", h.ID[:], err)
+	}
+	if _, err := io.CopyN(w, rs, int64(h.Size)); err != nil {
+		return fmt.Errorf("error copying %q chunk: %w", h.ID[:], err)
+	}
+	if h.Size%2 == 1 {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return fmt.Errorf("error writing %q chunk padding: %w", h.ID[:], err)
+		}
+	}
+	return nil
+}
+
+// le32 returns little-endian bytes of the value.
+func le32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
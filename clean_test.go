@@ -0,0 +1,64 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestClean(t *testing.T) {
+	format := chunk("fmt ", fmtPayload(2, 16, 4, 44100))
+	info := chunk("LIST", append([]byte("INFOINAM"), 5, 0, 0, 0, 't', 'i', 't', 'l', 'e', 0))
+	data := chunk("data", rampData(10))
+	clean := riff(format, info, data)
+
+	// garbage with the size of RIFF header that excludes it.
+	declared := append(riff(format, info, data), []byte("junkjunk")...)
+	// garbage after RIFF header with zero size.
+	undeclared := append(riff(format, info, data), 0xDE, 0xAD, 0xBE, 0xEF, 1, 2, 3, 4, 5)
+	binary.LittleEndian.PutUint32(undeclared[4:], 0)
+	// interrupted recording with 4 and a half frames of declared 250.
+	interrupted := riff(format, info, append([]byte("data\xe8\x03\x00\x00"), rampData(10)[:18]...))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected []byte
+		samples  int
+	}{
+		{name: "clean", data: clean, expected: clean, samples: 10},
+		{name: "after declared size", data: declared, expected: clean, samples: 10},
+		{name: "undeclared", data: undeclared, expected: clean, samples: 10},
+		{name: "interrupted", data: interrupted, expected: riff(format, info, chunk("data", rampData(8))), samples: 8},
+	}
+	for _, test := range tests {
+		var out buffer
+		if err := wav.Clean(bytes.NewReader(test.data), &out); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !bytes.Equal(out.data, test.expected) {
+			t.Errorf("%s: expected\n%x\ngot\n%x", test.name, test.expected, out.data)
+		}
+		var warnings []wav.Warning
+		r, err := wav.NewReader(bytes.NewReader(out.data), wav.WithWarnings(&warnings))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("%s: unexpected warnings %v", test.name, warnings)
+		}
+		if samples := readAll(t, r); len(samples) != test.samples {
+			t.Errorf("%s: expected %d samples got %d", test.name, test.samples, len(samples))
+		}
+		if info, err := wav.ReadInfo(bytes.NewReader(out.data)); err != nil || !reflect.DeepEqual(info, map[string]string{"INAM": "title"}) {
+			t.Errorf("%s: unexpected info %v: %v", test.name, info, err)
+		}
+	}
+
+	if err := wav.Clean(bytes.NewReader(riff(format)), &buffer{}); err == nil {
+		t.Errorf("expected error for stream without data")
+	}
+}
@@ -0,0 +1,161 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// blockDecoder decodes compressed blocks into 16-bit samples.
+type blockDecoder struct {
+	r        io.Reader
+	channels int
+	block    []byte
+	// minBlock is the size of the smallest decodable block.
+	minBlock int
+	// decode decodes the block into interleaved samples and returns the
+	// number of decoded frames.
+	decode func(block []byte, samples []int) int
+	// decoded interleaved samples of current block.
+	samples []int
+	// position and length of decoded block in frames.
+	position int
+	length   int
+	// number of frames left to decode as declared by fact chunk, -1 if
+	// the stream has no fact chunk.
+	remaining int64
+}
+
+// newCodecReader returns the reader of compressed or float stream, which
+// go-audio can't decode. ErrInvalidWav is returned if the format isn't
+// supported.
+func newCodecReader(rs io.ReadSeeker, bufferSize int) (*Reader, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return nil, err
+	}
+	f, err := readFormat(rs, c)
+	if err != nil {
+		return nil, ErrInvalidWav
+	}
+	if f.float() {
+		return newFloatReader(rs, c, f, bufferSize)
+	}
+	var d *blockDecoder
+	switch f.AudioFormat {
+	case formatIMAADPCM:
+		d, err = newIMADecoder(f)
+	case formatGSM:
+		d, err = newGSMDecoder(f)
+	default:
+		return nil, ErrInvalidWav
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d.remaining, err = factFrames(rs, c); err != nil {
+		return nil, err
+	}
+	if d.r, err = dataReader(rs, c); err != nil {
+		return nil, err
+	}
+
+	r := Reader{
+		format: Format{
+			SampleRate: signal.Frequency(f.SampleRate),
+			Channels:   int(f.Channels),
+			BitDepth:   signal.BitDepth16,
+		},
+		codec: d,
+	}
+	r.allocate(bufferSize)
+	return &r, nil
+}
+
+// dataReader returns the reader of data chunk payload limited by the
+// size of the stream.
+func dataReader(rs io.ReadSeeker, c container) (io.Reader, error) {
+	h, ok := c.find(dataID)
+	if !ok {
+		return nil, ErrInvalidWav
+	}
+	size := int64(h.Size)
+	if available := c.Size - h.Offset; available < size {
+		size = available
+	}
+	if _, err := rs.Seek(h.Offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking data chunk: %w", err)
+	}
+	return io.LimitReader(rs, size), nil
+}
+
+// factFrames returns the number of frames declared by fact chunk, -1 if
+// the stream has no fact chunk.
+func factFrames(rs io.ReadSeeker, c container) (int64, error) {
+	h, ok := c.find(factID)
+	if !ok || h.Size < 4 {
+		return -1, nil
+	}
+	payload, err := readPayload(rs, h)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint32(payload)), nil
+}
+
+// read decodes up to provided number of frames into the buffer. Returns
+// the number of frames read, io.EOF is returned when data is done. The
+// frames beyond the length declared by fact chunk are dropped.
+func (d *blockDecoder) read(dst signal.Signed, frames int) (int, error) {
+	if d.remaining >= 0 && int64(frames) > d.remaining {
+		if d.remaining == 0 {
+			return 0, io.EOF
+		}
+		frames = int(d.remaining)
+	}
+	read := 0
+	for read < frames {
+		if d.position == d.length {
+			if err := d.next(); err != nil {
+				if err == io.EOF && read > 0 {
+					break
+				}
+				return read, err
+			}
+		}
+		n := frames - read
+		if available := d.length - d.position; available < n {
+			n = available
+		}
+		samples := d.samples[d.position*d.channels : (d.position+n)*d.channels]
+		for i, v := range samples {
+			dst.SetSample(read*d.channels+i, int64(v))
+		}
+		d.position += n
+		read += n
+	}
+	if d.remaining >= 0 {
+		d.remaining -= int64(read)
+	}
+	return read, nil
+}
+
+// next reads and decodes the next block. The last block can be shorter
+// than block align.
+func (d *blockDecoder) next() error {
+	n, err := io.ReadFull(d.r, d.block)
+	if err == io.EOF || (err == io.ErrUnexpectedEOF && n < d.minBlock) {
+		return io.EOF
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("error reading block: %w", err)
+	}
+	d.position = 0
+	d.length = d.decode(d.block[:n], d.samples)
+	if d.length == 0 {
+		return io.EOF
+	}
+	return nil
+}
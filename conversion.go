@@ -0,0 +1,42 @@
+package wav
+
+import (
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// snapTolerance is the distance in LSB below which the scaled sample is
+// snapped to the nearest level before rounding. It absorbs the error of
+// positive samples scaled by different maximum signed values, so they
+// aren't truncated to the level below after up-conversion round trip.
+const snapTolerance = 1.0 / 64
+
+// snap returns the nearest level if the scaled sample is within the
+// tolerance from it.
+func snap(v float64) float64 {
+	if level := math.Round(v); math.Abs(v-level) < snapTolerance {
+		return level
+	}
+	return v
+}
+
+// unsignedAsFloating converts unsigned fixed-point samples into
+// floating-point with the same scaling of both signs as signed samples.
+// Returns a number of samples written per channel.
+func unsignedAsFloating(src signal.Unsigned, dst signal.Floating) int {
+	length := src.Len()
+	if dst.Len() < length {
+		length = dst.Len()
+	}
+	msv := float64(src.BitDepth().MaxSignedValue())
+	for i := 0; i < length; i++ {
+		v := float64(src.Sample(i)) - (msv + 1)
+		if v > 0 {
+			dst.SetSample(i, v/msv)
+		} else {
+			dst.SetSample(i, v/(msv+1))
+		}
+	}
+	return signal.ChannelLength(length, src.Channels())
+}
@@ -0,0 +1,135 @@
+package wav_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// conversionSamples returns the samples of provided bit depth that cover
+// the whole range: every value of 8 and 16 bits, and extremes with
+// spread values of wider ones.
+func conversionSamples(bitDepth signal.BitDepth) []int64 {
+	msv := int64(bitDepth.MaxSignedValue())
+	if bitDepth <= signal.BitDepth16 {
+		samples := make([]int64, 0, 2*(msv+1))
+		for v := -msv - 1; v <= msv; v++ {
+			samples = append(samples, v)
+		}
+		return samples
+	}
+	samples := []int64{-msv - 1, -msv, -1, 0, 1, msv - 1, msv}
+	for i, v := int64(0), int64(12345); i < 4096; i++ {
+		v = (v*1103515245 + 12345) % (2 * (msv + 1))
+		samples = append(samples, v-msv-1)
+	}
+	return samples
+}
+
+// encodePCM returns canonical wav stream with provided mono samples.
+func encodePCM(bitDepth signal.BitDepth, samples []int64) []byte {
+	bytesPerSample := int(bitDepth) / 8
+	pcm := make([]byte, 0, len(samples)*bytesPerSample)
+	for _, v := range samples {
+		if bitDepth == signal.BitDepth8 {
+			v += 128
+		}
+		for b := 0; b < bytesPerSample; b++ {
+			pcm = append(pcm, byte(v>>(8*uint(b))))
+		}
+	}
+	return riff(chunk("fmt ", fmtPayload(1, uint16(bitDepth), uint16(bytesPerSample), 48000)), chunk("data", pcm))
+}
+
+// decodePCM returns mono samples of canonical wav stream.
+func decodePCM(bitDepth signal.BitDepth, data []byte) []int64 {
+	bytesPerSample := int(bitDepth) / 8
+	pcm := data[44:]
+	samples := make([]int64, len(pcm)/bytesPerSample)
+	for i := range samples {
+		var u uint64
+		for b := 0; b < bytesPerSample; b++ {
+			u |= uint64(pcm[i*bytesPerSample+b]) << (8 * uint(b))
+		}
+		if bitDepth == signal.BitDepth8 {
+			samples[i] = int64(u) - 128
+			continue
+		}
+		shift := 64 - uint(bitDepth)
+		samples[i] = int64(u<<shift) >> shift
+	}
+	return samples
+}
+
+// asFloating returns the value of the sample as Source decodes it.
+func asFloating(v int64, bitDepth signal.BitDepth) float64 {
+	msv := float64(bitDepth.MaxSignedValue())
+	if v > 0 {
+		return float64(v) / msv
+	}
+	return float64(v) / (msv + 1)
+}
+
+func TestConversionMatrix(t *testing.T) {
+	bitDepths := []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24, signal.BitDepth32}
+	roundings := []struct {
+		rounding wav.Rounding
+		// maximum error in LSB of output bit depth.
+		maxError float64
+	}{
+		{rounding: wav.RoundTruncate, maxError: 1},
+		{rounding: wav.RoundHalfEven, maxError: 0.5},
+	}
+	for _, r := range roundings {
+		for _, from := range bitDepths {
+			samples := conversionSamples(from)
+			in := encodePCM(from, samples)
+			for _, to := range bitDepths {
+				var converted buffer
+				transcode(t, wav.Source(bytes.NewReader(in)), wav.Sink(&converted, to, wav.WithRounding(r.rounding)))
+				result := decodePCM(to, converted.data)
+				if len(result) != len(samples) {
+					t.Fatalf("%v to %v: expected %d samples got %d", from, to, len(samples), len(result))
+				}
+
+				for i, v := range samples {
+					expected, actual := asFloating(v, from), asFloating(result[i], to)
+					if lsb := 1 / float64(to.MaxSignedValue()); math.Abs(expected-actual) > r.maxError*lsb+1e-12 {
+						t.Errorf("rounding %v, %v to %v: sample %d converted to %d", r.rounding, from, to, v, result[i])
+						break
+					}
+				}
+				if to < from {
+					continue
+				}
+
+				// up-conversion is lossless.
+				var back buffer
+				transcode(t, wav.Source(bytes.NewReader(converted.data)), wav.Sink(&back, from, wav.WithRounding(r.rounding)))
+				restored := decodePCM(from, back.data)
+				for i := range samples {
+					if restored[i] != samples[i] {
+						t.Errorf("rounding %v, %v to %v and back: sample %d restored as %d", r.rounding, from, to, samples[i], restored[i])
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConversionSymmetry(t *testing.T) {
+	// full scale values of both signs are kept.
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24, signal.BitDepth32} {
+		var out buffer
+		transcode(t, floatSource(48000, 1, []float64{-1, 1}), wav.Sink(&out, bitDepth))
+		msv := int64(bitDepth.MaxSignedValue())
+		if samples := decodePCM(bitDepth, out.data); samples[0] != -msv-1 || samples[1] != msv {
+			t.Errorf("%v: unexpected full scale samples %v", bitDepth, samples)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package wav
+
+import "sync/atomic"
+
+// Counters holds the progress of Sink. Values are updated after every
+// buffer and can be read from any goroutine. Zero value is ready to use.
+type Counters struct {
+	// accessed atomically, must be 64-bit aligned.
+	frames int64
+	bytes  int64
+}
+
+// WithCounters makes Sink update provided counters.
+func WithCounters(c *Counters) Option {
+	return func(o *options) {
+		o.counters = c
+	}
+}
+
+// Frames returns the number of frames written, including silence.
+func (c *Counters) Frames() int64 {
+	return atomic.LoadInt64(&c.frames)
+}
+
+// Bytes returns the number of bytes written, including header and chunks.
+// The stream is final when Sink is flushed.
+func (c *Counters) Bytes() int64 {
+	return atomic.LoadInt64(&c.bytes)
+}
+
+// update stores the progress of the writer.
+func (c *Counters) update(frames int, bytes int) {
+	if c == nil {
+		return
+	}
+	atomic.StoreInt64(&c.frames, int64(frames))
+	atomic.StoreInt64(&c.bytes, int64(bytes))
+}
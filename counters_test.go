@@ -0,0 +1,37 @@
+package wav_test
+
+import (
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestCounters(t *testing.T) {
+	var c wav.Counters
+	format := wav.Format{SampleRate: 8000, Channels: 2, BitDepth: signal.BitDepth16}
+	var out buffer
+	w, err := wav.NewWriter(&out, format, wav.WithCounters(&c), wav.WithChunk([4]byte{'t', 'e', 's', 't'}, []byte{1, 2}, wav.AfterData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 2, Length: 10, Capacity: 10}.Float64()
+	for i := 1; i <= 3; i++ {
+		if _, err := w.Write(buf); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+		if frames := c.Frames(); frames != int64(i*10) {
+			t.Errorf("expected %d frames got %d", i*10, frames)
+		}
+		if bytes := c.Bytes(); bytes != int64(len(out.data)) {
+			t.Errorf("expected %d bytes got %d", len(out.data), bytes)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if bytes := c.Bytes(); bytes != int64(len(out.data)) || bytes != 44+120+10 {
+		t.Errorf("expected %d bytes got %d", len(out.data), bytes)
+	}
+}
@@ -0,0 +1,260 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// SourceCrossfade reads wav data from multiple ReadSeekers one after
+// another. Consecutive files are overlapped with equal-power crossfade of
+// provided duration: the tail of one file is mixed with the head of the
+// next one, so the output is shorter than the files together by the
+// total length of overlaps. If a file is too short for the crossfade, the
+// fade is shortened. All files must have the same sample rate and number
+// of channels.
+func SourceCrossfade(crossfade time.Duration, rss ...io.ReadSeeker) pipe.SourceAllocatorFunc {
+	return func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+		if len(rss) == 0 {
+			return pipe.Source{}, errors.New("no sources for crossfade")
+		}
+		readers := make([]*frameReader, 0, len(rss))
+		lengths := make([]int, 0, len(rss))
+		var props pipe.SignalProperties
+		for i, rs := range rss {
+			length, err := framesLength(rs)
+			if err != nil {
+				return pipe.Source{}, fmt.Errorf("source %d: %w", i, err)
+			}
+			source, err := Source(rs)(mctx, bufferSize)
+			if err != nil {
+				return pipe.Source{}, fmt.Errorf("source %d: %w", i, err)
+			}
+			if i == 0 {
+				props = source.SignalProperties
+			} else if source.SignalProperties != props {
+				return pipe.Source{}, fmt.Errorf("source %d: signal properties %+v don't match %+v", i, source.SignalProperties, props)
+			}
+			readers = append(readers, newFrameReader(source, bufferSize))
+			lengths = append(lengths, length)
+		}
+
+		c := crossfader{
+			readers:  readers,
+			fades:    crossfadeLengths(lengths, props.SampleRate.Events(crossfade)),
+			lengths:  lengths,
+			channels: props.Channels,
+			a:        make([]float64, bufferSize*props.Channels),
+			b:        make([]float64, bufferSize*props.Channels),
+		}
+		return pipe.Source{
+			SourceFunc:       c.source,
+			SignalProperties: props,
+		}, nil
+	}
+}
+
+// framesLength returns the number of frames declared in the data chunk.
+func framesLength(rs io.ReadSeeker) (int, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return 0, err
+	}
+	f, err := readFormat(rs, c)
+	if err != nil {
+		return 0, err
+	}
+	h, ok := c.find(dataID)
+	if !ok || f.BlockAlign == 0 {
+		return 0, ErrInvalidWav
+	}
+	size := int64(h.Size)
+	if available := c.Size - h.Offset; available < size {
+		size = available
+	}
+	return int(size / int64(f.BlockAlign)), nil
+}
+
+// crossfadeLengths returns the length of every overlap between
+// consecutive files. Fade can't take more frames than the second file has
+// and what is left of the first file after the previous fade.
+func crossfadeLengths(lengths []int, fade int) []int {
+	fades := make([]int, len(lengths)-1)
+	head := 0
+	for i := range fades {
+		f := fade
+		if remaining := lengths[i] - head; remaining < f {
+			f = remaining
+		}
+		if lengths[i+1] < f {
+			f = lengths[i+1]
+		}
+		fades[i] = f
+		head = f
+	}
+	return fades
+}
+
+// crossfader mixes the files.
+type crossfader struct {
+	readers  []*frameReader
+	fades    []int
+	lengths  []int
+	channels int
+	// current file and the position in it.
+	current  int
+	position int
+	// buffers for mixing.
+	a, b []float64
+}
+
+func (c *crossfader) source(out signal.Floating) (int, error) {
+	written := 0
+	for written < out.Length() {
+		if c.current == len(c.readers) {
+			break
+		}
+		fadeOut := 0
+		if c.current < len(c.fades) {
+			fadeOut = c.fades[c.current]
+		}
+		bodyEnd := c.lengths[c.current] - fadeOut
+		limit := out.Length() - written
+		if c.position < bodyEnd {
+			if remaining := bodyEnd - c.position; remaining < limit {
+				limit = remaining
+			}
+			n, err := c.readers[c.current].read(c.a[:limit*c.channels])
+			if err != nil {
+				return 0, err
+			}
+			// file ended before its declared length.
+			if n == 0 {
+				c.next()
+				continue
+			}
+			signal.WriteFloat64(c.a[:n*c.channels], out.Slice(written, written+n))
+			c.position += n
+			written += n
+			continue
+		}
+		if fadeOut == 0 {
+			c.next()
+			continue
+		}
+		// mix the tail with the head of the next file.
+		fadePosition := c.position - bodyEnd
+		if remaining := fadeOut - fadePosition; remaining < limit {
+			limit = remaining
+		}
+		na, err := c.readers[c.current].read(c.a[:limit*c.channels])
+		if err != nil {
+			return 0, err
+		}
+		nb, err := c.readers[c.current+1].read(c.b[:limit*c.channels])
+		if err != nil {
+			return 0, err
+		}
+		n := na
+		if nb > n {
+			n = nb
+		}
+		if n == 0 {
+			c.next()
+			continue
+		}
+		for i := 0; i < n; i++ {
+			t := (float64(fadePosition+i) + 0.5) / float64(fadeOut) * math.Pi / 2
+			gainA, gainB := math.Cos(t), math.Sin(t)
+			for ch := 0; ch < c.channels; ch++ {
+				var v float64
+				if i < na {
+					v += c.a[i*c.channels+ch] * gainA
+				}
+				if i < nb {
+					v += c.b[i*c.channels+ch] * gainB
+				}
+				out.SetSample(out.BufferIndex(ch, written+i), v)
+			}
+		}
+		c.position += n
+		written += n
+	}
+	if written == 0 {
+		return 0, io.EOF
+	}
+	return written, nil
+}
+
+// next switches to the next file. Its head was already read by the fade.
+func (c *crossfader) next() {
+	if c.current < len(c.fades) {
+		c.position = c.fades[c.current]
+	}
+	c.current++
+}
+
+// frameReader reads frames from the source into interleaved slices.
+type frameReader struct {
+	source   pipe.SourceFunc
+	buffer   signal.Floating
+	channels int
+	// position and length of buffered data.
+	position int
+	length   int
+	err      error
+}
+
+func newFrameReader(source pipe.Source, bufferSize int) *frameReader {
+	return &frameReader{
+		source: source.SourceFunc,
+		buffer: signal.Allocator{
+			Channels: source.Channels,
+			Length:   bufferSize,
+			Capacity: bufferSize,
+		}.Float64(),
+		channels: source.Channels,
+	}
+}
+
+// read fills provided interleaved slice with frames. Returns the number
+// of frames read, zero means that source is done. Only errors other than
+// io.EOF are returned.
+func (r *frameReader) read(dst []float64) (int, error) {
+	frames := len(dst) / r.channels
+	read := 0
+	for read < frames {
+		if r.position == r.length {
+			if r.err != nil {
+				break
+			}
+			r.position = 0
+			r.length, r.err = r.source(r.buffer)
+			if r.length == 0 {
+				if r.err == nil {
+					r.err = io.EOF
+				}
+				break
+			}
+		}
+		n := frames - read
+		if available := r.length - r.position; available < n {
+			n = available
+		}
+		for i := 0; i < n*r.channels; i++ {
+			dst[read*r.channels+i] = r.buffer.Sample(r.position*r.channels + i)
+		}
+		r.position += n
+		read += n
+	}
+	if read == 0 && r.err != io.EOF {
+		return 0, r.err
+	}
+	return read, nil
+}
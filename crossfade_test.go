@@ -0,0 +1,68 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestSourceCrossfade(t *testing.T) {
+	constant := func(value float64, frames int) *bytes.Reader {
+		samples := make([]float64, frames)
+		for i := range samples {
+			samples[i] = value
+		}
+		var b buffer
+		transcode(t, floatSource(1000, 1, samples), wav.Sink(&b, signal.BitDepth16))
+		return bytes.NewReader(b.data)
+	}
+
+	tests := []struct {
+		name     string
+		lengths  []int
+		expected int
+		fade     int
+	}{
+		{
+			name:     "full fade",
+			lengths:  []int{100, 100, 100},
+			expected: 260,
+			fade:     20,
+		},
+		{
+			name:     "short file",
+			lengths:  []int{100, 10},
+			expected: 100,
+			fade:     10,
+		},
+	}
+	for _, test := range tests {
+		sources := []io.ReadSeeker{constant(0.5, test.lengths[0])}
+		for _, l := range test.lengths[1:] {
+			sources = append(sources, constant(0.25, l))
+		}
+		var out buffer
+		transcode(t,
+			wav.SourceCrossfade(20*time.Millisecond, sources...),
+			wav.Sink(&out, signal.BitDepth16),
+		)
+		samples := int16Samples(out.data)
+		if len(samples) != test.expected {
+			t.Fatalf("%s: expected %d frames got %d", test.name, test.expected, len(samples))
+		}
+		start := test.lengths[0] - test.fade
+		for i := 0; i < test.fade; i++ {
+			phase := (float64(i) + 0.5) / float64(test.fade) * math.Pi / 2
+			expected := 0.5*math.Cos(phase) + 0.25*math.Sin(phase)
+			if result := float64(samples[start+i]) / 32767; math.Abs(result-expected) > 1e-3 {
+				t.Errorf("%s: frame %d expected %v got %v", test.name, start+i, expected, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package wav
+
+import (
+	"pipelined.dev/signal"
+)
+
+const (
+	// minNormal is the smallest positive normal float64 value.
+	minNormal = 0x1p-1022
+	// minNormal32 is the smallest positive normal float32 value.
+	minNormal32 = 0x1p-126
+)
+
+// WithFlushDenormals makes Source flush denormal samples to zero after
+// decoding. Processing of denormals is very slow on some CPUs, so
+// real-time pipelines can avoid it at the cost of a comparison per
+// sample. Samples of 32-bit float streams are flushed if they're denormal
+// as float32 values. Denormals are never flushed by default.
+func WithFlushDenormals() Option {
+	return func(o *options) {
+		o.flushDenormals = true
+	}
+}
+
+// flushDenormals replaces denormals of provided number of frames with zero
+// in place.
+func (r *Reader) flushDenormals(floats signal.Floating, frames int) {
+	if !r.denormals {
+		return
+	}
+	min := minNormal
+	if r.float != nil && r.format.BitDepth == signal.BitDepth32 {
+		min = minNormal32
+	}
+	for i := 0; i < frames*floats.Channels(); i++ {
+		if v := floats.Sample(i); v != 0 && v < min && v > -min {
+			floats.SetSample(i, 0)
+		}
+	}
+}
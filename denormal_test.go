@@ -0,0 +1,81 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestWithFlushDenormals(t *testing.T) {
+	// attenuation makes the smallest 32-bit samples denormal.
+	pcm := []byte{0xFF, 0xFF, 0xFF, 0x7F, 0x01, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}
+	data := riff(chunk("fmt ", fmtPayload(1, 32, 4, 48000)), chunk("data", pcm))
+
+	r, err := wav.NewReader(bytes.NewReader(data), wav.WithHeadroom(-6000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	samples := readAll(t, r)
+	if samples[1] == 0 || samples[2] == 0 {
+		t.Fatalf("expected denormal samples by default got %v", samples)
+	}
+
+	r, err = wav.NewReader(bytes.NewReader(data), wav.WithHeadroom(-6000), wav.WithFlushDenormals())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	flushed := readAll(t, r)
+	if flushed[0] != samples[0] || flushed[1] != 0 || flushed[2] != 0 {
+		t.Errorf("expected denormals flushed to zero got %v", flushed)
+	}
+}
+
+func TestWithFlushDenormalsFloat(t *testing.T) {
+	// denormal samples of float32 and float64 values.
+	samples32 := []float32{0.5, 1e-40, -1e-42, -0.25}
+	pcm32 := make([]byte, 4*len(samples32))
+	for i, v := range samples32 {
+		binary.LittleEndian.PutUint32(pcm32[4*i:], math.Float32bits(v))
+	}
+	samples64 := []float64{0.5, 1e-310, -5e-324, -0.25}
+	pcm64 := make([]byte, 8*len(samples64))
+	for i, v := range samples64 {
+		binary.LittleEndian.PutUint64(pcm64[8*i:], math.Float64bits(v))
+	}
+	format := fmtPayload(1, 32, 4, 48000)
+	binary.LittleEndian.PutUint16(format, 3)
+	for _, test := range []struct {
+		data     []byte
+		denormal []float64
+	}{
+		{
+			data:     riff(chunk("fmt ", format), chunk("data", pcm32)),
+			denormal: []float64{0.5, float64(samples32[1]), float64(samples32[2]), -0.25},
+		},
+		{
+			data:     float64Stream(pcm64),
+			denormal: samples64,
+		},
+	} {
+		r, err := wav.NewReader(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bitDepth := r.Format().BitDepth
+		if samples := readAll(t, r); !reflect.DeepEqual(samples, test.denormal) {
+			t.Errorf("%d bits: expected denormal samples by default got %v", bitDepth, samples)
+		}
+
+		r, err = wav.NewReader(bytes.NewReader(test.data), wav.WithFlushDenormals())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if flushed := readAll(t, r); !reflect.DeepEqual(flushed, []float64{0.5, 0, 0, -0.25}) {
+			t.Errorf("%d bits: expected denormals flushed to zero got %v", bitDepth, flushed)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package wav
+
+import (
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// tolerance of the distance between a sample and the nearest level of
+// quantization grid, in levels.
+const depthTolerance = 1e-6
+
+// WarnUpconversion makes Sink report a warning when its bit depth exceeds
+// the effective bit depth of input samples, e.g. 8-bit source is written
+// as 24-bit. The effective bit depth is the lowest supported bit depth
+// that represents every sample exactly. The warning is purely
+// informational, the data is written as usual. It's appended on Close to
+// the slice provided with WithWarnings.
+func WarnUpconversion() Option {
+	return func(o *options) {
+		o.upconversion = true
+	}
+}
+
+// depthDetector finds the effective bit depth of samples.
+type depthDetector struct {
+	bitDepth signal.BitDepth
+	// candidate bit depths below the output one and whether every sample
+	// so far fits them.
+	candidates []signal.BitDepth
+	fits       []bool
+	// nonZero is true if any sample isn't silent.
+	nonZero bool
+	warn    func(offset int64, format string, args ...interface{})
+}
+
+// newDepthDetector returns a detector of input depths lower than
+// provided output bit depth.
+func (o *options) newDepthDetector(bitDepth signal.BitDepth) *depthDetector {
+	d := depthDetector{
+		bitDepth: bitDepth,
+		warn:     o.warn,
+	}
+	for _, candidate := range supportedBitDepths {
+		if candidate < bitDepth {
+			d.candidates = append(d.candidates, candidate)
+			d.fits = append(d.fits, true)
+		}
+	}
+	return &d
+}
+
+// update checks the samples of provided buffer against every candidate.
+func (d *depthDetector) update(floats signal.Floating) {
+	if d == nil {
+		return
+	}
+	for i := 0; i < floats.Len(); i++ {
+		v := floats.Sample(i)
+		if v == 0 {
+			continue
+		}
+		d.nonZero = true
+		for c, candidate := range d.candidates {
+			if d.fits[c] && !onGrid(v, candidate) {
+				d.fits[c] = false
+			}
+		}
+	}
+}
+
+// report warns about the lowest bit depth that fits all samples.
+func (d *depthDetector) report() {
+	if d == nil || !d.nonZero {
+		return
+	}
+	for c, candidate := range d.candidates {
+		if d.fits[c] {
+			d.warn(0, "output bit depth %d exceeds effective input bit depth %d", d.bitDepth, candidate)
+			return
+		}
+	}
+}
+
+// onGrid returns true if the sample is a level of fixed-point samples
+// with provided bit depth. Decoders scale the samples either by maximum
+// signed value or by the one above it, so both grids are accepted.
+func onGrid(v float64, bitDepth signal.BitDepth) bool {
+	msv := float64(bitDepth.MaxSignedValue())
+	return isLevel(v*msv) || isLevel(v*(msv+1))
+}
+
+// isLevel returns true if the scaled sample is an integer level.
+func isLevel(v float64) bool {
+	return math.Abs(v-math.Round(v)) < depthTolerance
+}
@@ -0,0 +1,83 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestWarnUpconversion(t *testing.T) {
+	// 8-bit unsigned samples with both positive and negative levels.
+	data8 := riff(
+		chunk("fmt ", fmtPayload(1, 8, 1, 44100)),
+		chunk("data", []byte{128, 255, 0, 1, 200, 60, 129, 127}),
+	)
+	var data16 buffer
+	transcode(t, wav.Source(bytes.NewReader(data8)), wav.Sink(&data16, signal.BitDepth16))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		bitDepth signal.BitDepth
+		options  []wav.Option
+		expected []string
+	}{
+		{
+			name:     "8 to 24 bits",
+			data:     data8,
+			bitDepth: signal.BitDepth24,
+			options:  []wav.Option{wav.WarnUpconversion()},
+			expected: []string{"output bit depth 24 exceeds effective input bit depth 8"},
+		},
+		{
+			name:     "16 to 32 bits",
+			data:     data16.data,
+			bitDepth: signal.BitDepth32,
+			options:  []wav.Option{wav.WarnUpconversion()},
+			expected: []string{"output bit depth 32 exceeds effective input bit depth 16"},
+		},
+		{
+			name:     "same bit depth",
+			data:     data8,
+			bitDepth: signal.BitDepth8,
+			options:  []wav.Option{wav.WarnUpconversion()},
+		},
+		{
+			name:     "full resolution",
+			data:     riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", rampData(1000))),
+			bitDepth: signal.BitDepth16,
+			options:  []wav.Option{wav.WarnUpconversion()},
+		},
+		{
+			name:     "silence",
+			data:     riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", make([]byte, 20))),
+			bitDepth: signal.BitDepth24,
+			options:  []wav.Option{wav.WarnUpconversion()},
+		},
+		{
+			name:     "disabled",
+			data:     data8,
+			bitDepth: signal.BitDepth24,
+		},
+	}
+	for _, test := range tests {
+		var (
+			warnings []wav.Warning
+			out      buffer
+		)
+		options := append(test.options, wav.WithWarnings(&warnings))
+		transcode(t, wav.Source(bytes.NewReader(test.data)), wav.Sink(&out, test.bitDepth, options...))
+		if len(warnings) != len(test.expected) {
+			t.Errorf("%s: expected warnings %v got %v", test.name, test.expected, warnings)
+			continue
+		}
+		for i := range warnings {
+			if warnings[i].Message != test.expected[i] {
+				t.Errorf("%s: expected warning %q got %q", test.name, test.expected[i], warnings[i].Message)
+			}
+		}
+	}
+}
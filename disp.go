@@ -0,0 +1,48 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// dispID is the identifier of the chunk with display data.
+var dispID = [4]byte{'D', 'I', 'S', 'P'}
+
+// dispText is the CF_TEXT clipboard format of DISP chunk.
+const dispText = 1
+
+// WithDisp makes Sink write provided title as text DISP chunk before
+// data. Windows shows it as the display name of the sound. Empty title is
+// not written.
+func WithDisp(title string) Option {
+	return func(o *options) {
+		o.disp = title
+	}
+}
+
+// ReadDisp returns the text of DISP chunk. Empty string is returned if
+// the stream doesn't have DISP chunk or it has other than text type, e.g.
+// an icon. The stream is rewinded to the start afterwards.
+func ReadDisp(rs io.ReadSeeker) (string, error) {
+	payload, ok, err := ReadChunk(rs, dispID)
+	if err != nil || !ok {
+		return "", err
+	}
+	if len(payload) < 4 || binary.LittleEndian.Uint32(payload) != dispText {
+		return "", nil
+	}
+	text := payload[4:]
+	if i := bytes.IndexByte(text, 0); i != -1 {
+		text = text[:i]
+	}
+	return string(text), nil
+}
+
+// encodeDisp returns the payload of text DISP chunk.
+func encodeDisp(title string) []byte {
+	p := make([]byte, 4, 4+len(title)+1)
+	binary.LittleEndian.PutUint32(p, dispText)
+	p = append(p, title...)
+	return append(p, 0)
+}
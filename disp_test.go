@@ -0,0 +1,36 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestDisp(t *testing.T) {
+	var out buffer
+	transcode(t, floatSource(8000, 1, []float64{0, 0.5}), wav.Sink(&out, signal.BitDepth16, wav.WithDisp("Door bell")))
+	if ids := chunkIDs(out.data); len(ids) != 3 || ids[1] != "DISP" {
+		t.Errorf("unexpected chunks %v", ids)
+	}
+	title, err := wav.ReadDisp(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title != "Door bell" {
+		t.Errorf("unexpected title %q", title)
+	}
+
+	// absent chunk and other types are empty.
+	for _, data := range [][]byte{
+		riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)), chunk("data", nil)),
+		riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)), chunk("DISP", []byte{8, 0, 0, 0, 1, 2}), chunk("data", nil)),
+	} {
+		title, err := wav.ReadDisp(bytes.NewReader(data))
+		if err != nil || title != "" {
+			t.Errorf("expected empty title got %q: %v", title, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WithEmbedded makes Source read wav data embedded into a larger stream,
+// e.g. an archive, at provided byte offset. The offset is treated as the
+// start of RIFF header. Positive length bounds the embedded data, zero
+// length means the data lasts until the end of stream. The embedded data
+// isn't copied.
+func WithEmbedded(offset, length int64) Option {
+	return func(o *options) {
+		o.embedded = &[2]int64{offset, length}
+	}
+}
+
+// EmbeddedReader is io.ReadSeeker of the section of another ReadSeeker.
+// Positions are relative to the start of the section.
+type EmbeddedReader struct {
+	rs     io.ReadSeeker
+	start  int64
+	length int64
+	offset int64
+}
+
+// NewEmbeddedReader returns a new reader of the section of provided
+// stream that starts at offset. Positive length bounds the section, zero
+// length means the section lasts until the end of stream.
+func NewEmbeddedReader(rs io.ReadSeeker, offset, length int64) *EmbeddedReader {
+	return &EmbeddedReader{
+		rs:     rs,
+		start:  offset,
+		length: length,
+	}
+}
+
+// embed returns the reader of the section provided with WithEmbedded.
+func (o *options) embed(rs io.ReadSeeker) (io.ReadSeeker, error) {
+	offset, length := o.embedded[0], o.embedded[1]
+	if offset < 0 || length < 0 {
+		return nil, fmt.Errorf("invalid embedded section at %d of %d bytes", offset, length)
+	}
+	return NewEmbeddedReader(rs, offset, length), nil
+}
+
+// Read implements io.Reader.
+func (r *EmbeddedReader) Read(p []byte) (int, error) {
+	if r.length > 0 {
+		if r.offset >= r.length {
+			return 0, io.EOF
+		}
+		if remaining := r.length - r.offset; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	if _, err := r.rs.Seek(r.start+r.offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := r.rs.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (r *EmbeddedReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		size, err := r.size()
+		if err != nil {
+			return r.offset, err
+		}
+		offset += size
+	default:
+		return r.offset, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return r.offset, errors.New("negative position")
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// size returns the size of the section.
+func (r *EmbeddedReader) size() (int64, error) {
+	end, err := r.rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	size := end - r.start
+	if size < 0 {
+		size = 0
+	}
+	if r.length > 0 && r.length < size {
+		size = r.length
+	}
+	return size, nil
+}
@@ -0,0 +1,59 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestWithEmbedded(t *testing.T) {
+	embedded := riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", rampData(10)))
+	prefix := bytes.Repeat([]byte{0xAB}, 101)
+	archive := append(append(append([]byte(nil), prefix...), embedded...), []byte("next archive entry")...)
+
+	r, err := wav.NewReader(bytes.NewReader(archive), wav.WithEmbedded(int64(len(prefix)), int64(len(embedded))))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := readAll(t, mustReader(t, embedded))
+	if samples := readAll(t, r); !reflect.DeepEqual(samples, expected) {
+		t.Errorf("expected %v got %v", expected, samples)
+	}
+
+	// unbounded section lasts until the end of stream.
+	var out buffer
+	transcode(t, wav.Source(bytes.NewReader(append(prefix, embedded...)), wav.WithEmbedded(int64(len(prefix)), 0)), wav.Sink(&out, 16))
+	if !bytes.Equal(out.data, embedded) {
+		t.Errorf("unexpected output of unbounded section")
+	}
+
+	if _, err := wav.NewReader(bytes.NewReader(archive), wav.WithEmbedded(-1, 0)); err == nil {
+		t.Errorf("expected error for negative offset")
+	}
+	if _, err := wav.NewReader(bytes.NewReader(archive), wav.WithEmbedded(0, 0)); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %v", err)
+	}
+
+	er := wav.NewEmbeddedReader(bytes.NewReader(archive), int64(len(prefix)), int64(len(embedded)))
+	if size, err := er.Seek(0, io.SeekEnd); err != nil || size != int64(len(embedded)) {
+		t.Errorf("expected size %d got %d: %v", len(embedded), size, err)
+	}
+	er.Seek(0, io.SeekStart)
+	if data, err := ioutil.ReadAll(er); err != nil || !bytes.Equal(data, embedded) {
+		t.Errorf("unexpected section: %v", err)
+	}
+}
+
+// mustReader returns the reader of provided stream.
+func mustReader(t *testing.T, data []byte) *wav.Reader {
+	t.Helper()
+	r, err := wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return r
+}
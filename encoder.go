@@ -0,0 +1,162 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+// encoder writes wav stream with go-audio encoder. The header is written
+// before the first buffer, so it can contain chunks between fmt and data.
+type encoder struct {
+	*wav.Encoder
+	ws          io.WriteSeeker
+	format      Format
+	bext        *Bext
+	reserveRF64 bool
+	formType    [4]byte
+	fmtPayload  []byte
+	leading     []rawChunk
+	trailing    []rawChunk
+	dataStarted bool
+	// offset of bext payload, it's updated on flush.
+	bextOffset int64
+	// offset of data payload.
+	dataOffset int64
+	// wave list of data and slnt chunks, nil if data is a single chunk.
+	waveList *waveList
+}
+
+// newEncoder returns encoder configured by Sink options.
+func (o *options) newEncoder(ws io.WriteSeeker, f Format) *encoder {
+	return &encoder{
+		Encoder: wav.NewEncoder(
+			ws,
+			int(f.SampleRate),
+			8*o.bytesPerSample(f),
+			f.Channels,
+			wavOutFormat,
+		),
+		ws:          ws,
+		format:      f,
+		bext:        o.bext,
+		reserveRF64: o.reserveRF64,
+		formType:    o.riffFormType(),
+		fmtPayload:  o.formatPayload(f),
+		leading:     o.leadingChunks(f),
+		trailing:    o.trailingChunks(),
+		waveList:    o.newWaveList(f),
+	}
+}
+
+// write writes the buffer of PCM data. The header is written before the
+// first buffer.
+func (e *encoder) write(pcm *audio.IntBuffer) error {
+	if !e.dataStarted {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+		e.dataStarted = true
+		if e.waveList != nil {
+			if err := e.waveList.start(e); err != nil {
+				return err
+			}
+		}
+		// data chunk header is written by go-audio encoder.
+		e.dataOffset = int64(e.WrittenBytes) + 8
+	}
+	if e.waveList != nil {
+		// go-audio encoder writes a single data chunk.
+		return e.waveList.write(e, pcm.Data, e.BitDepth/8)
+	}
+	// go-audio encoder doesn't write its own header if something was
+	// already written.
+	return e.Write(pcm)
+}
+
+// writeHeader writes RIFF header, ds64 placeholder, fmt chunk and leading
+// chunks.
+func (e *encoder) writeHeader() error {
+	if err := e.AddLE(riffID); err != nil {
+		return fmt.Errorf("error writing RIFF header: %w", err)
+	}
+	// size is updated when encoder is closed.
+	if err := e.AddLE(uint32(0)); err != nil {
+		return fmt.Errorf("error writing RIFF header: %w", err)
+	}
+	if err := e.AddLE(e.formType); err != nil {
+		return fmt.Errorf("error writing RIFF header: %w", err)
+	}
+	// placeholder must be the first chunk to be replaced by ds64.
+	if e.reserveRF64 {
+		if err := writeChunk(e.Encoder, rawChunk{ID: junkID, Payload: make([]byte, ds64Size)}); err != nil {
+			return err
+		}
+	}
+	if err := writeChunk(e.Encoder, rawChunk{ID: fmtID, Payload: e.fmtPayload}); err != nil {
+		return err
+	}
+	if e.bext != nil {
+		e.bextOffset = int64(e.WrittenBytes) + 8
+		if err := writeChunk(e.Encoder, rawChunk{ID: bextID, Payload: e.bext.encode()}); err != nil {
+			return err
+		}
+	}
+	for _, c := range e.leading {
+		if err := writeChunk(e.Encoder, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close writes trailing chunks and closes go-audio encoder.
+func (e *encoder) close() error {
+	// write empty data chunk if no buffers were received.
+	if !e.dataStarted {
+		empty := audio.IntBuffer{
+			Format: &audio.Format{
+				NumChannels: e.format.Channels,
+				SampleRate:  int(e.format.SampleRate),
+			},
+		}
+		if err := e.write(&empty); err != nil {
+			return fmt.Errorf("error writing PCM buffer: %w", err)
+		}
+	}
+	if e.waveList != nil {
+		if err := e.waveList.close(e); err != nil {
+			return err
+		}
+	}
+	if e.bext != nil {
+		if err := e.updateBext(); err != nil {
+			return err
+		}
+	}
+	for _, c := range e.trailing {
+		if err := writeChunk(e.Encoder, c); err != nil {
+			return err
+		}
+	}
+	if err := e.Close(); err != nil {
+		return fmt.Errorf("error flushing WAV encoder: %w", err)
+	}
+	return nil
+}
+
+// updateBext overwrites fixed fields of bext chunk.
+func (e *encoder) updateBext() error {
+	if _, err := e.ws.Seek(e.bextOffset, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking bext chunk: %w", err)
+	}
+	if _, err := e.ws.Write(e.bext.encode()[:bextFixedSize]); err != nil {
+		return fmt.Errorf("error updating bext chunk: %w", err)
+	}
+	if _, err := e.ws.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("error seeking stream end: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package wav
+
+// EventKind is the kind of lifecycle event of Source and Sink.
+type EventKind int
+
+const (
+	// EventStart is emitted when Source or Sink is allocated.
+	EventStart EventKind = iota
+	// EventChunk is emitted for every chunk parsed by Source.
+	EventChunk
+	// EventBuffer is emitted after every buffer is decoded or encoded.
+	EventBuffer
+	// EventEnd is emitted when Source reaches the end of stream or Sink
+	// is flushed.
+	EventEnd
+)
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventStart:
+		return "start"
+	case EventChunk:
+		return "chunk"
+	case EventBuffer:
+		return "buffer"
+	case EventEnd:
+		return "end"
+	}
+	return "unknown"
+}
+
+// Component is the name of component that emitted the event.
+type Component string
+
+// Components that emit events.
+const (
+	ComponentSource Component = "source"
+	ComponentSink   Component = "sink"
+)
+
+// Event is a lifecycle event of Source or Sink. Only the fields relevant
+// to the kind are set.
+type Event struct {
+	Kind      EventKind
+	Component Component
+	// Format of the stream, set for all events.
+	Format Format
+	// ChunkID, ChunkSize and Offset of the chunk payload are set for
+	// chunk events.
+	ChunkID   [4]byte
+	ChunkSize uint32
+	Offset    int64
+	// Buffer is the number of the buffer starting from 1, set for buffer
+	// events.
+	Buffer int
+	// Frames is the number of frames of the buffer for buffer events and
+	// the total number of frames of all buffers for end events. Silence
+	// written by Sink isn't counted.
+	Frames int64
+	// Padding is the number of silent frames that follow the frames of
+	// the buffer, set for buffer events of Source with PadFinalBuffer.
+	// Padding isn't counted in Frames.
+	Padding int
+}
+
+// EventFunc receives lifecycle events.
+type EventFunc func(Event)
+
+// WithEvents makes Source and Sink call provided function on lifecycle
+// events. The function is called from the goroutine of the component.
+func WithEvents(fn EventFunc) Option {
+	return func(o *options) {
+		o.events = fn
+	}
+}
+
+// emitter emits events of a single component. Nil emitter does nothing.
+type emitter struct {
+	fn        EventFunc
+	component Component
+	format    Format
+	buffers   int
+	frames    int64
+	ended     bool
+}
+
+// newEmitter returns emitter of the component or nil if events are
+// disabled.
+func (o *options) newEmitter(component Component) *emitter {
+	if o.events == nil {
+		return nil
+	}
+	return &emitter{fn: o.events, component: component}
+}
+
+func (e *emitter) emit(event Event) {
+	event.Component = e.component
+	event.Format = e.format
+	e.fn(event)
+}
+
+// start emits start event with the format of the stream.
+func (e *emitter) start(f Format) {
+	if e == nil {
+		return
+	}
+	e.format = f
+	e.emit(Event{Kind: EventStart})
+}
+
+// chunks emits the events of container chunks.
+func (e *emitter) chunks(c container) {
+	if e == nil {
+		return
+	}
+	for _, h := range c.chunks {
+		e.emit(Event{Kind: EventChunk, ChunkID: h.ID, ChunkSize: h.Size, Offset: h.Offset})
+	}
+}
+
+// buffer emits buffer event.
+func (e *emitter) buffer(frames int) {
+	e.paddedBuffer(frames, 0)
+}
+
+// paddedBuffer emits buffer event of the frames followed by silence.
+func (e *emitter) paddedBuffer(frames, padding int) {
+	if e == nil {
+		return
+	}
+	e.buffers++
+	e.frames += int64(frames)
+	e.emit(Event{Kind: EventBuffer, Buffer: e.buffers, Frames: int64(frames), Padding: padding})
+}
+
+// end emits end event once.
+func (e *emitter) end() {
+	if e == nil || e.ended {
+		return
+	}
+	e.ended = true
+	e.emit(Event{Kind: EventEnd, Frames: e.frames})
+}
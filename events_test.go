@@ -0,0 +1,55 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestEvents(t *testing.T) {
+	const frames = 2*bufferSize + 10
+	samples := make([]float64, frames)
+	var sinkEvents, sourceEvents []wav.Event
+	var out buffer
+	transcode(t,
+		floatSource(8000, 1, samples),
+		wav.Sink(&out, signal.BitDepth16, wav.WithEvents(func(e wav.Event) { sinkEvents = append(sinkEvents, e) })),
+	)
+	transcode(t,
+		wav.Source(bytes.NewReader(out.data), wav.WithEvents(func(e wav.Event) { sourceEvents = append(sourceEvents, e) })),
+		wav.Sink(&buffer{}, signal.BitDepth16),
+	)
+
+	format := wav.Format{SampleRate: 8000, Channels: 1, BitDepth: signal.BitDepth16}
+	buffers := []wav.Event{
+		{Kind: wav.EventBuffer, Format: format, Buffer: 1, Frames: bufferSize},
+		{Kind: wav.EventBuffer, Format: format, Buffer: 2, Frames: bufferSize},
+		{Kind: wav.EventBuffer, Format: format, Buffer: 3, Frames: 10},
+	}
+	expected := append([]wav.Event{{Kind: wav.EventStart, Format: format}}, buffers...)
+	expected = append(expected, wav.Event{Kind: wav.EventEnd, Format: format, Frames: frames})
+	for i := range expected {
+		expected[i].Component = wav.ComponentSink
+	}
+	if !reflect.DeepEqual(expected, sinkEvents) {
+		t.Errorf("unexpected sink events:\n%+v\ngot:\n%+v", expected, sinkEvents)
+	}
+
+	expected = []wav.Event{
+		{Kind: wav.EventStart, Format: format},
+		{Kind: wav.EventChunk, Format: format, ChunkID: [4]byte{'f', 'm', 't', ' '}, ChunkSize: 16, Offset: 20},
+		{Kind: wav.EventChunk, Format: format, ChunkID: [4]byte{'d', 'a', 't', 'a'}, ChunkSize: 2 * frames, Offset: 44},
+	}
+	expected = append(expected, buffers...)
+	expected = append(expected, wav.Event{Kind: wav.EventEnd, Format: format, Frames: frames})
+	for i := range expected {
+		expected[i].Component = wav.ComponentSource
+	}
+	if !reflect.DeepEqual(expected, sourceEvents) {
+		t.Errorf("unexpected source events:\n%+v\ngot:\n%+v", expected, sourceEvents)
+	}
+}
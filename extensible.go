@@ -0,0 +1,87 @@
+package wav
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// extensibleSize is the size of fmt extension of extensible format.
+const extensibleSize = 22
+
+// Sub-format GUIDs of extensible format in the byte order of the stream.
+var (
+	SubFormatPCM   = [16]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}
+	SubFormatFloat = [16]byte{0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}
+)
+
+// Extensible is the extension of WAVE_FORMAT_EXTENSIBLE fmt chunk.
+type Extensible struct {
+	// ValidBits is the number of meaningful bits of every sample. Zero
+	// means the whole sample.
+	ValidBits uint16
+	// ChannelMask is the bit mask of speaker positions.
+	ChannelMask uint32
+	// SubFormat GUID is kept in the byte order of the stream, so
+	// non-standard GUIDs are written back exactly as they were read.
+	SubFormat [16]byte
+}
+
+// ReadExtensible returns the extension of extensible fmt chunk. Nil is
+// returned if the stream has other format or the extension is missing,
+// e.g. cbSize field is zero. The stream is rewinded to the
+// start afterwards.
+func ReadExtensible(rs io.ReadSeeker) (*Extensible, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return nil, err
+	}
+	f, err := readFormat(rs, c)
+	if err != nil {
+		return nil, err
+	}
+	if !f.extended() {
+		return nil, nil
+	}
+	e := Extensible{
+		ValidBits:   binary.LittleEndian.Uint16(f.Extension[0:]),
+		ChannelMask: binary.LittleEndian.Uint32(f.Extension[2:]),
+	}
+	copy(e.SubFormat[:], f.Extension[6:extensibleSize])
+	return &e, nil
+}
+
+// WithExtensible makes Sink write extensible fmt chunk with provided
+// extension. The sub-format must match the samples: SubFormatFloat is
+// required for Float32 samples and SubFormatPCM for integer ones, unless
+// the stream is meant for a specific consumer.
+func WithExtensible(e *Extensible) Option {
+	return func(o *options) {
+		o.extensible = e
+	}
+}
+
+// formatPayload returns the payload of fmt chunk that Sink writes.
+func (o *options) formatPayload(f Format) []byte {
+	if o.extensible == nil {
+		payload := make([]byte, 16)
+		putFormat(payload, f)
+		o.putContainer(payload, f)
+		if o.float {
+			putFloatFormat(payload)
+		}
+		return payload
+	}
+	payload := make([]byte, 18+extensibleSize)
+	putFormat(payload, f)
+	o.putContainer(payload, f)
+	validBits := o.extensible.ValidBits
+	if validBits == 0 {
+		validBits = uint16(f.BitDepth)
+	}
+	binary.LittleEndian.PutUint16(payload[0:], formatExtensible)
+	binary.LittleEndian.PutUint16(payload[16:], extensibleSize)
+	binary.LittleEndian.PutUint16(payload[18:], validBits)
+	binary.LittleEndian.PutUint32(payload[20:], o.extensible.ChannelMask)
+	copy(payload[24:], o.extensible.SubFormat[:])
+	return payload
+}
@@ -0,0 +1,130 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+const (
+	wavExtensibleCB0  = "_testdata/extensible_cb0.wav"
+	wavExtensibleCB22 = "_testdata/extensible_cb22.wav"
+)
+
+func TestExtensible(t *testing.T) {
+	// sub-format GUID of a vendor-specific format.
+	guid := [16]byte{0x78, 0x56, 0x34, 0x12, 0xBC, 0x9A, 0xF0, 0xDE, 0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	payload := fmtPayload(2, 16, 4, 48000)
+	binary.LittleEndian.PutUint16(payload[0:], 0xFFFE)
+	extension := make([]byte, 22)
+	binary.LittleEndian.PutUint16(extension[0:], 16)
+	binary.LittleEndian.PutUint32(extension[2:], 0x3)
+	copy(extension[6:], guid[:])
+	payload = append(append(payload, 22, 0), extension...)
+	pcm := make([]byte, 40)
+	for i := range pcm {
+		pcm[i] = byte(i)
+	}
+	data := riff(chunk("fmt ", payload), chunk("data", pcm))
+
+	ext, err := wav.ReadExtensible(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &wav.Extensible{ValidBits: 16, ChannelMask: 0x3, SubFormat: guid}
+	if !reflect.DeepEqual(ext, expected) {
+		t.Fatalf("expected %+v got %+v", expected, ext)
+	}
+
+	var out buffer
+	transcode(t, wav.Source(bytes.NewReader(data)), wav.Sink(&out, signal.BitDepth16, wav.WithExtensible(ext)))
+	if ids := chunkIDs(out.data); ids[0] != "fmt " || binary.LittleEndian.Uint32(out.data[16:]) != 40 {
+		t.Fatalf("unexpected fmt chunk %v", out.data[12:20])
+	}
+	if !bytes.Equal(out.data[20:60], payload) {
+		t.Errorf("fmt chunk isn't preserved:\n%x\n%x", payload, out.data[20:60])
+	}
+	if !bytes.Equal(out.data[68:], pcm) {
+		t.Errorf("samples aren't preserved")
+	}
+	result, err := wav.ReadExtensible(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v got %+v", expected, result)
+	}
+
+	// PCM format has no extension.
+	out = buffer{}
+	transcode(t, floatSource(48000, 2, []float64{0.5, -0.5}), wav.Sink(&out, signal.BitDepth16))
+	if result, err := wav.ReadExtensible(bytes.NewReader(out.data)); err != nil || result != nil {
+		t.Errorf("unexpected extension %+v: %v", result, err)
+	}
+}
+
+func TestExtensibleCBSize(t *testing.T) {
+	// both fixtures have the same 24-bit stereo samples.
+	var expected []float64
+	for _, test := range []struct {
+		path      string
+		extension *wav.Extensible
+		warning   string
+	}{
+		{
+			path:      wavExtensibleCB22,
+			extension: &wav.Extensible{ValidBits: 24, ChannelMask: 0x3, SubFormat: wav.SubFormatPCM},
+		},
+		{
+			path:    wavExtensibleCB0,
+			warning: "extensible format has 0 bytes of extension instead of 22, decoded as 24-bit PCM",
+		},
+	} {
+		f, err := os.Open(test.path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer f.Close()
+
+		ext, err := wav.ReadExtensible(f)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.path, err)
+		}
+		if !reflect.DeepEqual(ext, test.extension) {
+			t.Errorf("%s: expected extension %+v got %+v", test.path, test.extension, ext)
+		}
+		info, err := wav.Probe(f)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.path, err)
+		}
+		if info.BitDepth != signal.BitDepth24 || info.ValidBits != 24 || info.Frames != 48 {
+			t.Errorf("%s: unexpected info %+v", test.path, info)
+		}
+
+		var warnings []wav.Warning
+		r, err := wav.NewReader(f, wav.WithWarnings(&warnings))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.path, err)
+		}
+		switch {
+		case test.warning == "" && len(warnings) != 0:
+			t.Errorf("%s: unexpected warnings %v", test.path, warnings)
+		case test.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0].Message, test.warning)):
+			t.Errorf("%s: expected warning %q got %v", test.path, test.warning, warnings)
+		}
+		samples := readAll(t, r)
+		if expected == nil {
+			expected = samples
+		}
+		if len(samples) != 96 || !reflect.DeepEqual(samples, expected) {
+			t.Errorf("%s: unexpected samples %v", test.path, samples)
+		}
+	}
+}
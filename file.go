@@ -0,0 +1,114 @@
+package wav
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// SourceFile opens the wav file and returns the source that reads it.
+// The file is validated right away with provided options, so invalid
+// files are reported before the pipe is started. Returned close function
+// closes the file, it's safe to call it multiple times.
+func SourceFile(path string, options ...Option) (pipe.SourceAllocatorFunc, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening wav file: %w", err)
+	}
+	if err := validateSource(f, options); err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return Source(f, options...), closeOnce(f), nil
+}
+
+// validateSource checks that Source decodes the stream with provided
+// options and seeks the stream back to the start. Warnings, events and
+// chunks aren't reported, so they aren't duplicated when the stream is
+// decoded.
+func validateSource(rs io.ReadSeeker, options []Option) error {
+	o := newOptions(options)
+	o.warnings, o.events, o.chunkFunc = nil, nil, nil
+	if _, err := o.newReader(rs, 0); err != nil {
+		return err
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking wav file: %w", err)
+	}
+	return nil
+}
+
+// closeOnce returns a function that closes provided closer only once.
+func closeOnce(c io.Closer) func() error {
+	var (
+		once sync.Once
+		err  error
+	)
+	return func() error {
+		once.Do(func() {
+			err = c.Close()
+		})
+		return err
+	}
+}
+
+// SinkFile writes wav data to the file at provided path. Data is written
+// to the temporary file with ".tmp" suffix, which is renamed to the path
+// after successful flush. If the sink fails or the pipe is canceled, the
+// temporary file is removed, so partial files never appear at the path.
+func SinkFile(path string, bitDepth signal.BitDepth, options ...Option) pipe.SinkAllocatorFunc {
+	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
+		tmpPath := path + ".tmp"
+		f, err := os.Create(tmpPath)
+		if err != nil {
+			return pipe.Sink{}, fmt.Errorf("error creating temporary file: %w", err)
+		}
+		discard := func() {
+			f.Close()
+			os.Remove(tmpPath)
+		}
+
+		sink, err := Sink(f, bitDepth, options...)(mctx, bufferSize, props)
+		if err != nil {
+			discard()
+			return pipe.Sink{}, err
+		}
+
+		var failed bool
+		sinkFn := sink.SinkFunc
+		sink.SinkFunc = func(floats signal.Floating) error {
+			if err := sinkFn(floats); err != nil {
+				failed = true
+				return err
+			}
+			return nil
+		}
+		flushFn := sink.FlushFunc
+		sink.FlushFunc = func(ctx context.Context) error {
+			if failed || ctx.Err() != nil {
+				discard()
+				return nil
+			}
+			if err := flushFn(ctx); err != nil {
+				discard()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				os.Remove(tmpPath)
+				return fmt.Errorf("error closing temporary file: %w", err)
+			}
+			if err := os.Rename(tmpPath, path); err != nil {
+				os.Remove(tmpPath)
+				return fmt.Errorf("error renaming temporary file: %w", err)
+			}
+			return nil
+		}
+		return sink, nil
+	}
+}
@@ -0,0 +1,120 @@
+package wav_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestSourceFile(t *testing.T) {
+	source, closeFn, err := wav.SourceFile(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out buffer
+	transcode(t, source, wav.Sink(&out, signal.BitDepth16))
+	if err := closeFn(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+	if err := closeFn(); err != nil {
+		t.Errorf("unexpected second close error: %v", err)
+	}
+
+	if _, _, err := wav.SourceFile(notWav); !errors.Is(err, wav.ErrInvalidWav) {
+		t.Errorf("expected invalid wav error, got: %v", err)
+	}
+	if _, _, err := wav.SourceFile("_testdata/missing.wav"); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+
+	// file is validated with provided options.
+	if _, _, err := wav.SourceFile(wavSample, wav.WithMaxFrames(1)); !errors.Is(err, wav.ErrFrameLimit) {
+		t.Errorf("expected frame limit error, got: %v", err)
+	}
+	data, err := ioutil.ReadFile(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "wav")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "leading.wav")
+	if err := ioutil.WriteFile(path, append(make([]byte, 100), data...), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := wav.SourceFile(path); !errors.Is(err, wav.ErrInvalidWav) {
+		t.Errorf("expected invalid wav error for leading bytes, got: %v", err)
+	}
+	source, closeFn, err = wav.SourceFile(path, wav.SkipLeadingBytes(200))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closeFn()
+	var skipped buffer
+	transcode(t, source, wav.Sink(&skipped, signal.BitDepth16))
+	if !bytes.Equal(out.data, skipped.data) {
+		t.Errorf("file with leading bytes doesn't match")
+	}
+}
+
+func TestSinkFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wav")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out.wav")
+	transcode(t, floatSource(8000, 1, []float64{0, 0.5, 1}), wav.SinkFile(path, signal.BitDepth16))
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected temporary file to be renamed: %v", err)
+	}
+	source, closeFn, err := wav.SourceFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closeFn()
+	var out buffer
+	transcode(t, source, wav.Sink(&out, signal.BitDepth16))
+
+	// infinite source that is canceled.
+	canceled := filepath.Join(dir, "canceled.wav")
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Source: func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+			return pipe.Source{
+				SourceFunc: func(out signal.Floating) (int, error) {
+					return out.Length(), nil
+				},
+				SignalProperties: pipe.SignalProperties{
+					SampleRate: 8000,
+					Channels:   1,
+				},
+			}, nil
+		},
+		Sink: wav.SinkFile(canceled, signal.BitDepth16),
+	})
+	if err != nil {
+		t.Fatalf("unexpected pipe error: %v", err)
+	}
+	ctx, cancelFn := context.WithCancel(context.Background())
+	errc := p.Start(ctx)
+	cancelFn()
+	pipe.Wait(errc)
+	for _, name := range []string{canceled, canceled + ".tmp"} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// errFixedConversion is returned when fixed-point frames are read from
+// reader that converts decoded frames.
+var errFixedConversion = errors.New("fixed-point frames can't be mid/side decoded or resampled")
+
+// ReadSigned decodes frames into provided fixed-point buffer as they're
+// stored in the stream, without the conversion to floating-point. It
+// returns the number of frames read and io.EOF when the stream is done.
+// The buffer must have the same number of channels and bit depth as the
+// stream, and the stream must have signed samples: 8-bit samples are
+// signed only with Signed8Bit option. With WithInt32 option the buffer
+// must be 32-bit instead and any linear PCM stream is accepted.
+// BufferFunc and Analysis are not applied. Pipe lines carry only
+// floating-point buffers, so processors of pipe can't accept these
+// frames, this is meant for integrations that process integers on their
+// own.
+func (r *Reader) ReadSigned(dst signal.Signed) (int, error) {
+	if err := r.checkFixed(dst.Channels(), dst.BitDepth()); err != nil {
+		return 0, err
+	}
+	if r.justification != nil {
+		return r.fixedDone(r.readInt32(dst))
+	}
+	if r.unsignedSamples() {
+		return 0, errors.New("stream has unsigned samples")
+	}
+	var (
+		n   int
+		err error
+	)
+	if r.codec != nil {
+		n, err = r.codec.read(dst, dst.Length())
+	} else {
+		n, err = r.readSignedFixed(dst)
+	}
+	return r.fixedDone(n, err)
+}
+
+// ReadUnsigned decodes frames of 8-bit stream into provided fixed-point
+// buffer as ReadSigned does. The stream must have unsigned samples.
+func (r *Reader) ReadUnsigned(dst signal.Unsigned) (int, error) {
+	if r.justification != nil {
+		return 0, errors.New("32-bit frames are signed")
+	}
+	if err := r.checkFixed(dst.Channels(), dst.BitDepth()); err != nil {
+		return 0, err
+	}
+	if !r.unsignedSamples() {
+		return 0, errors.New("stream has signed samples")
+	}
+	read, err := r.readFixed(dst.Length())
+	if err != nil {
+		return r.fixedDone(0, err)
+	}
+	for i := 0; i < read; i++ {
+		dst.SetSample(i, uint64(r.pcm.Data[i]))
+	}
+	return r.fixedDone(signal.ChannelLength(read, r.format.Channels), nil)
+}
+
+// checkFixed returns an error if frames with provided properties can't be
+// read without conversion.
+func (r *Reader) checkFixed(channels int, bitDepth signal.BitDepth) error {
+	if channels != r.format.Channels {
+		return fmt.Errorf("buffer has %d channels instead of %d", channels, r.format.Channels)
+	}
+	if expected := r.fixedBitDepth(); bitDepth != expected {
+		return fmt.Errorf("buffer has bit depth %v instead of %v", bitDepth, expected)
+	}
+	if r.float != nil {
+		return errors.New("stream has float samples")
+	}
+	if r.midSide || r.resampler != nil {
+		return errFixedConversion
+	}
+	return nil
+}
+
+// fixedBitDepth returns the bit depth of fixed-point frames.
+func (r *Reader) fixedBitDepth() signal.BitDepth {
+	if r.justification != nil {
+		return signal.BitDepth32
+	}
+	return r.format.BitDepth
+}
+
+// readFixed reads up to provided number of frames into PCM buffer. It
+// returns the number of samples read.
+func (r *Reader) readFixed(frames int) (int, error) {
+	r.sizePCM(frames)
+	read, err := r.readPCM(&r.pcm)
+	if err != nil {
+		return 0, fmt.Errorf("error reading PCM buffer: %w", err)
+	}
+	if read == 0 {
+		return 0, io.EOF
+	}
+	return read, nil
+}
+
+func (r *Reader) readSignedFixed(dst signal.Signed) (int, error) {
+	read, err := r.readFixed(dst.Length())
+	if err != nil {
+		return 0, err
+	}
+	if r.signed8 && r.format.BitDepth == signal.BitDepth8 {
+		signed8Samples(r.pcm.Data[:read])
+	}
+	return signal.WriteInt(r.pcm.Data[:read], dst), nil
+}
+
+// fixedDone emits the events of fixed-point read.
+func (r *Reader) fixedDone(n int, err error) (int, error) {
+	if err != nil {
+		if err == io.EOF {
+			r.events.end()
+		}
+		return 0, err
+	}
+	r.events.buffer(n)
+	return n, nil
+}
+
+// WriteSigned encodes frames of provided fixed-point buffer as they are,
+// without the conversion from floating-point. It returns the number of
+// frames written, which is less than provided if exact length is reached.
+// The buffer must have the same number of channels and bit depth as the
+// stream, and the stream must have signed samples: 8-bit samples are
+// signed only with Signed8Bit option. BufferFunc isn't applied and
+// conversions of samples, such as channel gains, mid/side and float
+// samples, can't be used.
+func (w *Writer) WriteSigned(src signal.Signed) (int, error) {
+	if err := w.checkFixed(src.Channels(), src.BitDepth()); err != nil {
+		return 0, err
+	}
+	if w.unsignedSamples() {
+		return 0, errors.New("stream has unsigned samples")
+	}
+	n, data, err := w.fixedBuffer(src.Length())
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	signal.ReadInt(src.Slice(0, n), data)
+	return w.writeFixed(n, data)
+}
+
+// WriteUnsigned encodes frames of 8-bit fixed-point buffer as WriteSigned
+// does. The stream must have unsigned samples.
+func (w *Writer) WriteUnsigned(src signal.Unsigned) (int, error) {
+	if err := w.checkFixed(src.Channels(), src.BitDepth()); err != nil {
+		return 0, err
+	}
+	if !w.unsignedSamples() {
+		return 0, errors.New("stream has signed samples")
+	}
+	n, data, err := w.fixedBuffer(src.Length())
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	for i := range data {
+		data[i] = int(src.Sample(i))
+	}
+	return w.writeFixed(n, data)
+}
+
+// checkFixed returns an error if frames with provided properties can't be
+// written without conversion.
+func (w *Writer) checkFixed(channels int, bitDepth signal.BitDepth) error {
+	f := w.encoder.format
+	if channels != f.Channels {
+		return fmt.Errorf("buffer has %d channels instead of %d", channels, f.Channels)
+	}
+	if bitDepth != f.BitDepth {
+		return fmt.Errorf("buffer has bit depth %v instead of %v", bitDepth, f.BitDepth)
+	}
+	if w.float || w.quantizer.midSide || w.quantizer.gains != nil {
+		return errors.New("fixed-point frames can't be converted")
+	}
+	return nil
+}
+
+// fixedBuffer writes pre-roll and returns the number of frames that fit
+// the exact length with PCM buffer for them.
+func (w *Writer) fixedBuffer(frames int) (int, []int, error) {
+	if err := w.writePreroll(); err != nil {
+		return 0, nil, err
+	}
+	n := w.limit(frames)
+	channels := w.encoder.format.Channels
+	if n > len(w.pcm.Data)/channels {
+		w.allocate(n)
+	}
+	return n, w.pcm.Data[:n*channels], nil
+}
+
+// writeFixed encodes provided samples of n frames.
+func (w *Writer) writeFixed(n int, data []int) (int, error) {
+	if err := w.encode(data); err != nil {
+		return 0, err
+	}
+	w.frames += n
+	w.counters.update(w.frames, w.encoder.WrittenBytes)
+	w.events.buffer(n)
+	return n, w.checkpoint(n)
+}
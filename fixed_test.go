@@ -0,0 +1,132 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestReadSigned(t *testing.T) {
+	pcm := []byte{
+		0x01, 0x00, 0x00, 0xFF, 0xFF, 0xFF,
+		0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x80,
+		0x56, 0x34, 0x12, 0x00, 0x00, 0x00,
+	}
+	data := riff(chunk("fmt ", fmtPayload(2, 24, 6, 48000)), chunk("data", pcm))
+	r, err := wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 2, Length: 2, Capacity: 2}.Int32(signal.BitDepth24)
+	var samples []int32
+	for {
+		n, err := r.ReadSigned(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < n*2; i++ {
+			samples = append(samples, int32(buf.Sample(i)))
+		}
+	}
+	expected := []int32{1, -1, 1<<23 - 1, -1 << 23, 0x123456, 0}
+	if !reflect.DeepEqual(samples, expected) {
+		t.Errorf("expected %v got %v", expected, samples)
+	}
+
+	r, _ = wav.NewReader(bytes.NewReader(data))
+	if _, err := r.ReadSigned(signal.Allocator{Channels: 2, Length: 2, Capacity: 2}.Int16(signal.BitDepth16)); err == nil {
+		t.Errorf("expected error for bit depth mismatch")
+	}
+	if _, err := r.ReadUnsigned(signal.Allocator{Channels: 2, Length: 2, Capacity: 2}.Uint8(signal.BitDepth24)); err == nil {
+		t.Errorf("expected error for unsigned buffer")
+	}
+	r, _ = wav.NewReader(bytes.NewReader(data), wav.WithResample(44100))
+	if _, err := r.ReadSigned(buf); err == nil {
+		t.Errorf("expected error for resampled stream")
+	}
+}
+
+func TestReadUnsigned(t *testing.T) {
+	data := riff(chunk("fmt ", fmtPayload(1, 8, 1, 8000)), chunk("data", []byte{0, 128, 255, 7}))
+	r, err := wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 1, Length: 8, Capacity: 8}.Uint8(signal.BitDepth8)
+	n, err := r.ReadUnsigned(buf)
+	if err != nil || n != 4 {
+		t.Fatalf("unexpected result %d: %v", n, err)
+	}
+	for i, expected := range []uint64{0, 128, 255, 7} {
+		if v := buf.Sample(i); v != expected {
+			t.Errorf("sample %d: expected %d got %d", i, expected, v)
+		}
+	}
+	if _, err := r.ReadUnsigned(buf); err != io.EOF {
+		t.Errorf("expected EOF got %v", err)
+	}
+	if _, err := r.ReadSigned(signal.Allocator{Channels: 1, Length: 8, Capacity: 8}.Int8(signal.BitDepth8)); err == nil {
+		t.Errorf("expected error for signed buffer")
+	}
+}
+
+func TestWriteSigned(t *testing.T) {
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth16, signal.BitDepth24, signal.BitDepth32} {
+		const frames = 300
+		ints := signal.Allocator{Channels: 2, Length: frames, Capacity: frames}.Int64(bitDepth)
+		msv := int64(bitDepth.MaxSignedValue())
+		for i := 0; i < ints.Len(); i++ {
+			ints.SetSample(i, (int64(i)*7919)%(2*msv)-msv)
+		}
+		ints.SetSample(0, msv)
+		ints.SetSample(1, -msv-1)
+		floats := signal.Allocator{Channels: 2, Length: frames, Capacity: frames}.Float64()
+		signal.SignedAsFloating(ints, floats)
+
+		f := wav.Format{SampleRate: 48000, Channels: 2, BitDepth: bitDepth}
+		var fixed, floating buffer
+		w, err := wav.NewWriter(&fixed, f)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n, err := w.WriteSigned(ints); err != nil || n != frames {
+			t.Fatalf("unexpected result %d: %v", n, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		w, _ = wav.NewWriter(&floating, f)
+		w.Write(floats)
+		w.Close()
+		if !bytes.Equal(fixed.data, floating.data) {
+			t.Errorf("%v bits: fixed-point output differs from floating-point", bitDepth)
+		}
+	}
+
+	var out buffer
+	w, _ := wav.NewWriter(&out, wav.Format{SampleRate: 8000, Channels: 1, BitDepth: signal.BitDepth8}, wav.WithExactLength(3))
+	uints := signal.Allocator{Channels: 1, Length: 4, Capacity: 4}.Uint8(signal.BitDepth8)
+	signal.WriteUint8([]uint8{0, 128, 255, 7}, uints)
+	if n, err := w.WriteUnsigned(uints); err != nil || n != 3 {
+		t.Fatalf("unexpected result %d: %v", n, err)
+	}
+	w.Close()
+	if pcm := out.data[44:]; !bytes.Equal(pcm, []byte{0, 128, 255}) {
+		t.Errorf("unexpected 8-bit samples %v", pcm)
+	}
+	if _, err := w.WriteSigned(signal.Allocator{Channels: 1, Length: 1, Capacity: 1}.Int8(signal.BitDepth8)); err == nil {
+		t.Errorf("expected error for signed buffer")
+	}
+	w, _ = wav.NewWriter(&out, wav.Format{SampleRate: 8000, Channels: 2, BitDepth: signal.BitDepth16}, wav.MidSide())
+	if _, err := w.WriteSigned(signal.Allocator{Channels: 2, Length: 1, Capacity: 1}.Int16(signal.BitDepth16)); err == nil {
+		t.Errorf("expected error for mid/side conversion")
+	}
+}
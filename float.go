@@ -0,0 +1,168 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// errFloatBitDepth is returned when float samples are written with other
+// than 32-bit depth.
+var errFloatBitDepth = errors.New("float samples require 32-bit depth")
+
+// Float32 makes Sink write 32-bit IEEE float samples, the bit depth of
+// Sink must be 32. Source decodes float samples without this option.
+// Values beyond [-1, 1] are written as is, so the stream keeps the
+// headroom above 0 dBFS, unless WithFloatClamp is provided.
+func Float32() Option {
+	return func(o *options) {
+		o.float = true
+	}
+}
+
+// WithFloatClamp makes Sink clamp float samples to provided range. It
+// has no effect on integer samples, their range is handled according to
+// WithOverflow.
+func WithFloatClamp(min, max float64) Option {
+	return func(o *options) {
+		o.clamp = &[2]float64{min, max}
+	}
+}
+
+// validateFloat checks that float samples can be written with provided
+// format.
+func (o *options) validateFloat(f Format) error {
+	if !o.float {
+		return nil
+	}
+	if f.BitDepth != signal.BitDepth32 {
+		return errFloatBitDepth
+	}
+	if o.clamp != nil && !(o.clamp[0] < o.clamp[1]) {
+		return fmt.Errorf("invalid float clamp range [%v, %v]", o.clamp[0], o.clamp[1])
+	}
+	return nil
+}
+
+// putFloatFormat marks the payload of fmt chunk as IEEE float.
+func putFloatFormat(b []byte) {
+	binary.LittleEndian.PutUint16(b[0:], formatFloat)
+}
+
+// floatingAsFloat32 converts floating-point samples into the bits of
+// 32-bit float as go-audio encoder writes them. Returns a number of
+// samples written per channel.
+func (q quantizer) floatingAsFloat32(src signal.Floating, dst []int) int {
+	length := src.Len()
+	if len(dst) < length {
+		length = len(dst)
+	}
+	for i := 0; i < length; i++ {
+		var v float64
+		if q.midSide {
+			v = q.midSideSample(src, i)
+		} else {
+			v = q.sample(src, i)
+		}
+		if q.clamp != nil {
+			v = math.Max(q.clamp[0], math.Min(q.clamp[1], v))
+		}
+		dst[i] = int(int32(math.Float32bits(float32(v))))
+	}
+	return signal.ChannelLength(length, src.Channels())
+}
+
+func (w *Writer) writeFloat(floats signal.Floating) (int, error) {
+	n := w.quantizer.floatingAsFloat32(floats, w.pcm.Data)
+	data := w.pcm.Data[:floats.Channels()*n]
+	if err := w.encode(data); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// floatBitDepths are the bit depths of IEEE float samples decoded by
+// Source.
+var floatBitDepths = []signal.BitDepth{
+	signal.BitDepth32,
+	signal.BitDepth64,
+}
+
+// SupportedFloatBitDepths returns the bit depths of IEEE float data, in
+// ascending order. Source decodes all of them, Sink writes 32-bit float
+// samples only, see Float32.
+func SupportedFloatBitDepths() []signal.BitDepth {
+	return append([]signal.BitDepth(nil), floatBitDepths...)
+}
+
+// float returns true if the format stores IEEE float samples.
+func (f format) float() bool {
+	if f.extended() {
+		return bytes.Equal(f.Extension[6:extensibleSize], SubFormatFloat[:])
+	}
+	return f.AudioFormat == formatFloat
+}
+
+// floatDecoder decodes IEEE float samples of data chunk.
+type floatDecoder struct {
+	r              io.Reader
+	bytesPerSample int
+	buf            []byte
+}
+
+// newFloatReader returns the reader of IEEE float stream.
+func newFloatReader(rs io.ReadSeeker, c container, f format, bufferSize int) (*Reader, error) {
+	bitDepth := signal.BitDepth(f.BitsPerSample)
+	if f.Channels == 0 || (bitDepth != signal.BitDepth32 && bitDepth != signal.BitDepth64) || int(f.BlockAlign) != int(f.Channels)*f.bytesPerSample() {
+		return nil, fmt.Errorf("invalid float format: %d channels, %d bits, %d bytes block", f.Channels, f.BitsPerSample, f.BlockAlign)
+	}
+	data, err := dataReader(rs, c)
+	if err != nil {
+		return nil, err
+	}
+	r := Reader{
+		format: Format{
+			SampleRate: signal.Frequency(f.SampleRate),
+			Channels:   int(f.Channels),
+			BitDepth:   bitDepth,
+		},
+		float: &floatDecoder{
+			r:              data,
+			bytesPerSample: f.bytesPerSample(),
+		},
+	}
+	r.allocate(bufferSize)
+	return &r, nil
+}
+
+// read decodes whole frames that fit provided buffer. Returns the number
+// of frames read, io.EOF is returned when data is done.
+func (d *floatDecoder) read(dst signal.Floating) (int, error) {
+	frameSize := d.bytesPerSample * dst.Channels()
+	size := dst.Length() * frameSize
+	if cap(d.buf) < size {
+		d.buf = make([]byte, size)
+	}
+	n, err := io.ReadFull(d.r, d.buf[:size])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return 0, fmt.Errorf("error reading float samples: %w", err)
+	}
+	frames := n / frameSize
+	if frames == 0 {
+		return 0, io.EOF
+	}
+	for i := 0; i < frames*dst.Channels(); i++ {
+		b := d.buf[i*d.bytesPerSample:]
+		if d.bytesPerSample == 4 {
+			dst.SetSample(i, float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		} else {
+			dst.SetSample(i, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+	}
+	return frames, nil
+}
@@ -0,0 +1,131 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// float32Samples decodes 32-bit float samples of canonical wav file.
+func float32Samples(data []byte) []float32 {
+	pcm := data[44:]
+	samples := make([]float32, len(pcm)/4)
+	for i := range samples {
+		samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(pcm[4*i:]))
+	}
+	return samples
+}
+
+func TestFloat32(t *testing.T) {
+	samples := []float64{2, -1.5, 0.25, -0.5}
+	tests := []struct {
+		options  []wav.Option
+		expected []float32
+	}{
+		{
+			options:  []wav.Option{wav.Float32()},
+			expected: []float32{2, -1.5, 0.25, -0.5},
+		},
+		{
+			options:  []wav.Option{wav.Float32(), wav.WithFloatClamp(-1, 1)},
+			expected: []float32{1, -1, 0.25, -0.5},
+		},
+		{
+			options:  []wav.Option{wav.Float32(), wav.WithFloatClamp(-0.4, 4)},
+			expected: []float32{2, -0.4, 0.25, -0.4},
+		},
+	}
+	for _, test := range tests {
+		var out buffer
+		transcode(t, floatSource(48000, 2, samples), wav.Sink(&out, signal.BitDepth32, test.options...))
+		if format := binary.LittleEndian.Uint16(out.data[20:]); format != 3 {
+			t.Errorf("expected float format got %d", format)
+		}
+		if result := float32Samples(out.data); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("expected samples %v got %v", test.expected, result)
+		}
+	}
+
+	var out buffer
+	if _, err := wav.Sink(&out, signal.BitDepth16, wav.Float32())(mutable.Context{}, bufferSize, pipe.SignalProperties{SampleRate: 48000, Channels: 1}); err == nil {
+		t.Errorf("expected error for 16-bit float")
+	}
+	if _, err := wav.Sink(&out, signal.BitDepth32, wav.Float32(), wav.WithFloatClamp(1, -1))(mutable.Context{}, bufferSize, pipe.SignalProperties{SampleRate: 48000, Channels: 1}); err == nil {
+		t.Errorf("expected error for invalid clamp range")
+	}
+}
+
+func TestFloatDecoding(t *testing.T) {
+	samples := []float64{2, -1.5, 0.25, -0.5, 1e-40, 0.1}
+	expected := make([]float64, len(samples))
+	for i, v := range samples {
+		expected[i] = float64(float32(v))
+	}
+	for _, options := range [][]wav.Option{
+		{wav.Float32()},
+		{wav.Float32(), wav.WithExtensible(&wav.Extensible{ValidBits: 32, ChannelMask: 3, SubFormat: wav.SubFormatFloat})},
+	} {
+		var out buffer
+		transcode(t, floatSource(48000, 2, samples), wav.Sink(&out, signal.BitDepth32, options...))
+		r, err := wav.NewReader(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f := r.Format(); f.BitDepth != signal.BitDepth32 || f.Channels != 2 || f.SampleRate != 48000 {
+			t.Errorf("unexpected format %+v", f)
+		}
+		if result := readAll(t, r); !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected samples %v got %v", expected, result)
+		}
+	}
+
+	// 64-bit float stream.
+	pcm := make([]byte, 8*len(samples))
+	for i, v := range samples {
+		binary.LittleEndian.PutUint64(pcm[8*i:], math.Float64bits(v))
+	}
+	r, err := wav.NewReader(bytes.NewReader(float64Stream(pcm)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f := r.Format(); f.BitDepth != signal.BitDepth64 || f.Channels != 1 {
+		t.Errorf("unexpected 64-bit format %+v", f)
+	}
+	if result := readAll(t, r); !reflect.DeepEqual(result, samples) {
+		t.Errorf("expected 64-bit samples %v got %v", samples, result)
+	}
+
+	if _, err := r.ReadSigned(signal.Allocator{Channels: 1, Length: 4, Capacity: 4}.Int64(signal.BitDepth64)); err == nil {
+		t.Errorf("expected error for fixed-point frames of float stream")
+	}
+
+	if depths := wav.SupportedFloatBitDepths(); !reflect.DeepEqual(depths, []signal.BitDepth{signal.BitDepth32, signal.BitDepth64}) {
+		t.Errorf("unexpected float bit depths %v", depths)
+	}
+}
+
+// float64Stream returns mono 64-bit float wav file with provided samples.
+func float64Stream(pcm []byte) []byte {
+	data := make([]byte, 44, 44+len(pcm))
+	copy(data, "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(36+len(pcm)))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 3)
+	binary.LittleEndian.PutUint16(data[22:], 1)
+	binary.LittleEndian.PutUint32(data[24:], 8000)
+	binary.LittleEndian.PutUint32(data[28:], 8000*8)
+	binary.LittleEndian.PutUint16(data[32:], 8)
+	binary.LittleEndian.PutUint16(data[34:], 64)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], uint32(len(pcm)))
+	return append(data, pcm...)
+}
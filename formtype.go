@@ -0,0 +1,31 @@
+package wav
+
+import "fmt"
+
+// WithFormType makes Sink write provided form type in RIFF header instead
+// of "WAVE". This is meant for devices that expect a variant of wav
+// format, Source can't read such streams. Form type must consist of
+// printable ASCII characters.
+func WithFormType(formType [4]byte) Option {
+	return func(o *options) {
+		o.formType = &formType
+	}
+}
+
+// riffFormType returns the form type that Sink writes.
+func (o *options) riffFormType() [4]byte {
+	if o.formType == nil {
+		return waveID
+	}
+	return *o.formType
+}
+
+// validateFormType checks if the form type can be written.
+func (o *options) validateFormType() error {
+	for _, b := range o.riffFormType() {
+		if b < 0x20 || b > 0x7E {
+			return fmt.Errorf("invalid RIFF form type %q", o.formType[:])
+		}
+	}
+	return nil
+}
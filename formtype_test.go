@@ -0,0 +1,40 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestWithFormType(t *testing.T) {
+	samples := []float64{0.5, -0.5}
+	var expected, out buffer
+	transcode(t, floatSource(8000, 1, samples), wav.Sink(&expected, signal.BitDepth16))
+	formType := [4]byte{'W', 'A', 'V', 'X'}
+	transcode(t, floatSource(8000, 1, samples), wav.Sink(&out, signal.BitDepth16, wav.WithFormType(formType)))
+	if !bytes.Equal(out.data[8:12], formType[:]) {
+		t.Errorf("unexpected form type %q", out.data[8:12])
+	}
+	if !bytes.Equal(out.data[:8], expected.data[:8]) || !bytes.Equal(out.data[12:], expected.data[12:]) {
+		t.Errorf("stream differs beyond form type")
+	}
+
+	// verification reads the stream with the same form type.
+	transcode(t, floatSource(8000, 1, samples), wav.Sink(&readableBuffer{}, signal.BitDepth16, wav.WithFormType(formType), wav.WithVerify()))
+
+	var stream bytes.Buffer
+	transcode(t, floatSource(8000, 1, samples), wav.SinkStream(&stream, signal.BitDepth16, wav.WithFormType(formType)))
+	if !bytes.Equal(stream.Bytes()[8:12], formType[:]) {
+		t.Errorf("unexpected streamed form type %q", stream.Bytes()[8:12])
+	}
+
+	props := pipe.SignalProperties{SampleRate: 8000, Channels: 1}
+	if _, err := wav.Sink(&out, signal.BitDepth16, wav.WithFormType([4]byte{'W', 'A', 'V', 0}))(mutable.Context{}, bufferSize, props); err == nil {
+		t.Errorf("expected error for invalid form type")
+	}
+}
@@ -0,0 +1,80 @@
+package wav
+
+import (
+	"io"
+	"math"
+	"math/rand"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// SourceSine generates provided number of frames of full scale sine wave
+// with provided frequency. All channels have the same samples. It can
+// replace Source in tests and benchmarks that don't need files.
+func SourceSine(freq, sampleRate signal.Frequency, channels int, frames int64) pipe.SourceAllocatorFunc {
+	step := 2 * math.Pi * float64(freq) / float64(sampleRate)
+	return sourceGenerator(sampleRate, channels, frames, func() func(int64) float64 {
+		return func(frame int64) float64 {
+			return math.Sin(step * float64(frame))
+		}
+	})
+}
+
+// SourceNoise generates provided number of frames of white noise uniformly
+// distributed in [-1, 1). Every channel has its own samples. The noise is
+// generated from provided seed, so every allocation produces the same
+// frames.
+func SourceNoise(seed int64, sampleRate signal.Frequency, channels int, frames int64) pipe.SourceAllocatorFunc {
+	return sourceGenerator(sampleRate, channels, frames, func() func(int64) float64 {
+		random := rand.New(rand.NewSource(seed))
+		return func(int64) float64 {
+			return 2*random.Float64() - 1
+		}
+	})
+}
+
+// SourceSilence generates provided number of frames of silence.
+func SourceSilence(sampleRate signal.Frequency, channels int, frames int64) pipe.SourceAllocatorFunc {
+	return sourceGenerator(sampleRate, channels, frames, func() func(int64) float64 {
+		return func(int64) float64 {
+			return 0
+		}
+	})
+}
+
+// sourceGenerator returns source of frames with samples returned by
+// generator. The generator is created for every allocation and called
+// for every sample with the index of its frame.
+func sourceGenerator(sampleRate signal.Frequency, channels int, frames int64, generator func() func(frame int64) float64) pipe.SourceAllocatorFunc {
+	return func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+		if err := validateBufferSize(bufferSize); err != nil {
+			return pipe.Source{}, err
+		}
+		sample := generator()
+		var frame int64
+		return pipe.Source{
+			SourceFunc: func(out signal.Floating) (int, error) {
+				if frame == frames {
+					return 0, io.EOF
+				}
+				n := out.Length()
+				if remaining := frames - frame; int64(n) > remaining {
+					n = int(remaining)
+				}
+				for i := 0; i < n; i++ {
+					for c := 0; c < channels; c++ {
+						out.SetSample(i*channels+c, sample(frame))
+					}
+					frame++
+				}
+				return n, nil
+			},
+			SignalProperties: pipe.SignalProperties{
+				SampleRate: sampleRate,
+				Channels:   channels,
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,56 @@
+package wav_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestGenerators(t *testing.T) {
+	const frames = 1000
+	var sine buffer
+	transcode(t, wav.SourceSine(441, 44100, 2, frames), wav.Sink(&sine, signal.BitDepth32))
+	r, err := wav.NewReader(bytes.NewReader(sine.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	samples := readAll(t, r)
+	if len(samples) != 2*frames {
+		t.Fatalf("expected %d samples got %d", 2*frames, len(samples))
+	}
+	for i := 0; i < frames; i++ {
+		expected := math.Sin(2 * math.Pi * float64(i) / 100)
+		if math.Abs(samples[2*i]-expected) > 1e-6 || samples[2*i] != samples[2*i+1] {
+			t.Fatalf("frame %d: expected %v got %v", i, expected, samples[2*i:2*i+2])
+		}
+	}
+
+	var noise, again buffer
+	transcode(t, wav.SourceNoise(1, 48000, 2, frames), wav.Sink(&noise, signal.BitDepth16))
+	transcode(t, wav.SourceNoise(1, 48000, 2, frames), wav.Sink(&again, signal.BitDepth16))
+	if !reflect.DeepEqual(noise.data, again.data) {
+		t.Errorf("noise of the same seed differs")
+	}
+	pcm := int16Samples(noise.data)
+	if len(pcm) != 2*frames {
+		t.Fatalf("expected %d noise samples got %d", 2*frames, len(pcm))
+	}
+	var sum float64
+	for _, v := range pcm {
+		sum += float64(v) / 32768
+	}
+	if mean := sum / float64(len(pcm)); math.Abs(mean) > 0.05 {
+		t.Errorf("noise has mean %v", mean)
+	}
+
+	var silence buffer
+	transcode(t, wav.SourceSilence(8000, 1, frames), wav.Sink(&silence, signal.BitDepth16))
+	if !reflect.DeepEqual(int16Samples(silence.data), make([]int16, frames)) {
+		t.Errorf("unexpected samples of silence")
+	}
+}
@@ -0,0 +1,323 @@
+package wav
+
+import (
+	"fmt"
+	"math"
+)
+
+// GSM 6.10 as packed by Microsoft: every block of 65 bytes contains two
+// frames of 260 bits and decodes into 320 samples.
+const (
+	formatGSM       = 0x31
+	gsmBlockAlign   = 65
+	gsmFrameSamples = 160
+	gsmBlockSamples = 2 * gsmFrameSamples
+)
+
+// gsmLARBits is the size of every coded log-area ratio.
+var gsmLARBits = [8]uint{6, 6, 5, 5, 4, 4, 3, 3}
+
+// Tables of GSM 06.10 decoder.
+var (
+	gsmFAC = [8]int32{18431, 20479, 22527, 24575, 26623, 28671, 30719, 32767}
+	gsmQLB = [4]int32{3277, 11469, 21299, 32767}
+	// parameters to decode log-area ratios: B, MIC and INVA.
+	gsmLARB    = [8]int32{0, 0, 2048, -2560, 94, -1792, -341, -1144}
+	gsmLARMIC  = [8]int32{-32, -32, -16, -16, -8, -8, -4, -4}
+	gsmLARINVA = [8]int32{13107, 13107, 13107, 13107, 19223, 17476, 31454, 29708}
+)
+
+// gsmFrame contains the parameters of a single GSM frame.
+type gsmFrame struct {
+	LARc [8]int32
+	// parameters of every sub-frame.
+	Nc    [4]int32
+	bc    [4]int32
+	Mc    [4]int32
+	xmaxc [4]int32
+	xMc   [4][13]int32
+}
+
+// gsmState is the state of GSM decoder preserved between frames.
+type gsmState struct {
+	dp0   [280]int32
+	LARpp [2][8]int32
+	j     int
+	nrp   int32
+	v     [9]int32
+	msr   int32
+}
+
+// newGSMDecoder returns the decoder of GSM 6.10 blocks.
+func newGSMDecoder(f format) (*blockDecoder, error) {
+	if f.Channels != 1 || f.BlockAlign != gsmBlockAlign {
+		return nil, fmt.Errorf("invalid GSM 6.10 format: %d channels, %d bytes block", f.Channels, f.BlockAlign)
+	}
+	s := gsmState{nrp: 40}
+	return &blockDecoder{
+		channels: 1,
+		block:    make([]byte, gsmBlockAlign),
+		// first frame takes 260 bits.
+		minBlock: 33,
+		decode:   s.decodeBlock,
+		samples:  make([]int, gsmBlockSamples),
+	}, nil
+}
+
+// decodeBlock decodes GSM frames of the block. Returns the number of
+// decoded samples, which is the number of frames of mono stream.
+func (s *gsmState) decodeBlock(block []byte, samples []int) int {
+	bits := gsmBits{data: block}
+	decoded := 0
+	for i := 0; i < 2 && bits.available() >= 260; i++ {
+		frame := bits.frame()
+		s.decode(&frame, samples[decoded:decoded+gsmFrameSamples])
+		decoded += gsmFrameSamples
+	}
+	return decoded
+}
+
+// gsmBits reads the fields packed starting from the least significant bit.
+type gsmBits struct {
+	data   []byte
+	offset uint
+}
+
+// available returns the number of unread bits.
+func (b *gsmBits) available() int {
+	return len(b.data)*8 - int(b.offset)
+}
+
+// read returns the next field of provided size.
+func (b *gsmBits) read(size uint) int32 {
+	var v int32
+	for i := uint(0); i < size; i++ {
+		bit := b.data[b.offset/8] >> (b.offset % 8) & 1
+		v |= int32(bit) << i
+		b.offset++
+	}
+	return v
+}
+
+// frame reads the parameters of the next frame.
+func (b *gsmBits) frame() gsmFrame {
+	var f gsmFrame
+	for i, size := range gsmLARBits {
+		f.LARc[i] = b.read(size)
+	}
+	for j := 0; j < 4; j++ {
+		f.Nc[j] = b.read(7)
+		f.bc[j] = b.read(2)
+		f.Mc[j] = b.read(2)
+		f.xmaxc[j] = b.read(6)
+		for i := range f.xMc[j] {
+			f.xMc[j][i] = b.read(3)
+		}
+	}
+	return f
+}
+
+// decode synthesizes 160 samples of the frame.
+func (s *gsmState) decode(f *gsmFrame, samples []int) {
+	var wt [gsmFrameSamples]int32
+	for j := 0; j < 4; j++ {
+		var erp [40]int32
+		gsmRPEDecoding(f.xmaxc[j], f.Mc[j], &f.xMc[j], &erp)
+		s.longTermSynthesis(f.Nc[j], f.bc[j], &erp)
+		copy(wt[j*40:], s.dp0[120:160])
+	}
+
+	var sr [gsmFrameSamples]int32
+	s.shortTermSynthesis(&f.LARc, &wt, &sr)
+
+	// de-emphasis, upscaling and truncation to 13 bits.
+	msr := s.msr
+	for k, v := range sr {
+		msr = gsmAdd(v, gsmMultR(msr, 28180))
+		samples[k] = int(int16(gsmAdd(msr, msr) & 0xFFF8))
+	}
+	s.msr = msr
+}
+
+// gsmRPEDecoding reconstructs the excitation of the sub-frame.
+func gsmRPEDecoding(xmaxc, Mc int32, xMc *[13]int32, erp *[40]int32) {
+	// compute exponent and mantissa of the block maximum.
+	var exp int32
+	if xmaxc > 15 {
+		exp = (xmaxc >> 3) - 1
+	}
+	mant := xmaxc - exp<<3
+	if mant == 0 {
+		exp = -4
+		mant = 7
+	} else {
+		for mant <= 7 {
+			mant = mant<<1 | 1
+			exp--
+		}
+		mant -= 8
+	}
+
+	// inverse APCM quantization and grid positioning.
+	temp1 := gsmFAC[mant]
+	temp2 := gsmSub(6, exp)
+	temp3 := gsmASL(1, gsmSub(temp2, 1))
+	for i, x := range xMc {
+		temp := (x<<1 - 7) << 12
+		temp = gsmAdd(gsmMultR(temp1, temp), temp3)
+		erp[Mc+3*int32(i)] = gsmASR(temp, temp2)
+	}
+}
+
+// longTermSynthesis applies long term synthesis filter. Reconstructed
+// residual is stored in dp0[120:160].
+func (s *gsmState) longTermSynthesis(Ncr, bcr int32, erp *[40]int32) {
+	Nr := Ncr
+	if Ncr < 40 || Ncr > 120 {
+		Nr = s.nrp
+	}
+	s.nrp = Nr
+	brp := gsmQLB[bcr]
+	drp := s.dp0[120:]
+	for k := int32(0); k < 40; k++ {
+		drpp := gsmMultR(brp, s.dp0[120+k-Nr])
+		drp[k] = gsmAdd(erp[k], drpp)
+	}
+	copy(s.dp0[:120], s.dp0[40:160])
+}
+
+// shortTermSynthesis applies short term synthesis filter with
+// coefficients interpolated between the frames.
+func (s *gsmState) shortTermSynthesis(LARcr *[8]int32, wt, sr *[gsmFrameSamples]int32) {
+	prev := s.LARpp[s.j]
+	s.j ^= 1
+	current := &s.LARpp[s.j]
+	for i := range current {
+		temp := gsmAdd(LARcr[i], gsmLARMIC[i]) << 10
+		temp = gsmSub(temp, gsmLARB[i]<<1)
+		temp = gsmMultR(gsmLARINVA[i], temp)
+		current[i] = gsmAdd(temp, temp)
+	}
+
+	var LARp [8]int32
+	for i := range LARp {
+		LARp[i] = gsmAdd(gsmAdd(prev[i]>>2, current[i]>>2), prev[i]>>1)
+	}
+	s.synthesis(&LARp, wt[0:13], sr[0:13])
+	for i := range LARp {
+		LARp[i] = gsmAdd(prev[i]>>1, current[i]>>1)
+	}
+	s.synthesis(&LARp, wt[13:27], sr[13:27])
+	for i := range LARp {
+		LARp[i] = gsmAdd(gsmAdd(prev[i]>>2, current[i]>>2), current[i]>>1)
+	}
+	s.synthesis(&LARp, wt[27:40], sr[27:40])
+	LARp = *current
+	s.synthesis(&LARp, wt[40:], sr[40:])
+}
+
+// synthesis converts log-area ratios into reflection coefficients and
+// filters provided samples.
+func (s *gsmState) synthesis(LARp *[8]int32, wt, sr []int32) {
+	var rrp [8]int32
+	for i, v := range LARp {
+		temp := v
+		if temp < 0 {
+			temp = -temp
+			if temp > math.MaxInt16 {
+				temp = math.MaxInt16
+			}
+		}
+		switch {
+		case temp < 11059:
+			temp <<= 1
+		case temp < 20070:
+			temp += 11059
+		default:
+			temp = gsmAdd(temp>>2, 26112)
+		}
+		if v < 0 {
+			temp = -temp
+		}
+		rrp[i] = temp
+	}
+
+	v := &s.v
+	for k, sri := range wt {
+		for i := 7; i >= 0; i-- {
+			sri = gsmSub(sri, gsmMultRWrap(rrp[i], v[i]))
+			v[i+1] = gsmAdd(v[i], gsmMultRWrap(rrp[i], sri))
+		}
+		v[0] = sri
+		sr[k] = sri
+	}
+}
+
+// gsmAdd returns the sum saturated to 16 bits.
+func gsmAdd(a, b int32) int32 {
+	return gsmSaturate(a + b)
+}
+
+// gsmSub returns the difference saturated to 16 bits.
+func gsmSub(a, b int32) int32 {
+	return gsmSaturate(a - b)
+}
+
+func gsmSaturate(v int32) int32 {
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return v
+}
+
+// gsmMultR returns the rounded product of fractional values.
+func gsmMultR(a, b int32) int32 {
+	if a == math.MinInt16 && b == math.MinInt16 {
+		return math.MaxInt16
+	}
+	return (a*b + 16384) >> 15
+}
+
+// gsmMultRWrap returns the rounded product of fractional values truncated
+// to 16 bits, as reference implementation of synthesis filter does.
+func gsmMultRWrap(a, b int32) int32 {
+	if a == math.MinInt16 && b == math.MinInt16 {
+		return math.MaxInt16
+	}
+	return int32(int16((a*b + 16384) >> 15))
+}
+
+// gsmASL shifts the value left, negative shift is applied to the right.
+func gsmASL(a, n int32) int32 {
+	switch {
+	case n >= 16:
+		return 0
+	case n <= -16:
+		if a < 0 {
+			return -1
+		}
+		return 0
+	case n < 0:
+		return gsmASR(a, -n)
+	}
+	return a << uint(n)
+}
+
+// gsmASR shifts the value right, negative shift is applied to the left.
+func gsmASR(a, n int32) int32 {
+	switch {
+	case n >= 16:
+		if a < 0 {
+			return -1
+		}
+		return 0
+	case n <= -16:
+		return 0
+	case n < 0:
+		return a << uint(-n)
+	}
+	return a >> uint(n)
+}
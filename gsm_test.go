@@ -0,0 +1,152 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// gsmFmtPayload returns payload of GSM 6.10 fmt chunk.
+func gsmFmtPayload(sampleRate uint32) []byte {
+	p := fmtPayload(1, 0, 65, sampleRate)
+	binary.LittleEndian.PutUint16(p[0:], 0x31)
+	binary.LittleEndian.PutUint32(p[8:], 1625)
+	return append(p, 2, 0, 0x40, 0x01)
+}
+
+// gsmBlock returns the block of two frames with deterministic parameters.
+// Fields are packed starting from the least significant bit.
+func gsmBlock() []byte {
+	block := make([]byte, 65)
+	offset := 0
+	put := func(v, size int) {
+		for i := 0; i < size; i++ {
+			block[offset/8] |= byte(v>>uint(i)&1) << uint(offset%8)
+			offset++
+		}
+	}
+	for f := 0; f < 2; f++ {
+		for i, size := range []int{6, 6, 5, 5, 4, 4, 3, 3} {
+			put(1<<uint(size-1)+(f+i)%3-1, size)
+		}
+		for j := 0; j < 4; j++ {
+			put(40+(13*j+29*f)%81, 7)
+			put((j+f)%4, 2)
+			put((j+2*f)%4, 2)
+			put(20+5*j-3*f, 6)
+			for i := 0; i < 13; i++ {
+				put((5*i+j+f)%8, 3)
+			}
+		}
+	}
+	return block
+}
+
+func TestGSMKnownAnswer(t *testing.T) {
+	// full block and the first frame of the next block.
+	block := gsmBlock()
+	data := riff(
+		chunk("fmt ", gsmFmtPayload(8000)),
+		chunk("data", append(block, block[:33]...)),
+	)
+	r, err := wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var samples []int
+	for _, v := range readAll(t, r) {
+		samples = append(samples, int(math.Round(v*32768)))
+	}
+	if len(samples) != 480 {
+		t.Fatalf("expected 480 samples got %d", len(samples))
+	}
+	// samples at the start of every frame computed with independent
+	// implementation of GSM 06.10 reference decoder.
+	expected := map[int][]int{
+		0:   {-1456, -1280, -1088, -368, -232, -208, -672, -624, -440, 1016},
+		160: {3824, 2720, 3184, 1856, 2048, 1080, 256, -840, -1048, -1424},
+		320: {-4872, -3184, -2800, -1968, -720, 56, 360, 528, 1304, 2056},
+		470: {-2968, -3704, -2384, -3648, -2344, -2080, -3280, -2264, -1272, 4880},
+	}
+	for offset, e := range expected {
+		if result := samples[offset : offset+len(e)]; !reflect.DeepEqual(e, result) {
+			t.Errorf("samples at %d: expected %v got %v", offset, e, result)
+		}
+	}
+
+	// fact chunk bounds decoded frames.
+	data = riff(
+		chunk("fmt ", gsmFmtPayload(8000)),
+		chunk("fact", []byte{0x90, 0x01, 0, 0}),
+		chunk("data", append(block, block[:33]...)),
+	)
+	if r, err = wav.NewReader(bytes.NewReader(data)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bounded := readAll(t, r)
+	if len(bounded) != 400 {
+		t.Fatalf("expected 400 samples bounded by fact chunk got %d", len(bounded))
+	}
+	for i, v := range bounded {
+		if int(math.Round(v*32768)) != samples[i] {
+			t.Fatalf("sample %d of bounded stream differs", i)
+		}
+	}
+}
+
+func TestGSM(t *testing.T) {
+	blocks := make([]byte, 65*2+40)
+	for i := range blocks {
+		blocks[i] = byte(i * 37)
+	}
+	data := riff(
+		chunk("fmt ", gsmFmtPayload(8000)),
+		chunk("fact", []byte{0x20, 0x03, 0, 0}),
+		chunk("data", blocks),
+	)
+
+	info, err := wav.Probe(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected probe error: %v", err)
+	}
+	if codec := info.Codec(); codec != "GSM 6.10" {
+		t.Errorf("unexpected codec: %s", codec)
+	}
+
+	r, err := wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if format := r.Format(); format.BitDepth != signal.BitDepth16 || format.Channels != 1 {
+		t.Errorf("unexpected format: %+v", format)
+	}
+	buf := signal.Allocator{Channels: 1, Length: 100, Capacity: 100}.Float64()
+	frames := 0
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			// decoded samples have 13 bits of precision.
+			if v := int(buf.Sample(i) * 32768); v%8 != 0 && v < 32760 {
+				t.Fatalf("sample %d has more than 13 bits: %d", frames+i, v)
+			}
+		}
+		frames += n
+	}
+	// two full blocks and the first frame of the last block.
+	if expected := 320*2 + 160; frames != expected {
+		t.Errorf("expected %d frames got %d", expected, frames)
+	}
+}
@@ -0,0 +1,107 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// canonicalHeaderSize is the size of RIFF, fmt and data headers of PCM
+// wav stream without any other chunks.
+const canonicalHeaderSize = 44
+
+// supportedBitDepths are the bit depths of PCM output.
+var supportedBitDepths = []signal.BitDepth{
+	signal.BitDepth8,
+	signal.BitDepth16,
+	signal.BitDepth24,
+	signal.BitDepth32,
+}
+
+// SupportedBitDepths returns the bit depths of PCM data that Sink and
+// WriteHeader support, in ascending order.
+func SupportedBitDepths() []signal.BitDepth {
+	return append([]signal.BitDepth(nil), supportedBitDepths...)
+}
+
+// Format describes PCM data of wav stream.
+type Format struct {
+	SampleRate signal.Frequency
+	Channels   int
+	BitDepth   signal.BitDepth
+}
+
+// BlockAlign returns the size of a single frame in bytes.
+func (f Format) BlockAlign() int {
+	return f.Channels * int(f.BitDepth) / 8
+}
+
+// validate checks if format can be written in the fmt chunk.
+func (f Format) validate() error {
+	if !bitDepthSupported(f.BitDepth) {
+		return fmt.Errorf("unsupported bit depth: %d", f.BitDepth)
+	}
+	if f.Channels < 1 || f.Channels > math.MaxUint16 {
+		return fmt.Errorf("invalid number of channels: %d", f.Channels)
+	}
+	if f.SampleRate < 1 || f.SampleRate > math.MaxUint32 || f.SampleRate != signal.Frequency(math.Trunc(float64(f.SampleRate))) {
+		return fmt.Errorf("invalid sample rate: %v", f.SampleRate)
+	}
+	return nil
+}
+
+// bitDepthSupported returns true if PCM data can be written with provided
+// bit depth.
+func bitDepthSupported(bd signal.BitDepth) bool {
+	for _, supported := range supportedBitDepths {
+		if bd == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteHeader writes the final header of PCM wav stream with dataBytes of
+// samples. The header is written in one shot, so caller can write the
+// samples and the pad byte for the odd size right after it with any
+// writer. DataBytes must be aligned to the frame size.
+func WriteHeader(w io.Writer, f Format, dataBytes int) error {
+	if err := f.validate(); err != nil {
+		return err
+	}
+	if blockAlign := f.BlockAlign(); dataBytes < 0 || dataBytes%blockAlign != 0 {
+		return fmt.Errorf("data size %d isn't aligned to %d bytes frame", dataBytes, blockAlign)
+	}
+	riffSize := int64(canonicalHeaderSize-8) + int64(dataBytes) + int64(dataBytes%2)
+	if riffSize > math.MaxUint32 {
+		return fmt.Errorf("data size %d exceeds RIFF size limit", dataBytes)
+	}
+
+	header := make([]byte, canonicalHeaderSize)
+	copy(header[0:], riffID[:])
+	binary.LittleEndian.PutUint32(header[4:], uint32(riffSize))
+	copy(header[8:], waveID[:])
+	copy(header[12:], fmtID[:])
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	putFormat(header[20:], f)
+	copy(header[36:], dataID[:])
+	binary.LittleEndian.PutUint32(header[40:], uint32(dataBytes))
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("error writing header: %w", err)
+	}
+	return nil
+}
+
+// putFormat puts 16 bytes of PCM fmt chunk payload into provided slice.
+func putFormat(b []byte, f Format) {
+	blockAlign := f.BlockAlign()
+	binary.LittleEndian.PutUint16(b[0:], formatPCM)
+	binary.LittleEndian.PutUint16(b[2:], uint16(f.Channels))
+	binary.LittleEndian.PutUint32(b[4:], uint32(f.SampleRate))
+	binary.LittleEndian.PutUint32(b[8:], uint32(int(f.SampleRate)*blockAlign))
+	binary.LittleEndian.PutUint16(b[12:], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(b[14:], uint16(f.BitDepth))
+}
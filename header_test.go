@@ -0,0 +1,81 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestWriteHeader(t *testing.T) {
+	format := wav.Format{
+		SampleRate: 8000,
+		Channels:   1,
+		BitDepth:   signal.BitDepth8,
+	}
+	pcm := []byte{0x80, 0xBF, 0xFF}
+
+	var b bytes.Buffer
+	if err := wav.WriteHeader(&b, format, len(pcm)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.Write(pcm)
+	b.WriteByte(0)
+
+	var sink buffer
+	transcode(t, floatSource(8000, 1, []float64{0, 0.5, 1}), wav.Sink(&sink, signal.BitDepth8))
+	if !bytes.Equal(sink.data[8:], b.Bytes()[8:len(sink.data)]) {
+		t.Errorf("header doesn't match sink output:\n%v\n%v", sink.data, b.Bytes())
+	}
+	if riffSize := binary.LittleEndian.Uint32(b.Bytes()[4:]); riffSize != 40 {
+		t.Errorf("unexpected RIFF size: %d", riffSize)
+	}
+	var out buffer
+	transcode(t, wav.Source(bytes.NewReader(b.Bytes())), wav.Sink(&out, signal.BitDepth8))
+
+	tests := []struct {
+		format    wav.Format
+		dataBytes int
+	}{
+		{
+			format:    wav.Format{SampleRate: 44100, Channels: 2, BitDepth: signal.BitDepth24},
+			dataBytes: 7,
+		},
+		{
+			format:    wav.Format{SampleRate: 44100, Channels: 2, BitDepth: signal.BitDepth4},
+			dataBytes: 4,
+		},
+		{
+			format:    wav.Format{SampleRate: 44100, Channels: 0, BitDepth: signal.BitDepth16},
+			dataBytes: 0,
+		},
+		{
+			format:    wav.Format{SampleRate: 44100.5, Channels: 1, BitDepth: signal.BitDepth16},
+			dataBytes: 0,
+		},
+	}
+	for _, test := range tests {
+		if err := wav.WriteHeader(&b, test.format, test.dataBytes); err == nil {
+			t.Errorf("expected error for %+v with %d bytes", test.format, test.dataBytes)
+		}
+	}
+}
+
+func TestSupportedBitDepths(t *testing.T) {
+	for _, bitDepth := range wav.SupportedBitDepths() {
+		format := wav.Format{SampleRate: 44100, Channels: 1, BitDepth: bitDepth}
+		if err := wav.WriteHeader(&bytes.Buffer{}, format, 0); err != nil {
+			t.Errorf("unexpected error for bit depth %d: %v", bitDepth, err)
+		}
+		var out buffer
+		transcode(t, floatSource(44100, 1, []float64{0.5}), wav.Sink(&out, bitDepth))
+	}
+	depths := wav.SupportedBitDepths()
+	depths[0] = signal.BitDepth4
+	if wav.SupportedBitDepths()[0] == signal.BitDepth4 {
+		t.Errorf("supported bit depths are modified by caller")
+	}
+}
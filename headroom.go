@@ -0,0 +1,42 @@
+package wav
+
+import (
+	"fmt"
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// WithHeadroom makes Source attenuate decoded samples by provided number
+// of decibels, e.g. -6 dB, so full scale samples leave a margin for
+// processors that add gain. The attenuation must not be positive. Zero,
+// the default, doesn't attenuate. Reader.Headroom reports the applied
+// attenuation, so downstream can compensate it.
+func WithHeadroom(db float64) Option {
+	return func(o *options) {
+		o.headroom = db
+	}
+}
+
+// Headroom returns the attenuation of decoded samples in decibels.
+func (r *Reader) Headroom() float64 {
+	return r.headroom
+}
+
+// validateHeadroom checks the attenuation provided with WithHeadroom.
+func (o *options) validateHeadroom() error {
+	if o.headroom > 0 || math.IsNaN(o.headroom) || math.IsInf(o.headroom, 0) {
+		return fmt.Errorf("invalid headroom %v dB", o.headroom)
+	}
+	return nil
+}
+
+// attenuate applies the headroom to provided number of frames in place.
+func (r *Reader) attenuate(floats signal.Floating, frames int) {
+	if r.headroomGain == 0 {
+		return
+	}
+	for i := 0; i < frames*floats.Channels(); i++ {
+		floats.SetSample(i, floats.Sample(i)*r.headroomGain)
+	}
+}
@@ -0,0 +1,38 @@
+package wav_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestWithHeadroom(t *testing.T) {
+	// full scale samples of 32 bits.
+	pcm := []byte{0xFF, 0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x00, 0x80}
+	data := riff(chunk("fmt ", fmtPayload(1, 32, 4, 48000)), chunk("data", pcm))
+
+	r, err := wav.NewReader(bytes.NewReader(data), wav.WithHeadroom(-6))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Headroom() != -6 {
+		t.Errorf("expected headroom -6 got %v", r.Headroom())
+	}
+	gain := math.Pow(10, -6.0/20)
+	samples := readAll(t, r)
+	for i, expected := range []float64{gain, -gain} {
+		if math.Abs(samples[i]-expected) > 1e-9 {
+			t.Errorf("sample %d: expected %v got %v", i, expected, samples[i])
+		}
+	}
+
+	r, _ = wav.NewReader(bytes.NewReader(data))
+	if samples := readAll(t, r); r.Headroom() != 0 || samples[1] != -1 {
+		t.Errorf("unexpected attenuation by default: %v", samples)
+	}
+	if _, err := wav.NewReader(bytes.NewReader(data), wav.WithHeadroom(3)); err == nil {
+		t.Errorf("expected error for positive headroom")
+	}
+}
@@ -0,0 +1,162 @@
+package wav
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CodingHistoryEntry is the line of bext coding history, as defined by
+// EBU R 98. Each line describes one step of the processing chain.
+type CodingHistoryEntry struct {
+	// Algorithm is the coding algorithm, e.g. "ANALOGUE" or "PCM".
+	Algorithm string
+	// Frequency is the sample rate in Hz.
+	Frequency int
+	// BitRate is the bit rate of compressed formats in kbit/s.
+	BitRate int
+	// WordLength is the number of bits per sample.
+	WordLength int
+	// Mode is the channel mode, e.g. "mono" or "stereo".
+	Mode string
+	// Text is the free text, e.g. the name of the device.
+	Text string
+	// Raw is the line as it's stored in the history. Lines that don't
+	// follow the convention have only Raw set.
+	Raw string
+}
+
+// PCMCodingHistoryEntry returns the entry that describes linear PCM
+// stream of provided format, e.g. to record transcoding step.
+func PCMCodingHistoryEntry(f Format, text string) CodingHistoryEntry {
+	mode := "multitrack"
+	switch f.Channels {
+	case 1:
+		mode = "mono"
+	case 2:
+		mode = "stereo"
+	}
+	return CodingHistoryEntry{
+		Algorithm:  "PCM",
+		Frequency:  int(f.SampleRate),
+		WordLength: int(f.BitDepth),
+		Mode:       mode,
+		Text:       text,
+	}
+}
+
+// String returns the line of coding history without line ending. Fields
+// are written in the conventional order, empty fields are omitted. Raw
+// line is returned if the entry has no fields.
+func (e CodingHistoryEntry) String() string {
+	var fields []string
+	add := func(key, value string) {
+		if value != "" {
+			fields = append(fields, key+"="+value)
+		}
+	}
+	number := func(v int) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	}
+	add("A", e.Algorithm)
+	add("F", number(e.Frequency))
+	add("B", number(e.BitRate))
+	add("W", number(e.WordLength))
+	add("M", e.Mode)
+	add("T", e.Text)
+	if len(fields) == 0 {
+		return e.Raw
+	}
+	return strings.Join(fields, ",")
+}
+
+// FormatCodingHistory returns coding history with provided entries. Every
+// line is terminated with CR/LF as EBU R 98 requires.
+func FormatCodingHistory(entries []CodingHistoryEntry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString(e.String())
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// AppendCodingHistory adds the entry to the end of coding history. Line
+// ending is added to the last line if it's missing.
+func (b *Bext) AppendCodingHistory(e CodingHistoryEntry) {
+	if b.CodingHistory != "" && !strings.HasSuffix(b.CodingHistory, "\n") {
+		b.CodingHistory += "\r\n"
+	}
+	b.CodingHistory += FormatCodingHistory([]CodingHistoryEntry{e})
+}
+
+// CodingHistoryEntries returns the coding history split into entries. The
+// raw history is kept in CodingHistory field.
+func (b *Bext) CodingHistoryEntries() []CodingHistoryEntry {
+	return ParseCodingHistory(b.CodingHistory)
+}
+
+// ParseCodingHistory splits coding history into entries. Empty lines are
+// skipped, malformed lines are kept as raw entries.
+func ParseCodingHistory(history string) []CodingHistoryEntry {
+	var entries []CodingHistoryEntry
+	for _, line := range strings.Split(history, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		entry, ok := parseCodingHistoryLine(line)
+		if !ok {
+			entry = CodingHistoryEntry{}
+		}
+		entry.Raw = line
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseCodingHistoryLine parses the fields of the line. False is returned
+// if the line doesn't follow the convention.
+func parseCodingHistoryLine(line string) (CodingHistoryEntry, bool) {
+	var (
+		e   CodingHistoryEntry
+		err error
+	)
+	for len(line) > 0 {
+		if len(line) < 2 || line[1] != '=' {
+			return e, false
+		}
+		key := line[0]
+		// text is the last field and can contain commas.
+		if key == 'T' {
+			e.Text = line[2:]
+			return e, true
+		}
+		value := line[2:]
+		if i := strings.IndexByte(value, ','); i != -1 {
+			value, line = value[:i], value[i+1:]
+		} else {
+			line = ""
+		}
+		switch key {
+		case 'A':
+			e.Algorithm = value
+		case 'F':
+			e.Frequency, err = strconv.Atoi(value)
+		case 'B':
+			e.BitRate, err = strconv.Atoi(value)
+		case 'W':
+			e.WordLength, err = strconv.Atoi(value)
+		case 'M':
+			e.Mode = value
+		default:
+			return e, false
+		}
+		if err != nil {
+			return e, false
+		}
+	}
+	return e, true
+}
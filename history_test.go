@@ -0,0 +1,81 @@
+package wav_test
+
+import (
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestParseCodingHistory(t *testing.T) {
+	history := "A=ANALOGUE,M=stereo,T=Studer A816; SN1007; 38; telcom; Agfa PER528\r\n" +
+		"A=PCM,F=48000,W=18,M=stereo,T=NVision; NV1000; A/D\r\n" +
+		"\r\n" +
+		"processed by hand\r\n" +
+		"A=MPEG1L2,F=48000,B=192,W=bad,M=stereo\r\n" +
+		"A=PCM,F=48000,W=24,M=mono\n"
+	expected := []wav.CodingHistoryEntry{
+		{
+			Algorithm: "ANALOGUE",
+			Mode:      "stereo",
+			Text:      "Studer A816; SN1007; 38; telcom; Agfa PER528",
+			Raw:       "A=ANALOGUE,M=stereo,T=Studer A816; SN1007; 38; telcom; Agfa PER528",
+		},
+		{
+			Algorithm:  "PCM",
+			Frequency:  48000,
+			WordLength: 18,
+			Mode:       "stereo",
+			Text:       "NVision; NV1000; A/D",
+			Raw:        "A=PCM,F=48000,W=18,M=stereo,T=NVision; NV1000; A/D",
+		},
+		{Raw: "processed by hand"},
+		{Raw: "A=MPEG1L2,F=48000,B=192,W=bad,M=stereo"},
+		{
+			Algorithm:  "PCM",
+			Frequency:  48000,
+			WordLength: 24,
+			Mode:       "mono",
+			Raw:        "A=PCM,F=48000,W=24,M=mono",
+		},
+	}
+	b := wav.Bext{CodingHistory: history}
+	if entries := b.CodingHistoryEntries(); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected %+v got %+v", expected, entries)
+	}
+	if entries := wav.ParseCodingHistory(""); entries != nil {
+		t.Errorf("unexpected entries of empty history %+v", entries)
+	}
+}
+
+func TestFormatCodingHistory(t *testing.T) {
+	entries := []wav.CodingHistoryEntry{
+		{Algorithm: "ANALOGUE", Mode: "stereo", Text: "Studer A816, SN1007"},
+		{Algorithm: "MPEG1L2", Frequency: 48000, BitRate: 192, Mode: "stereo"},
+		wav.PCMCodingHistoryEntry(wav.Format{SampleRate: 48000, Channels: 1, BitDepth: 24}, "transcoded"),
+		{Raw: "processed by hand"},
+	}
+	history := wav.FormatCodingHistory(entries)
+	expected := "A=ANALOGUE,M=stereo,T=Studer A816, SN1007\r\n" +
+		"A=MPEG1L2,F=48000,B=192,M=stereo\r\n" +
+		"A=PCM,F=48000,W=24,M=mono,T=transcoded\r\n" +
+		"processed by hand\r\n"
+	if history != expected {
+		t.Errorf("expected %q got %q", expected, history)
+	}
+	parsed := wav.ParseCodingHistory(history)
+	for i := range parsed {
+		if entries[i].Raw == "" {
+			parsed[i].Raw = ""
+		}
+	}
+	if !reflect.DeepEqual(parsed, entries) {
+		t.Errorf("expected %+v got %+v", entries, parsed)
+	}
+
+	b := wav.Bext{CodingHistory: "A=ANALOGUE,M=mono"}
+	b.AppendCodingHistory(wav.CodingHistoryEntry{Algorithm: "PCM", Frequency: 44100, WordLength: 16, Mode: "mono"})
+	if expected := "A=ANALOGUE,M=mono\r\nA=PCM,F=44100,W=16,M=mono\r\n"; b.CodingHistory != expected {
+		t.Errorf("expected %q got %q", expected, b.CodingHistory)
+	}
+}
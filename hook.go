@@ -0,0 +1,28 @@
+package wav
+
+import "pipelined.dev/signal"
+
+// BufferFunc processes the buffer in place.
+type BufferFunc func(signal.Floating)
+
+// WithBufferFunc makes Source and Sink call provided function on every
+// buffer. Source calls it after the frames are decoded and Sink calls it
+// before the frames are encoded, so the function can be used for simple
+// processing, e.g. gain automation. The buffer contains only the frames
+// being read or written, interleaved. Sink modifies provided buffer.
+func WithBufferFunc(fn BufferFunc) Option {
+	return func(o *options) {
+		o.bufferFunc = fn
+	}
+}
+
+// process calls the function on provided number of frames of the buffer.
+func (fn BufferFunc) process(floats signal.Floating, frames int) {
+	if fn == nil || frames == 0 {
+		return
+	}
+	if frames != floats.Length() {
+		floats = floats.Slice(0, frames)
+	}
+	fn(floats)
+}
@@ -0,0 +1,42 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestBufferFunc(t *testing.T) {
+	samples := []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5}
+	// mute the second channel.
+	mute := func(floats signal.Floating) {
+		for i := 0; i < floats.Length(); i++ {
+			floats.SetSample(i*2+1, 0)
+		}
+	}
+	var muted buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&muted, signal.BitDepth16, wav.WithBufferFunc(mute)))
+	expected := []int16{16383, 0, 16383, 0, 16383, 0}
+	if result := int16Samples(muted.data); !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected %v got %v", expected, result)
+	}
+
+	var plain, decoded buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&plain, signal.BitDepth16))
+	frames := 0
+	count := func(floats signal.Floating) {
+		frames += floats.Length()
+		mute(floats)
+	}
+	transcode(t, wav.Source(bytes.NewReader(plain.data), wav.WithBufferFunc(count)), wav.Sink(&decoded, signal.BitDepth16))
+	if result := int16Samples(decoded.data); !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected %v got %v", expected, result)
+	}
+	if frames != 3 {
+		t.Errorf("expected 3 frames got %d", frames)
+	}
+}
@@ -0,0 +1,27 @@
+package wav
+
+import "io"
+
+// WithID3 makes Sink write provided ID3v2 tag as "id3 " chunk after the
+// data chunk.
+func WithID3(tag []byte) Option {
+	return func(o *options) {
+		o.id3 = tag
+	}
+}
+
+// ReadID3 returns the raw ID3v2 tag stored in "id3 " or "ID3 " chunk.
+// Nil is returned if the stream doesn't have such chunk. The stream is
+// rewinded to the start afterwards.
+func ReadID3(rs io.ReadSeeker) ([]byte, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range c.chunks {
+		if h.ID == id3ID || h.ID == [4]byte{'I', 'D', '3', ' '} {
+			return readPayload(rs, h)
+		}
+	}
+	return nil, nil
+}
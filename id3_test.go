@@ -0,0 +1,61 @@
+package wav_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestID3(t *testing.T) {
+	inFile, err := os.Open(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer inFile.Close()
+
+	tag, err := wav.ReadID3(inFile)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if len(tag) != 56 || !bytes.HasPrefix(tag, []byte("ID3")) {
+		t.Fatalf("unexpected tag: %q", tag)
+	}
+
+	tests := []struct {
+		source   func() pipe.SourceAllocatorFunc
+		bitDepth signal.BitDepth
+	}{
+		{
+			source:   func() pipe.SourceAllocatorFunc { return wav.Source(inFile) },
+			bitDepth: signal.BitDepth16,
+		},
+		{
+			// odd data size must be padded.
+			source:   func() pipe.SourceAllocatorFunc { return floatSource(8000, 1, []float64{0, 0.5, 1}) },
+			bitDepth: signal.BitDepth8,
+		},
+	}
+	for _, test := range tests {
+		var out buffer
+		transcode(t, test.source(), wav.Sink(&out, test.bitDepth, wav.WithID3(tag[:55])))
+		result, err := wav.ReadID3(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		if !bytes.Equal(tag[:55], result) {
+			t.Errorf("unexpected tag: %q", result)
+		}
+		var discard buffer
+		transcode(t, wav.Source(bytes.NewReader(out.data)), wav.Sink(&discard, test.bitDepth))
+	}
+
+	notag, err := wav.ReadID3(bytes.NewReader(riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)))))
+	if err != nil || notag != nil {
+		t.Errorf("expected no tag, got: %q %v", notag, err)
+	}
+}
@@ -0,0 +1,66 @@
+package wav
+
+import "pipelined.dev/signal"
+
+// unsignedOffset is the value of silence in unsigned 8-bit samples.
+const unsignedOffset = 128
+
+// Justification is the placement of decoded sample in wider 32-bit
+// sample.
+type Justification int
+
+const (
+	// RightJustified keeps the value of the sample, unused high bits are
+	// sign bits.
+	RightJustified Justification = iota
+	// LeftJustified places the sample in high bits, unused low bits are
+	// zero. The magnitude relative to full scale is kept.
+	LeftJustified
+)
+
+// WithInt32 makes Reader.ReadSigned decode the frames of any bit depth
+// into 32-bit buffers with provided justification. Unsigned 8-bit samples
+// are converted to signed, so ReadSigned accepts every linear PCM stream.
+func WithInt32(j Justification) Option {
+	return func(o *options) {
+		o.justification = &j
+	}
+}
+
+// readInt32 reads the frames of stream bit depth and justifies them into
+// 32-bit buffer.
+func (r *Reader) readInt32(dst signal.Signed) (int, error) {
+	var shift uint
+	if *r.justification == LeftJustified {
+		shift = uint(signal.BitDepth32 - r.format.BitDepth)
+	}
+	frames := dst.Length()
+	if r.unsignedSamples() {
+		read, err := r.readFixed(frames)
+		if err != nil {
+			return 0, err
+		}
+		for i := 0; i < read; i++ {
+			dst.SetSample(i, int64(r.pcm.Data[i]-unsignedOffset)<<shift)
+		}
+		return signal.ChannelLength(read, r.format.Channels), nil
+	}
+
+	r.sizePCM(frames)
+	var (
+		n   int
+		err error
+	)
+	if r.codec != nil {
+		n, err = r.codec.read(r.signed, frames)
+	} else {
+		n, err = r.readSignedFixed(r.signed.Slice(0, frames))
+	}
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < n*r.format.Channels; i++ {
+		dst.SetSample(i, r.signed.Sample(i)<<shift)
+	}
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// readInt32 reads all frames of the stream as 32-bit samples.
+func readInt32(t *testing.T, data []byte, j wav.Justification) []int32 {
+	t.Helper()
+	r, err := wav.NewReader(bytes.NewReader(data), wav.WithInt32(j))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: r.Format().Channels, Length: 3, Capacity: 3}.Int32(signal.BitDepth32)
+	var samples []int32
+	for {
+		n, err := r.ReadSigned(buf)
+		if err == io.EOF {
+			return samples
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < n*buf.Channels(); i++ {
+			samples = append(samples, int32(buf.Sample(i)))
+		}
+	}
+}
+
+func TestWithInt32(t *testing.T) {
+	pcm16 := []byte{0x01, 0x00, 0xFF, 0xFF, 0xFF, 0x7F, 0x00, 0x80, 0x00, 0x40}
+	data16 := riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("data", pcm16))
+	values16 := []int32{1, -1, 1<<15 - 1, -1 << 15, 1 << 14}
+
+	left := readInt32(t, data16, wav.LeftJustified)
+	if len(left) != len(values16) {
+		t.Fatalf("expected %d samples got %d", len(values16), len(left))
+	}
+	for i, v := range values16 {
+		// magnitude relative to full scale is preserved.
+		if expected, result := float64(v)/(1<<15), float64(left[i])/(1<<31); expected != result {
+			t.Errorf("sample %d: expected magnitude %v got %v", i, expected, result)
+		}
+	}
+	if right := readInt32(t, data16, wav.RightJustified); !reflect.DeepEqual(right, values16) {
+		t.Errorf("expected right-justified %v got %v", values16, right)
+	}
+
+	data8 := riff(chunk("fmt ", fmtPayload(2, 8, 2, 8000)), chunk("data", []byte{0, 128, 255, 127}))
+	if left := readInt32(t, data8, wav.LeftJustified); !reflect.DeepEqual(left, []int32{-1 << 31, 0, 127 << 24, -1 << 24}) {
+		t.Errorf("unexpected left-justified 8-bit samples %v", left)
+	}
+	if right := readInt32(t, data8, wav.RightJustified); !reflect.DeepEqual(right, []int32{-128, 0, 127, -1}) {
+		t.Errorf("unexpected right-justified 8-bit samples %v", right)
+	}
+
+	pcm24 := []byte{0x56, 0x34, 0x12, 0x00, 0x00, 0x80}
+	data24 := riff(chunk("fmt ", fmtPayload(1, 24, 3, 48000)), chunk("data", pcm24))
+	if left := readInt32(t, data24, wav.LeftJustified); !reflect.DeepEqual(left, []int32{0x12345600, -1 << 31}) {
+		t.Errorf("unexpected left-justified 24-bit samples %v", left)
+	}
+
+	r, _ := wav.NewReader(bytes.NewReader(data16), wav.WithInt32(wav.LeftJustified))
+	if _, err := r.ReadSigned(signal.Allocator{Channels: 1, Length: 2, Capacity: 2}.Int16(signal.BitDepth16)); err == nil {
+		t.Errorf("expected error for 16-bit buffer")
+	}
+}
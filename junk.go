@@ -0,0 +1,13 @@
+package wav
+
+// ds64Size is the size of ds64 payload without the table of chunk sizes.
+const ds64Size = 28
+
+// ReserveRF64 makes Sink write a JUNK chunk right after RIFF header. The
+// chunk has the size of ds64 chunk, so the stream can be upgraded to RF64
+// in place once it exceeds 4 GiB. JUNK chunks are skipped by Source.
+func ReserveRF64() Option {
+	return func(o *options) {
+		o.reserveRF64 = true
+	}
+}
@@ -0,0 +1,47 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestJunk(t *testing.T) {
+	pcm := []byte{0x00, 0x10, 0x00, 0xF0, 0x34, 0x12, 0xCC, 0xED}
+	plain := riff(chunk("fmt ", fmtPayload(2, 16, 4, 44100)), chunk("data", pcm))
+	var expected buffer
+	transcode(t, wav.Source(bytes.NewReader(plain)), wav.Sink(&expected, signal.BitDepth16))
+
+	tests := map[string][]byte{
+		"before fmt": riff(chunk("JUNK", make([]byte, 28)), chunk("fmt ", fmtPayload(2, 16, 4, 44100)), chunk("data", pcm)),
+		"odd size":   riff(chunk("JUNK", make([]byte, 27)), chunk("fmt ", fmtPayload(2, 16, 4, 44100)), chunk("data", pcm)),
+		"after fmt":  riff(chunk("fmt ", fmtPayload(2, 16, 4, 44100)), chunk("JUNK", make([]byte, 100)), chunk("data", pcm)),
+	}
+	for name, data := range tests {
+		var result buffer
+		transcode(t, wav.Source(bytes.NewReader(data)), wav.Sink(&result, signal.BitDepth16))
+		if !reflect.DeepEqual(int16Samples(expected.data), int16Samples(result.data)) {
+			t.Errorf("%s: expected %v got %v", name, int16Samples(expected.data), int16Samples(result.data))
+		}
+	}
+}
+
+func TestReserveRF64(t *testing.T) {
+	samples := []float64{0.5, -0.5, 0.25, -0.25}
+	var reserved buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&reserved, signal.BitDepth16, wav.ReserveRF64()))
+	if ids := chunkIDs(reserved.data); !reflect.DeepEqual(ids, []string{"JUNK", "fmt ", "data"}) {
+		t.Errorf("unexpected chunks: %v", ids)
+	}
+
+	var plain, decoded buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&plain, signal.BitDepth16))
+	transcode(t, wav.Source(bytes.NewReader(reserved.data)), wav.Sink(&decoded, signal.BitDepth16))
+	if !reflect.DeepEqual(int16Samples(plain.data), int16Samples(decoded.data)) {
+		t.Errorf("expected %v got %v", int16Samples(plain.data), int16Samples(decoded.data))
+	}
+}
@@ -0,0 +1,47 @@
+package wav
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// SkipLeadingBytes makes Source look for RIFF header within provided
+// number of bytes from the start of the stream, e.g. after a stray UTF-8
+// byte order mark of downloaded file. The bytes before the header are
+// ignored and positions of the stream are relative to the header.
+// ErrInvalidWav is returned if the header isn't found within the limit,
+// so the scan stays short even for large streams that aren't wav.
+func SkipLeadingBytes(limit int) Option {
+	return func(o *options) {
+		o.leadingLimit = limit
+	}
+}
+
+// skipLeading returns the stream that starts at the first RIFF header
+// within the limit of leading bytes.
+func (o *options) skipLeading(rs io.ReadSeeker) (io.ReadSeeker, error) {
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking stream start: %w", err)
+	}
+	// header must start within the limit and have its form type.
+	head := make([]byte, o.leadingLimit+12)
+	n, err := io.ReadFull(rs, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, ErrInvalidWav
+		}
+		return nil, fmt.Errorf("error reading leading bytes: %w", err)
+	}
+	head = head[:n]
+	formType := o.riffFormType()
+	for offset := 0; offset+12 <= len(head); offset++ {
+		if bytes.Equal(head[offset:offset+4], riffID[:]) && bytes.Equal(head[offset+8:offset+12], formType[:]) {
+			if _, err := rs.Seek(0, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("error seeking stream start: %w", err)
+			}
+			return NewEmbeddedReader(rs, int64(offset), 0), nil
+		}
+	}
+	return nil, ErrInvalidWav
+}
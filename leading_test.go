@@ -0,0 +1,51 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestSkipLeadingBytes(t *testing.T) {
+	data := riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", rampData(10)))
+	expected := readAll(t, mustReader(t, data))
+	bom := []byte{0xEF, 0xBB, 0xBF}
+	tests := []struct {
+		name    string
+		leading []byte
+		limit   int
+		valid   bool
+	}{
+		{name: "no leading bytes", limit: 16, valid: true},
+		{name: "byte order mark", leading: bom, limit: 16, valid: true},
+		{name: "within limit", leading: bytes.Repeat([]byte{0}, 16), limit: 16, valid: true},
+		{name: "false magic", leading: []byte("RIFFxxxxJUNK"), limit: 16, valid: true},
+		{name: "beyond limit", leading: bytes.Repeat([]byte{0}, 17), limit: 16},
+	}
+	for _, test := range tests {
+		stream := append(append([]byte(nil), test.leading...), data...)
+		r, err := wav.NewReader(bytes.NewReader(stream), wav.SkipLeadingBytes(test.limit))
+		if !test.valid {
+			if err != wav.ErrInvalidWav {
+				t.Errorf("%s: expected invalid wav error got %v", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if samples := readAll(t, r); !reflect.DeepEqual(samples, expected) {
+			t.Errorf("%s: expected %v got %v", test.name, expected, samples)
+		}
+	}
+
+	// leading bytes aren't skipped by default.
+	if _, err := wav.NewReader(bytes.NewReader(append(bom, data...))); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %v", err)
+	}
+	if _, err := wav.NewReader(bytes.NewReader(bom), wav.SkipLeadingBytes(16)); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error for short stream got %v", err)
+	}
+}
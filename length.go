@@ -0,0 +1,35 @@
+package wav
+
+// WithExactLength makes Sink write exactly provided number of frames. If
+// the input is shorter, it's padded with digital silence when the sink is
+// flushed. If the input is longer, frames beyond the length are
+// discarded. The input length doesn't need to be known up front: the
+// decision is made while the buffers are written, pre-roll silence is
+// counted as well.
+func WithExactLength(frames int) Option {
+	return func(o *options) {
+		o.exactLength = &frames
+	}
+}
+
+// limit returns the part of the buffer that fits the exact length.
+func (w *Writer) limit(frames int) int {
+	if w.exactLength == nil {
+		return frames
+	}
+	if remaining := *w.exactLength - w.frames; remaining < frames {
+		if remaining < 0 {
+			return 0
+		}
+		return remaining
+	}
+	return frames
+}
+
+// pad writes the silence that completes the exact length.
+func (w *Writer) pad() error {
+	if w.exactLength == nil || w.frames >= *w.exactLength {
+		return nil
+	}
+	return w.writeSilence(*w.exactLength - w.frames)
+}
@@ -0,0 +1,65 @@
+package wav_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestExactLength(t *testing.T) {
+	samples := make([]float64, 1000)
+	for i := range samples {
+		samples[i] = 0.5
+	}
+	tests := []struct {
+		length   int
+		options  []wav.Option
+		expected int
+		silent   int
+	}{
+		{
+			length:   700,
+			expected: 700,
+		},
+		{
+			length:   1300,
+			expected: 1300,
+			silent:   300,
+		},
+		{
+			length:   1000,
+			expected: 1000,
+		},
+		{
+			length:   1100,
+			options:  []wav.Option{wav.WithPreroll(200 * time.Millisecond)},
+			expected: 1100,
+		},
+	}
+	for _, test := range tests {
+		var out buffer
+		options := append(test.options, wav.WithExactLength(test.length))
+		transcode(t,
+			floatSource(1000, 1, samples),
+			wav.Sink(&out, signal.BitDepth16, options...),
+		)
+		result := int16Samples(out.data)
+		if len(result) != test.expected {
+			t.Errorf("length %d: expected %d frames got %d", test.length, test.expected, len(result))
+			continue
+		}
+		if test.silent > 0 {
+			tail := result[len(result)-test.silent:]
+			if !reflect.DeepEqual(tail, make([]int16, test.silent)) {
+				t.Errorf("length %d: padding isn't silent", test.length)
+			}
+			if result[len(result)-test.silent-1] == 0 {
+				t.Errorf("length %d: input is padded too early", test.length)
+			}
+		}
+	}
+}
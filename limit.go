@@ -0,0 +1,30 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFrameLimit is returned by Source when the stream has more frames than
+// allowed by WithMaxFrames.
+var ErrFrameLimit = errors.New("frame limit exceeded")
+
+// WithMaxFrames limits the number of frames that Source decodes. Source
+// allocation fails with ErrFrameLimit if data chunk declares more frames.
+// This protects from untrusted files that can exhaust resources.
+func WithMaxFrames(n int64) Option {
+	return func(o *options) {
+		o.maxFrames = n
+	}
+}
+
+// checkFrames returns error if declared number of frames exceeds the limit.
+func (o *options) checkFrames(c container, f format) error {
+	if o.maxFrames <= 0 {
+		return nil
+	}
+	if frames := c.info(f).Frames; frames > o.maxFrames {
+		return fmt.Errorf("%w: %d frames declared, limit is %d", ErrFrameLimit, frames, o.maxFrames)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package wav_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestMaxFrames(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 8000)),
+		chunk("data", make([]byte, 20)),
+	)
+	var out buffer
+	transcode(t, wav.Source(bytes.NewReader(data), wav.WithMaxFrames(10)), wav.Sink(&out, signal.BitDepth16))
+
+	_, err := pipe.New(bufferSize, pipe.Line{
+		Source: wav.Source(bytes.NewReader(data), wav.WithMaxFrames(9)),
+		Sink:   wav.Sink(&buffer{}, signal.BitDepth16),
+	})
+	if !errors.Is(err, wav.ErrFrameLimit) {
+		t.Errorf("expected frame limit error for declared size, got %v", err)
+	}
+}
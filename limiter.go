@@ -0,0 +1,169 @@
+package wav
+
+import (
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// Time constants of the limiter in seconds.
+const (
+	limiterLookahead = 0.0015
+	limiterRelease   = 0.05
+)
+
+// WithTruePeakLimit makes Normalize and LoudnessNormalize limit the peaks
+// of normalized stream to provided ceiling in dBTP. Peaks between samples
+// are estimated with cubic interpolation at 4x oversampling. The limiter
+// has lookahead, so the gain is reduced smoothly before the peak.
+func WithTruePeakLimit(ceilingDB float64) Option {
+	return func(o *options) {
+		o.truePeakLimit = &ceilingDB
+	}
+}
+
+// limiter is the lookahead peak limiter. Frames are delayed by the
+// lookahead, so the gain can be reduced along a linear ramp before the
+// peak.
+type limiter struct {
+	channels  int
+	lookahead int
+	ceiling   float64
+	release   float64
+	gain      float64
+	// delayed frames and the gains they require, oldest first.
+	frames   []float64
+	required []float64
+	// last three input frames, oldest first.
+	history []float64
+	// frame is the buffer of the input frame.
+	frame []float64
+	out   signal.Floating
+}
+
+func newLimiter(f Format, ceilingDB float64, bufferSize int) *limiter {
+	lookahead := int(math.Ceil(float64(f.SampleRate) * limiterLookahead))
+	if lookahead < 4 {
+		lookahead = 4
+	}
+	return &limiter{
+		channels:  f.Channels,
+		lookahead: lookahead,
+		ceiling:   math.Pow(10, ceilingDB/20),
+		release:   1 - math.Exp(-1/(float64(f.SampleRate)*limiterRelease)),
+		gain:      1,
+		frame:     make([]float64, f.Channels),
+		out: signal.Allocator{
+			Channels: f.Channels,
+			Length:   bufferSize,
+			Capacity: bufferSize,
+		}.Float64(),
+	}
+}
+
+// write limits provided frames and writes the frames that left the
+// lookahead.
+func (l *limiter) write(w *Writer, floats signal.Floating, frames int) error {
+	n := 0
+	for i := 0; i < frames; i++ {
+		for c := range l.frame {
+			l.frame[c] = floats.Sample(i*l.channels + c)
+		}
+		l.push(l.frame)
+		if len(l.required) > l.lookahead {
+			l.pop(n)
+			n++
+		}
+		if n == l.out.Length() {
+			if _, err := w.Write(l.out); err != nil {
+				return err
+			}
+			n = 0
+		}
+	}
+	if n > 0 {
+		if _, err := w.Write(l.out.Slice(0, n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush writes the frames left in the lookahead.
+func (l *limiter) flush(w *Writer) error {
+	n := 0
+	for len(l.required) > 0 {
+		l.pop(n)
+		n++
+		if n == l.out.Length() || len(l.required) == 0 {
+			if _, err := w.Write(l.out.Slice(0, n)); err != nil {
+				return err
+			}
+			n = 0
+		}
+	}
+	return nil
+}
+
+// push adds the frame to the lookahead.
+func (l *limiter) push(frame []float64) {
+	l.frames = append(l.frames, frame...)
+	l.required = append(l.required, l.requiredGain(maxAbs(frame)))
+
+	l.history = append(l.history, frame...)
+	if len(l.history) < 4*l.channels {
+		return
+	}
+	var peak float64
+	for c := 0; c < l.channels; c++ {
+		p0, p1, p2, p3 := l.history[c], l.history[l.channels+c], l.history[2*l.channels+c], frame[c]
+		for _, u := range []float64{0.25, 0.5, 0.75} {
+			peak = math.Max(peak, math.Abs(catmullRom(p0, p1, p2, p3, u)))
+		}
+	}
+	// the peak is between two previous frames.
+	gain := l.requiredGain(peak)
+	last := len(l.required) - 2
+	l.required[last] = math.Min(l.required[last], gain)
+	l.required[last-1] = math.Min(l.required[last-1], gain)
+	l.history = l.history[l.channels:]
+}
+
+// pop writes the oldest frame of the lookahead into provided frame of
+// output buffer.
+func (l *limiter) pop(i int) {
+	gain := l.gain + (1-l.gain)*l.release
+	for k, required := range l.required {
+		ramp := required + (1-required)*float64(k)/float64(l.lookahead+1)
+		gain = math.Min(gain, ramp)
+	}
+	for c := 0; c < l.channels; c++ {
+		l.out.SetSample(i*l.channels+c, l.frames[c]*gain)
+	}
+	l.gain = gain
+	l.frames = l.frames[l.channels:]
+	l.required = l.required[1:]
+}
+
+// requiredGain returns the gain that keeps provided peak below the
+// ceiling.
+func (l *limiter) requiredGain(peak float64) float64 {
+	if peak <= l.ceiling {
+		return 1
+	}
+	return l.ceiling / peak
+}
+
+// maxAbs returns the maximum absolute value.
+func maxAbs(values []float64) float64 {
+	var max float64
+	for _, v := range values {
+		max = math.Max(max, math.Abs(v))
+	}
+	return max
+}
+
+// catmullRom returns the value of Catmull-Rom spline between p1 and p2.
+func catmullRom(p0, p1, p2, p3, u float64) float64 {
+	return 0.5 * (2*p1 + (p2-p0)*u + (2*p0-5*p1+4*p2-p3)*u*u + (3*p1-p0-3*p2+p3)*u*u*u)
+}
@@ -0,0 +1,155 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Form types of LIST chunks.
+var (
+	infoType = [4]byte{'I', 'N', 'F', 'O'}
+	adtlType = [4]byte{'a', 'd', 't', 'l'}
+)
+
+// cueID is the identifier of cue points chunk.
+var cueID = [4]byte{'c', 'u', 'e', ' '}
+
+// Cue is the cue point of the stream with its associated data.
+type Cue struct {
+	ID uint32
+	// Position is the offset of the cue point in frames.
+	Position uint32
+	// Label and Note are the texts of "labl" and "note" chunks of
+	// associated data list.
+	Label string
+	Note  string
+}
+
+// ReadInfo returns the text fields of all LIST chunks of INFO type, keyed
+// by their identifiers, e.g. "INAM" or "IART". Nil is returned if the
+// stream doesn't have INFO list. The stream is rewinded to the start
+// afterwards.
+func ReadInfo(rs io.ReadSeeker) (map[string]string, error) {
+	lists, err := readLists(rs, infoType)
+	if err != nil {
+		return nil, err
+	}
+	var info map[string]string
+	for _, list := range lists {
+		for _, c := range list {
+			if info == nil {
+				info = make(map[string]string)
+			}
+			info[string(c.ID[:])] = listString(c.Payload)
+		}
+	}
+	return info, nil
+}
+
+// ReadCues returns the cue points of the stream. Labels and notes are
+// recovered from all LIST chunks of adtl type. Nil is returned if the
+// stream doesn't have cue chunk. The stream is rewinded to the start
+// afterwards.
+func ReadCues(rs io.ReadSeeker) ([]Cue, error) {
+	payload, ok, err := ReadChunk(rs, cueID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if len(payload) < 4 {
+		return nil, fmt.Errorf("cue chunk is too short: %d bytes", len(payload))
+	}
+	count := int(binary.LittleEndian.Uint32(payload))
+	if available := (len(payload) - 4) / 24; count > available {
+		return nil, fmt.Errorf("cue chunk declares %d points, but has %d", count, available)
+	}
+	cues := make([]Cue, count)
+	index := make(map[uint32]int, count)
+	for i := range cues {
+		p := payload[4+24*i:]
+		cues[i] = Cue{
+			ID:       binary.LittleEndian.Uint32(p[0:]),
+			Position: binary.LittleEndian.Uint32(p[20:]),
+		}
+		index[cues[i].ID] = i
+	}
+
+	lists, err := readLists(rs, adtlType)
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range lists {
+		for _, c := range list {
+			if len(c.Payload) < 4 {
+				continue
+			}
+			i, ok := index[binary.LittleEndian.Uint32(c.Payload)]
+			if !ok {
+				continue
+			}
+			switch string(c.ID[:]) {
+			case "labl":
+				cues[i].Label = listString(c.Payload[4:])
+			case "note":
+				cues[i].Note = listString(c.Payload[4:])
+			}
+		}
+	}
+	return cues, nil
+}
+
+// readLists returns the sub-chunks of every LIST chunk with provided form
+// type. The stream is rewinded to the start afterwards.
+func readLists(rs io.ReadSeeker, formType [4]byte) ([][]rawChunk, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return nil, err
+	}
+	var lists [][]rawChunk
+	for _, h := range c.chunks {
+		if h.ID != listID {
+			continue
+		}
+		payload, err := readPayload(rs, h)
+		if err != nil {
+			return nil, err
+		}
+		if len(payload) < 4 || !bytes.Equal(payload[:4], formType[:]) {
+			continue
+		}
+		lists = append(lists, listChunks(payload[4:]))
+	}
+	return lists, nil
+}
+
+// listChunks splits the payload of LIST chunk into sub-chunks. Truncated
+// sub-chunk ends the list.
+func listChunks(p []byte) []rawChunk {
+	var chunks []rawChunk
+	for len(p) >= 8 {
+		var c rawChunk
+		copy(c.ID[:], p)
+		size := int(binary.LittleEndian.Uint32(p[4:]))
+		if size > len(p)-8 {
+			break
+		}
+		c.Payload = p[8 : 8+size]
+		chunks = append(chunks, c)
+		if end := 8 + size + size%2; end < len(p) {
+			p = p[end:]
+		} else {
+			break
+		}
+	}
+	return chunks
+}
+
+// listString returns the text of list sub-chunk up to the terminating
+// zero.
+func listString(p []byte) string {
+	if i := bytes.IndexByte(p, 0); i != -1 {
+		p = p[:i]
+	}
+	return string(p)
+}
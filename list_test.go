@@ -0,0 +1,49 @@
+package wav_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+const wavLists = "_testdata/lists.wav"
+
+func TestLists(t *testing.T) {
+	f, err := os.Open(wavLists)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	// INFO list precedes data and adtl list follows cue chunk.
+	info, err := wav.ReadInfo(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedInfo := map[string]string{"INAM": "Take 3", "IART": "Foley"}
+	if !reflect.DeepEqual(info, expectedInfo) {
+		t.Errorf("expected info %v got %v", expectedInfo, info)
+	}
+	cues, err := wav.ReadCues(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedCues := []wav.Cue{
+		{ID: 1, Position: 2, Label: "door", Note: "close mic"},
+		{ID: 2, Position: 6, Label: "steps"},
+	}
+	if !reflect.DeepEqual(cues, expectedCues) {
+		t.Errorf("expected cues %+v got %+v", expectedCues, cues)
+	}
+
+	sample, err := os.Open(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer sample.Close()
+	if cues, err := wav.ReadCues(sample); err != nil || cues != nil {
+		t.Errorf("unexpected cues %+v: %v", cues, err)
+	}
+}
@@ -0,0 +1,184 @@
+package wav
+
+import (
+	"io"
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// Gating of integrated loudness as defined by ITU-R BS.1770-4.
+const (
+	loudnessBlock         = 0.4
+	loudnessStep          = 0.1
+	loudnessAbsoluteGate  = -70
+	loudnessRelativeGate  = -10
+	loudnessOffset        = -0.691
+	loudnessBlockSegments = int(loudnessBlock / loudnessStep)
+)
+
+// MeasureLoudness returns the integrated loudness of wav stream in LUFS,
+// as defined by ITU-R BS.1770-4 and EBU R 128. Channels are weighted as
+// front channels, except for 6 channels that are treated as 5.1 layout
+// with ignored LFE and weighted surround channels. Negative infinity is
+// returned for the stream shorter than 400 ms or too quiet to pass the
+// absolute gate. The stream is rewinded to the start afterwards.
+func MeasureLoudness(rs io.ReadSeeker) (float64, error) {
+	r, err := NewReader(rs)
+	if err != nil {
+		return 0, err
+	}
+	m := newLoudnessMeter(r.Format())
+	buf := signal.Allocator{
+		Channels: r.Format().Channels,
+		Length:   normalizeBufferSize,
+		Capacity: normalizeBufferSize,
+	}.Float64()
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		m.update(buf, n)
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return m.integrated(), nil
+}
+
+// biquad is the second-order IIR filter in direct form I.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func (f *biquad) filter(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x1, f.x2 = x, f.x1
+	f.y1, f.y2 = y, f.y1
+	return y
+}
+
+// kWeighting returns the pre-filter and RLB filter of K-weighting for
+// provided sample rate. Coefficients are derived with bilinear transform
+// of the analog prototypes, so they match the ones of BS.1770 at 48 kHz.
+func kWeighting(sampleRate signal.Frequency) (biquad, biquad) {
+	const (
+		shelfGain = 3.999843853973347
+		shelfQ    = 0.7071752369554196
+		shelfFreq = 1681.974450955533
+		passQ     = 0.5003270373238773
+		passFreq  = 38.13547087602444
+	)
+	vh := math.Pow(10, shelfGain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	k := math.Tan(math.Pi * shelfFreq / float64(sampleRate))
+	a0 := 1 + k/shelfQ + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/shelfQ + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/shelfQ + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/shelfQ + k*k) / a0,
+	}
+
+	k = math.Tan(math.Pi * passFreq / float64(sampleRate))
+	a0 = 1 + k/passQ + k*k
+	pass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/passQ + k*k) / a0,
+	}
+	return shelf, pass
+}
+
+// loudnessMeter accumulates K-weighted energy of 100 ms segments.
+type loudnessMeter struct {
+	weights []float64
+	shelf   []biquad
+	pass    []biquad
+	// segment size in frames and the number of frames and energy of the
+	// current segment.
+	segmentSize int
+	frames      int
+	energy      float64
+	segments    []float64
+}
+
+func newLoudnessMeter(f Format) *loudnessMeter {
+	m := loudnessMeter{
+		weights:     make([]float64, f.Channels),
+		shelf:       make([]biquad, f.Channels),
+		pass:        make([]biquad, f.Channels),
+		segmentSize: int(math.Round(float64(f.SampleRate) * loudnessStep)),
+	}
+	for c := range m.weights {
+		m.weights[c] = 1
+		m.shelf[c], m.pass[c] = kWeighting(f.SampleRate)
+	}
+	if f.Channels == 6 {
+		m.weights[3] = 0
+		m.weights[4], m.weights[5] = 1.41, 1.41
+	}
+	return &m
+}
+
+// update accumulates provided number of frames.
+func (m *loudnessMeter) update(floats signal.Floating, frames int) {
+	channels := len(m.weights)
+	for i := 0; i < frames; i++ {
+		for c := 0; c < channels; c++ {
+			v := m.pass[c].filter(m.shelf[c].filter(floats.Sample(i*channels + c)))
+			m.energy += m.weights[c] * v * v
+		}
+		m.frames++
+		if m.frames == m.segmentSize {
+			m.segments = append(m.segments, m.energy)
+			m.frames, m.energy = 0, 0
+		}
+	}
+}
+
+// integrated returns gated integrated loudness of complete blocks.
+func (m *loudnessMeter) integrated() float64 {
+	var blocks []float64
+	for i := 0; i+loudnessBlockSegments <= len(m.segments); i++ {
+		var energy float64
+		for _, e := range m.segments[i : i+loudnessBlockSegments] {
+			energy += e
+		}
+		blocks = append(blocks, energy/float64(loudnessBlockSegments*m.segmentSize))
+	}
+	gated := gateBlocks(blocks, loudnessAbsoluteGate)
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+	relative := blocksLoudness(gated) + loudnessRelativeGate
+	return blocksLoudness(gateBlocks(gated, relative))
+}
+
+// gateBlocks returns the blocks with loudness above the gate.
+func gateBlocks(blocks []float64, gate float64) []float64 {
+	var gated []float64
+	for _, b := range blocks {
+		if loudnessOffset+10*math.Log10(b) > gate {
+			gated = append(gated, b)
+		}
+	}
+	return gated
+}
+
+// blocksLoudness returns the loudness of mean energy of the blocks.
+func blocksLoudness(blocks []float64) float64 {
+	var sum float64
+	for _, b := range blocks {
+		sum += b
+	}
+	return loudnessOffset + 10*math.Log10(sum/float64(len(blocks)))
+}
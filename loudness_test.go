@@ -0,0 +1,56 @@
+package wav_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// sine returns interleaved samples of sine in every channel.
+func sine(sampleRate signal.Frequency, channels int, freq, amplitude float64, frames int) []float64 {
+	samples := make([]float64, 0, frames*channels)
+	for i := 0; i < frames; i++ {
+		v := amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+		for c := 0; c < channels; c++ {
+			samples = append(samples, v)
+		}
+	}
+	return samples
+}
+
+func TestMeasureLoudness(t *testing.T) {
+	tests := []struct {
+		sampleRate signal.Frequency
+		channels   int
+		amplitude  float64
+		expected   float64
+	}{
+		// full scale 1 kHz sine in one channel is -3.01 LUFS.
+		{48000, 1, 1, -3.01},
+		{44100, 1, 1, -3.01},
+		{48000, 2, 0.1, -20},
+		{48000, 1, 0.0001, math.Inf(-1)},
+	}
+	for _, test := range tests {
+		var in buffer
+		samples := sine(test.sampleRate, test.channels, 1000, test.amplitude, int(test.sampleRate)*2)
+		transcode(t, floatSource(test.sampleRate, test.channels, samples), wav.Sink(&in, signal.BitDepth24))
+		loudness, err := wav.MeasureLoudness(bytes.NewReader(in.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if math.IsInf(test.expected, -1) {
+			if !math.IsInf(loudness, -1) {
+				t.Errorf("expected gated loudness got %v", loudness)
+			}
+			continue
+		}
+		if math.Abs(loudness-test.expected) > 0.05 {
+			t.Errorf("%v Hz %d channels: expected %v LUFS got %v", test.sampleRate, test.channels, test.expected, loudness)
+		}
+	}
+}
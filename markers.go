@@ -0,0 +1,86 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// cuePointSize is the size of a single point of cue chunk.
+const cuePointSize = 24
+
+// Marker is the named position of the timeline.
+type Marker struct {
+	// Position is the offset of the marker in frames.
+	Position int64
+	Label    string
+}
+
+// SinkWithMarkers writes wav data to WriteSeeker as Sink does and writes
+// provided markers as cue chunk with "labl" chunks of adtl list after
+// data when it's flushed. Cue points have the identifiers from 1 in the
+// order of markers, the labels refer to the same identifiers. Markers
+// without label have only cue points. ReadCues recovers the markers.
+func SinkWithMarkers(ws io.WriteSeeker, bitDepth signal.BitDepth, markers []Marker, options ...Option) pipe.SinkAllocatorFunc {
+	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
+		chunks, err := markerChunks(markers)
+		if err != nil {
+			return pipe.Sink{}, err
+		}
+		markerOptions := append([]Option(nil), options...)
+		for _, c := range chunks {
+			markerOptions = append(markerOptions, WithChunk(c.ID, c.Payload, AfterData))
+		}
+		return Sink(ws, bitDepth, markerOptions...)(mctx, bufferSize, props)
+	}
+}
+
+// markerChunks returns cue chunk and adtl list of provided markers.
+func markerChunks(markers []Marker) ([]rawChunk, error) {
+	if len(markers) == 0 {
+		return nil, nil
+	}
+	cue := make([]byte, 4, 4+cuePointSize*len(markers))
+	binary.LittleEndian.PutUint32(cue, uint32(len(markers)))
+	adtl := append([]byte(nil), adtlType[:]...)
+	for i, m := range markers {
+		if m.Position < 0 || m.Position > math.MaxUint32 {
+			return nil, fmt.Errorf("invalid marker position %d", m.Position)
+		}
+		id := uint32(i + 1)
+		point := make([]byte, cuePointSize)
+		binary.LittleEndian.PutUint32(point[0:], id)
+		binary.LittleEndian.PutUint32(point[4:], uint32(m.Position))
+		copy(point[8:], dataID[:])
+		binary.LittleEndian.PutUint32(point[20:], uint32(m.Position))
+		cue = append(cue, point...)
+		if m.Label != "" {
+			adtl = appendLabel(adtl, id, m.Label)
+		}
+	}
+	chunks := []rawChunk{{ID: cueID, Payload: cue}}
+	if len(adtl) > len(adtlType) {
+		chunks = append(chunks, rawChunk{ID: listID, Payload: adtl})
+	}
+	return chunks, nil
+}
+
+// appendLabel appends "labl" sub-chunk with zero-terminated text to the
+// payload of adtl list.
+func appendLabel(p []byte, id uint32, label string) []byte {
+	size := 4 + len(label) + 1
+	header := make([]byte, 12)
+	copy(header, "labl")
+	binary.LittleEndian.PutUint32(header[4:], uint32(size))
+	binary.LittleEndian.PutUint32(header[8:], id)
+	p = append(append(append(p, header...), label...), 0)
+	if size%2 == 1 {
+		p = append(p, 0)
+	}
+	return p
+}
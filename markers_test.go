@@ -0,0 +1,56 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestSinkWithMarkers(t *testing.T) {
+	markers := []wav.Marker{
+		{Position: 0, Label: "start"},
+		{Position: 3, Label: "odd"},
+		{Position: 4},
+		{Position: 7, Label: "even"},
+	}
+	var out buffer
+	transcode(t, floatSource(44100, 2, make([]float64, 16)), wav.SinkWithMarkers(&out, signal.BitDepth16, markers))
+	if ids := chunkIDs(out.data); !reflect.DeepEqual(ids, []string{"fmt ", "data", "cue ", "LIST"}) {
+		t.Fatalf("unexpected chunks %v", ids)
+	}
+	cues, err := wav.ReadCues(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []wav.Cue{
+		{ID: 1, Position: 0, Label: "start"},
+		{ID: 2, Position: 3, Label: "odd"},
+		{ID: 3, Position: 4},
+		{ID: 4, Position: 7, Label: "even"},
+	}
+	if !reflect.DeepEqual(cues, expected) {
+		t.Errorf("expected %+v got %+v", expected, cues)
+	}
+	var warnings []wav.Warning
+	if _, err := wav.NewReader(bytes.NewReader(out.data), wav.WithWarnings(&warnings)); err != nil || len(warnings) != 0 {
+		t.Errorf("unexpected warnings %v: %v", warnings, err)
+	}
+
+	// without markers nothing is written.
+	out = buffer{}
+	transcode(t, floatSource(44100, 2, make([]float64, 16)), wav.SinkWithMarkers(&out, signal.BitDepth16, nil))
+	if ids := chunkIDs(out.data); !reflect.DeepEqual(ids, []string{"fmt ", "data"}) {
+		t.Errorf("unexpected chunks %v", ids)
+	}
+
+	sink := wav.SinkWithMarkers(&buffer{}, signal.BitDepth16, []wav.Marker{{Position: -1}})
+	if _, err := sink(mutable.Context{}, bufferSize, pipe.SignalProperties{SampleRate: 44100, Channels: 2}); err == nil {
+		t.Errorf("expected error for negative position")
+	}
+}
@@ -0,0 +1,136 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// MergeWithOffset writes the channels of a followed by the channels of b,
+// e.g. to sync separately recorded lav mic and camera audio. Positive
+// offset delays b by provided number of frames, negative offset delays a.
+// The delayed stream and the shorter one are padded with silence, so the
+// output lasts until the end of both streams. Both streams must have the
+// same sample rate. Options are applied to the writer.
+func MergeWithOffset(a, b io.ReadSeeker, offsetFrames int64, out io.WriteSeeker, bitDepth signal.BitDepth, options ...Option) error {
+	ar, err := NewReader(a)
+	if err != nil {
+		return fmt.Errorf("a: %w", err)
+	}
+	br, err := NewReader(b)
+	if err != nil {
+		return fmt.Errorf("b: %w", err)
+	}
+	af, bf := ar.Format(), br.Format()
+	if af.SampleRate != bf.SampleRate {
+		return fmt.Errorf("sample rate %v of b doesn't match sample rate %v of a", bf.SampleRate, af.SampleRate)
+	}
+	inputs := []*mergeInput{newMergeInput(ar), newMergeInput(br)}
+	if offsetFrames > 0 {
+		inputs[1].delay = offsetFrames
+	} else {
+		inputs[0].delay = -offsetFrames
+	}
+	w, err := NewWriter(out, Format{
+		SampleRate: af.SampleRate,
+		Channels:   af.Channels + bf.Channels,
+		BitDepth:   bitDepth,
+	}, options...)
+	if err != nil {
+		return err
+	}
+	buf := signal.Allocator{
+		Channels: af.Channels + bf.Channels,
+		Length:   normalizeBufferSize,
+		Capacity: normalizeBufferSize,
+	}.Float64()
+	for {
+		var frames int
+		for i, in := range inputs {
+			n, err := in.fill()
+			if err != nil {
+				return fmt.Errorf("%c: %w", 'a'+i, err)
+			}
+			if n > frames {
+				frames = n
+			}
+		}
+		if frames == 0 {
+			return w.Close()
+		}
+		channels, offset := buf.Channels(), 0
+		for _, in := range inputs {
+			inChannels := in.buf.Channels()
+			for i := 0; i < frames; i++ {
+				for c := 0; c < inChannels; c++ {
+					buf.SetSample(i*channels+offset+c, in.buf.Sample(i*inChannels+c))
+				}
+			}
+			offset += inChannels
+		}
+		if _, err := w.Write(buf.Slice(0, frames)); err != nil {
+			return err
+		}
+	}
+}
+
+// mergeInput reads the frames of merged stream after the delay.
+type mergeInput struct {
+	reader *Reader
+	buf    signal.Floating
+	// number of silent frames before the stream.
+	delay int64
+	done  bool
+}
+
+func newMergeInput(r *Reader) *mergeInput {
+	return &mergeInput{
+		reader: r,
+		buf: signal.Allocator{
+			Channels: r.Format().Channels,
+			Length:   normalizeBufferSize,
+			Capacity: normalizeBufferSize,
+		}.Float64(),
+	}
+}
+
+// fill fills the buffer with the next frames of delayed stream and
+// silence after its end. It returns the number of frames before the end.
+func (in *mergeInput) fill() (int, error) {
+	n := in.silence()
+	for n < in.buf.Length() && !in.done {
+		read, err := in.reader.Read(in.buf.Slice(n, in.buf.Length()))
+		if err == io.EOF {
+			in.done = true
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		n += read
+	}
+	in.zero(n)
+	return n, nil
+}
+
+// silence writes the remaining delay at the start of the buffer. It
+// returns the number of written frames.
+func (in *mergeInput) silence() int {
+	frames := in.buf.Length()
+	if int64(frames) > in.delay {
+		frames = int(in.delay)
+	}
+	in.delay -= int64(frames)
+	for i := 0; i < frames*in.buf.Channels(); i++ {
+		in.buf.SetSample(i, 0)
+	}
+	return frames
+}
+
+// zero fills the buffer with silence starting at provided frame.
+func (in *mergeInput) zero(at int) {
+	for i := at * in.buf.Channels(); i < in.buf.Len(); i++ {
+		in.buf.SetSample(i, 0)
+	}
+}
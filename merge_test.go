@@ -0,0 +1,55 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestMergeWithOffset(t *testing.T) {
+	a := riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)), chunk("data", rampData(5000)))
+	b := riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)), chunk("data", rampData(3000)))
+
+	for _, offset := range []int64{0, 1500, 4500, -3, -6000} {
+		var out buffer
+		if err := wav.MergeWithOffset(bytes.NewReader(a), bytes.NewReader(b), offset, &out, signal.BitDepth16); err != nil {
+			t.Fatalf("offset %d: unexpected error: %v", offset, err)
+		}
+		// delayed channels padded with silence.
+		var delayA, delayB int
+		if offset > 0 {
+			delayB = int(offset)
+		} else {
+			delayA = int(-offset)
+		}
+		channelA := append(make([]int16, delayA), ramp(0, 5000)...)
+		channelB := append(make([]int16, delayB), ramp(0, 3000)...)
+		frames := len(channelA)
+		if len(channelB) > frames {
+			frames = len(channelB)
+		}
+		channelA = append(channelA, make([]int16, frames-len(channelA))...)
+		channelB = append(channelB, make([]int16, frames-len(channelB))...)
+		expected := make([]int16, 0, 2*frames)
+		for i := 0; i < frames; i++ {
+			expected = append(expected, channelA[i], channelB[i])
+		}
+		if channels := binary.LittleEndian.Uint16(out.data[22:]); channels != 2 {
+			t.Errorf("offset %d: expected 2 channels got %d", offset, channels)
+		}
+		if samples := int16Samples(out.data); !reflect.DeepEqual(samples, expected) {
+			t.Errorf("offset %d: unexpected samples", offset)
+		}
+	}
+
+	other := riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", make([]byte, 8)))
+	var out buffer
+	if err := wav.MergeWithOffset(bytes.NewReader(a), bytes.NewReader(other), 0, &out, signal.BitDepth16); err == nil {
+		t.Errorf("expected error for sample rate mismatch")
+	}
+}
@@ -0,0 +1,111 @@
+package wav
+
+import (
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// Level is the level of a single channel in the metering window.
+type Level struct {
+	// Peak is the maximum absolute value of the samples.
+	Peak float64
+	// RMS is the root mean square of the samples.
+	RMS float64
+}
+
+// Meter is the levels of every channel in the metering window.
+type Meter struct {
+	// Frame is the position of the first frame of the window.
+	Frame int64
+	// Frames is the length of the window. It's less than the window size
+	// only for the last window of the stream.
+	Frames int
+	Levels []Level
+}
+
+// MeterFunc receives the levels of metering windows.
+type MeterFunc func(Meter)
+
+// WithMeter makes Source and Sink call provided function with the levels
+// of every window of provided number of frames. Windows are aligned to
+// the frames of the stream regardless of buffer sizes: the levels of
+// buffers are accumulated until the window is complete. The last window
+// is reported when the stream is done, even if it's incomplete. The
+// function is called from the goroutine of the component and the levels
+// must not be retained.
+func WithMeter(window int, fn MeterFunc) Option {
+	return func(o *options) {
+		o.meterWindow = window
+		o.meter = fn
+	}
+}
+
+// meter accumulates the levels of metering windows.
+type meter struct {
+	fn     MeterFunc
+	window int
+	// number of frames accumulated in the current window and the
+	// position of its first frame.
+	frames int
+	start  int64
+	sumSq  []float64
+	levels []Level
+}
+
+// newMeter returns a meter of provided number of channels, nil if
+// metering is disabled.
+func (o *options) newMeter(channels int) *meter {
+	if o.meter == nil || o.meterWindow <= 0 {
+		return nil
+	}
+	return &meter{
+		fn:     o.meter,
+		window: o.meterWindow,
+		sumSq:  make([]float64, channels),
+		levels: make([]Level, channels),
+	}
+}
+
+// update accumulates provided number of frames and reports complete
+// windows.
+func (m *meter) update(floats signal.Floating, frames int) {
+	if m == nil {
+		return
+	}
+	channels := len(m.sumSq)
+	for i := 0; i < frames; i++ {
+		for c := 0; c < channels; c++ {
+			v := floats.Sample(i*channels + c)
+			m.sumSq[c] += v * v
+			if abs := math.Abs(v); abs > m.levels[c].Peak {
+				m.levels[c].Peak = abs
+			}
+		}
+		if m.frames++; m.frames == m.window {
+			m.flush()
+		}
+	}
+}
+
+// flush reports the current window if it has any frames and starts the
+// next one.
+func (m *meter) flush() {
+	if m == nil || m.frames == 0 {
+		return
+	}
+	for c := range m.levels {
+		m.levels[c].RMS = math.Sqrt(m.sumSq[c] / float64(m.frames))
+	}
+	m.fn(Meter{
+		Frame:  m.start,
+		Frames: m.frames,
+		Levels: m.levels,
+	})
+	m.start += int64(m.frames)
+	m.frames = 0
+	for c := range m.levels {
+		m.sumSq[c] = 0
+		m.levels[c] = Level{}
+	}
+}
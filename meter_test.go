@@ -0,0 +1,66 @@
+package wav_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestWithMeter(t *testing.T) {
+	// left channel is a ramp, right channel is constant.
+	samples := make([]float64, 0, 20)
+	for i := 0; i < 10; i++ {
+		samples = append(samples, float64(i)/10, -0.5)
+	}
+	var meters []wav.Meter
+	record := func(m wav.Meter) {
+		m.Levels = append([]wav.Level(nil), m.Levels...)
+		meters = append(meters, m)
+	}
+	check := func(name string) {
+		t.Helper()
+		if len(meters) != 3 {
+			t.Fatalf("%s: expected 3 windows got %v", name, meters)
+		}
+		for i, m := range meters {
+			frames := 4
+			if i == 2 {
+				frames = 2
+			}
+			if m.Frame != int64(4*i) || m.Frames != frames {
+				t.Errorf("%s: unexpected window %d: %+v", name, i, m)
+			}
+			var sumSq float64
+			for j := 4 * i; j < 4*i+frames; j++ {
+				sumSq += samples[2*j] * samples[2*j]
+			}
+			left := wav.Level{Peak: samples[2*(4*i+frames-1)], RMS: math.Sqrt(sumSq / float64(frames))}
+			if !closeLevel(m.Levels[0], left) || !closeLevel(m.Levels[1], wav.Level{Peak: 0.5, RMS: 0.5}) {
+				t.Errorf("%s: unexpected levels of window %d: %+v", name, i, m.Levels)
+			}
+		}
+	}
+
+	var out buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&out, signal.BitDepth16, wav.WithMeter(4, record)))
+	check("sink")
+
+	// buffers of 3 frames aren't aligned to the window.
+	meters = nil
+	r, err := wav.NewReader(bytes.NewReader(out.data), wav.WithMeter(4, record))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	readAll(t, r)
+	check("reader")
+}
+
+// closeLevel returns true if the levels match with the precision of
+// 16-bit samples.
+func closeLevel(a, b wav.Level) bool {
+	return math.Abs(a.Peak-b.Peak) < 1e-4 && math.Abs(a.RMS-b.RMS) < 1e-4
+}
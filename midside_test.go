@@ -0,0 +1,116 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestMidSide(t *testing.T) {
+	samples := []float64{0.5, 0.25, -0.5, 0.5, 0, 0}
+	var encoded buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&encoded, signal.BitDepth16, wav.MidSide(), wav.WithRounding(wav.RoundHalfUp)))
+	if ids := chunkIDs(encoded.data); !reflect.DeepEqual(ids, []string{"fmt ", "mstr", "data"}) {
+		t.Errorf("unexpected chunks: %v", ids)
+	}
+
+	// decoded without the option returns mid and side.
+	var raw buffer
+	transcode(t, wav.Source(bytes.NewReader(encoded.data)), wav.Sink(&raw, signal.BitDepth16))
+	expected := []int16{12288, 8192, 0, -32768, 0, 0}
+	if result := int16Samples(raw.data); !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected mid/side %v got %v", expected, result)
+	}
+
+	var decoded buffer
+	transcode(t, wav.Source(bytes.NewReader(encoded.data), wav.MidSide()), wav.Sink(&decoded, signal.BitDepth16))
+	var original buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&original, signal.BitDepth16, wav.WithRounding(wav.RoundHalfUp)))
+	if want, got := int16Samples(original.data), int16Samples(decoded.data); !reflect.DeepEqual(want, got) {
+		t.Errorf("expected lossless samples %v got %v", want, got)
+	}
+
+	// full scale side wraps around and round trip is still lossless.
+	extremes := []float64{1, -1, -1, 1, 0.3, -0.7, -0.001, 0.999, 1, 1, -1, -1}
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24, signal.BitDepth32} {
+		var original, encoded buffer
+		transcode(t, floatSource(44100, 2, extremes), wav.Sink(&original, bitDepth))
+		transcode(t, floatSource(44100, 2, extremes), wav.Sink(&encoded, bitDepth, wav.MidSide()))
+		expected, err := wav.NewReader(bytes.NewReader(original.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r, err := wav.NewReader(bytes.NewReader(encoded.data), wav.MidSide())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want, got := readAll(t, expected), readAll(t, r); !reflect.DeepEqual(want, got) {
+			t.Errorf("%d bits: expected lossless samples %v got %v", bitDepth, want, got)
+		}
+	}
+
+	// float samples are converted in floating-point.
+	var float buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&float, signal.BitDepth32, wav.Float32(), wav.MidSide()))
+	r, err := wav.NewReader(bytes.NewReader(float.data), wav.MidSide())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := readAll(t, r); !reflect.DeepEqual(result, samples) {
+		t.Errorf("expected float samples %v got %v", samples, result)
+	}
+
+	// payload declares the layout of samples.
+	mstr := [4]byte{'m', 's', 't', 'r'}
+	for _, test := range []struct {
+		data    []byte
+		version byte
+	}{
+		{data: encoded.data, version: 2},
+		{data: float.data, version: 1},
+	} {
+		if payload, ok, err := wav.ReadChunk(bytes.NewReader(test.data), mstr); err != nil || !ok || !bytes.Equal(payload, []byte{test.version, 0}) {
+			t.Errorf("expected version %d got %v: %v", test.version, payload, err)
+		}
+	}
+
+	// integer stream of version 1 is converted in floating-point.
+	pcm := []byte{0x00, 0x30, 0x00, 0x10, 0x00, 0xE0, 0x00, 0x20}
+	version1 := riff(chunk("fmt ", fmtPayload(2, 16, 4, 44100)), chunk("mstr", []byte{1, 0}), chunk("data", pcm))
+	r, err = wav.NewReader(bytes.NewReader(version1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected1 := readAll(t, r)
+	for i := 0; i < len(expected1); i += 2 {
+		mid, side := expected1[i], expected1[i+1]
+		expected1[i], expected1[i+1] = mid+side, mid-side
+	}
+	r, err = wav.NewReader(bytes.NewReader(version1), wav.MidSide())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := readAll(t, r); !reflect.DeepEqual(result, expected1) {
+		t.Errorf("expected version 1 samples %v got %v", expected1, result)
+	}
+
+	// unmarked stream is decoded as is.
+	var unmarked buffer
+	transcode(t, wav.Source(bytes.NewReader(original.data), wav.MidSide()), wav.Sink(&unmarked, signal.BitDepth16))
+	if !reflect.DeepEqual(int16Samples(original.data), int16Samples(unmarked.data)) {
+		t.Errorf("unmarked stream is changed")
+	}
+
+	_, err = pipe.New(bufferSize, pipe.Line{
+		Source: floatSource(44100, 1, []float64{0.5}),
+		Sink:   wav.Sink(&buffer{}, signal.BitDepth16, wav.MidSide()),
+	})
+	if err == nil {
+		t.Errorf("expected error for mono mid/side")
+	}
+}
@@ -0,0 +1,44 @@
+package wav
+
+import (
+	"errors"
+
+	"pipelined.dev/signal"
+)
+
+// WithMinimal makes Sink write the smallest valid stream: RIFF header, fmt
+// chunk and data chunk. Metadata and other optional chunks are not
+// written even if enabled by other options, including bext, ID3, ADM,
+// checksum, JUNK placeholder and custom chunks. The fmt chunk isn't
+// extensible and silence isn't written as wave list. Linear PCM stream takes
+// exactly 44 bytes plus the size of data and its padding. Mid/side and
+// signed 8-bit streams can't be minimal, because they can't be decoded
+// without their marker chunks.
+func WithMinimal() Option {
+	return func(o *options) {
+		o.minimal = true
+	}
+}
+
+// minimized returns the options without optional chunks if Sink writes
+// minimal stream of provided format.
+func (o *options) minimized(f Format) (*options, error) {
+	if !o.minimal {
+		return o, nil
+	}
+	if o.midSide || o.signed8 && f.BitDepth == signal.BitDepth8 {
+		return nil, errors.New("mid/side and signed 8-bit streams can't be minimal")
+	}
+	m := *o
+	m.bext = nil
+	m.id3 = nil
+	m.disp = ""
+	m.admTracks, m.axml = nil, nil
+	m.checksum = false
+	m.reserveRF64 = false
+	m.preserved = nil
+	m.before, m.after = nil, nil
+	m.extensible = nil
+	m.silenceChunks = 0
+	return &m, nil
+}
@@ -0,0 +1,64 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestWithMinimal(t *testing.T) {
+	samples := make([]float64, 2*101)
+	for i := range samples {
+		samples[i] = float64(i%7) / 10
+	}
+	// silence that would be written as slnt chunk.
+	for i := 100; i < 180; i++ {
+		samples[i] = 0
+	}
+	options := []wav.Option{
+		wav.WithBext(&wav.Bext{Description: "minimal"}),
+		wav.WithID3([]byte("ID3")),
+		wav.WithDisp("title"),
+		wav.WithChecksum(),
+		wav.ReserveRF64(),
+		wav.WithChunk([4]byte{'t', 'e', 's', 't'}, []byte{1, 2}, wav.BeforeData),
+		wav.WithSilenceChunks(20),
+	}
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24} {
+		var full, minimal buffer
+		extensible := wav.WithExtensible(&wav.Extensible{ValidBits: uint16(bitDepth), ChannelMask: 3, SubFormat: wav.SubFormatPCM})
+		transcode(t, floatSource(44100, 2, samples), wav.Sink(&full, bitDepth))
+		transcode(t, floatSource(44100, 2, samples), wav.Sink(&minimal, bitDepth, append(options, extensible, wav.WithMinimal())...))
+
+		dataBytes := 2 * 101 * int(bitDepth) / 8
+		if size := len(minimal.data); size != 44+dataBytes {
+			t.Errorf("%d bits: expected %d bytes got %d", bitDepth, 44+dataBytes, size)
+		}
+		if ids := chunkIDs(minimal.data); !reflect.DeepEqual([]string{"fmt ", "data"}, ids) {
+			t.Errorf("%d bits: unexpected chunks %v", bitDepth, ids)
+		}
+		info, err := wav.Probe(bytes.NewReader(minimal.data))
+		if err != nil {
+			t.Fatalf("%d bits: unexpected error: %v", bitDepth, err)
+		}
+		if info.Frames != 101 {
+			t.Errorf("%d bits: expected 101 frames got %d", bitDepth, info.Frames)
+		}
+		fullData, _, _ := wav.ReadChunk(bytes.NewReader(full.data), [4]byte{'d', 'a', 't', 'a'})
+		minimalData, _, _ := wav.ReadChunk(bytes.NewReader(minimal.data), [4]byte{'d', 'a', 't', 'a'})
+		if !bytes.Equal(fullData, minimalData) {
+			t.Errorf("%d bits: samples differ", bitDepth)
+		}
+	}
+
+	props := pipe.SignalProperties{SampleRate: 44100, Channels: 2}
+	if _, err := wav.Sink(&buffer{}, signal.BitDepth16, wav.MidSide(), wav.WithMinimal())(mutable.Context{}, bufferSize, props); err == nil {
+		t.Errorf("expected error for minimal mid/side stream")
+	}
+}
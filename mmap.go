@@ -0,0 +1,26 @@
+package wav
+
+import (
+	"os"
+
+	"pipelined.dev/pipe"
+)
+
+// SourceMmap memory-maps the wav file and returns the source that decodes
+// the frames directly from the mapping, so no read syscalls are made per
+// buffer and seeks are free. The file is validated right away with
+// provided options, as SourceFile does. Returned close function unmaps the
+// file, it must be called only after the pipe is done. It's safe to call
+// it multiple times. The file isn't closed by the source. On platforms
+// without mmap support the file is read directly.
+func SourceMmap(f *os.File, options ...Option) (pipe.SourceAllocatorFunc, func() error, error) {
+	rs, c, err := mmap(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateSource(rs, options); err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	return Source(rs, options...), closeOnce(c), nil
+}
@@ -0,0 +1,19 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package wav
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// mmap returns the file itself, because memory mapping isn't supported.
+func mmap(f *os.File) (io.ReadSeeker, io.Closer, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, fmt.Errorf("error seeking wav file: %w", err)
+	}
+	return f, ioutil.NopCloser(nil), nil
+}
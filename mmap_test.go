@@ -0,0 +1,80 @@
+package wav_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestSourceMmap(t *testing.T) {
+	f, err := os.Open(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	source, closeFn, err := wav.SourceMmap(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var mapped buffer
+	transcode(t, source, wav.Sink(&mapped, signal.BitDepth16))
+	if err := closeFn(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+	if err := closeFn(); err != nil {
+		t.Errorf("unexpected second close error: %v", err)
+	}
+
+	sample, err := os.Open(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer sample.Close()
+	var read buffer
+	transcode(t, wav.Source(sample), wav.Sink(&read, signal.BitDepth16))
+	if !bytes.Equal(mapped.data, read.data) {
+		t.Errorf("mapped output doesn't match")
+	}
+
+	invalid, err := os.Open(notWav)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer invalid.Close()
+	if _, _, err := wav.SourceMmap(invalid); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %v", err)
+	}
+
+	// file is validated with provided options.
+	data, err := ioutil.ReadFile(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	leading, err := ioutil.TempFile("", "wav")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(leading.Name())
+	defer leading.Close()
+	if _, err := leading.Write(append(make([]byte, 100), data...)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := wav.SourceMmap(leading); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error for leading bytes got %v", err)
+	}
+	source, closeFn, err = wav.SourceMmap(leading, wav.SkipLeadingBytes(200))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closeFn()
+	var skipped buffer
+	transcode(t, source, wav.Sink(&skipped, signal.BitDepth16))
+	if !bytes.Equal(mapped.data, skipped.data) {
+		t.Errorf("mapped file with leading bytes doesn't match")
+	}
+}
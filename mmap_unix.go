@@ -0,0 +1,43 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package wav
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// mapping is the memory-mapped content of the file.
+type mapping []byte
+
+// Close unmaps the file.
+func (m mapping) Close() error {
+	if err := syscall.Munmap(m); err != nil {
+		return fmt.Errorf("error unmapping file: %w", err)
+	}
+	return nil
+}
+
+// mmap maps the whole file into memory for reading.
+func mmap(f *os.File) (io.ReadSeeker, io.Closer, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading file info: %w", err)
+	}
+	size := info.Size()
+	if size == 0 {
+		return nil, nil, ErrInvalidWav
+	}
+	if int64(int(size)) != size {
+		return nil, nil, fmt.Errorf("file size %d exceeds address space", size)
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error mapping file: %w", err)
+	}
+	return bytes.NewReader(data), mapping(data), nil
+}
@@ -0,0 +1,137 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// normalizeBufferSize is the number of frames processed at once by
+// Normalize.
+const normalizeBufferSize = 4096
+
+// NormalizeBoostOnly makes Normalize leave the stream that already peaks
+// at or above the target as is, instead of attenuating it.
+func NormalizeBoostOnly() Option {
+	return func(o *options) {
+		o.boostOnly = true
+	}
+}
+
+// Normalize encodes wav stream with the gain that makes its peak reach
+// provided level in dBFS. The stream is read twice: the first pass finds
+// the peak and the second one applies the gain, the stream is rewinded
+// between passes. Silent stream is encoded without gain. Options are
+// applied to the writer.
+func Normalize(rs io.ReadSeeker, ws io.WriteSeeker, targetPeakDB float64, bitDepth signal.BitDepth, options ...Option) error {
+	peak, err := readPeak(rs)
+	if err != nil {
+		return err
+	}
+	gain := 1.0
+	if peak > 0 {
+		gain = math.Pow(10, targetPeakDB/20) / peak
+	}
+	if opts := newOptions(options); opts.boostOnly && gain < 1 {
+		gain = 1
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking stream start: %w", err)
+	}
+	return encodeWithGain(rs, ws, gain, bitDepth, options)
+}
+
+// LoudnessNormalize encodes wav stream with the gain that makes its
+// integrated loudness reach provided level in LUFS, e.g. -23 for EBU R 128
+// broadcast or -16 for streaming. The loudness is measured in the first
+// pass, see MeasureLoudness. Boosted peaks can exceed full scale, use
+// WithTruePeakLimit to limit them. Stream that is too quiet to measure is
+// encoded without gain. Options are applied to the writer.
+func LoudnessNormalize(rs io.ReadSeeker, ws io.WriteSeeker, targetLUFS float64, bitDepth signal.BitDepth, options ...Option) error {
+	loudness, err := MeasureLoudness(rs)
+	if err != nil {
+		return err
+	}
+	gain := 1.0
+	if !math.IsInf(loudness, -1) {
+		gain = math.Pow(10, (targetLUFS-loudness)/20)
+	}
+	return encodeWithGain(rs, ws, gain, bitDepth, options)
+}
+
+// readPeak returns the maximum absolute sample value of the stream.
+func readPeak(rs io.ReadSeeker) (float64, error) {
+	r, err := NewReader(rs)
+	if err != nil {
+		return 0, err
+	}
+	buf := signal.Allocator{
+		Channels: r.Format().Channels,
+		Length:   normalizeBufferSize,
+		Capacity: normalizeBufferSize,
+	}.Float64()
+	var peak float64
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			return peak, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		for i := 0; i < n*buf.Channels(); i++ {
+			peak = math.Max(peak, math.Abs(buf.Sample(i)))
+		}
+	}
+}
+
+// encodeWithGain encodes the stream with provided gain applied.
+func encodeWithGain(rs io.ReadSeeker, ws io.WriteSeeker, gain float64, bitDepth signal.BitDepth, options []Option) error {
+	r, err := NewReader(rs)
+	if err != nil {
+		return err
+	}
+	f := r.Format()
+	f.BitDepth = bitDepth
+	w, err := NewWriter(ws, f, options...)
+	if err != nil {
+		return err
+	}
+	var l *limiter
+	if opts := newOptions(options); opts.truePeakLimit != nil {
+		l = newLimiter(f, *opts.truePeakLimit, normalizeBufferSize)
+	}
+	buf := signal.Allocator{
+		Channels: f.Channels,
+		Length:   normalizeBufferSize,
+		Capacity: normalizeBufferSize,
+	}.Float64()
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n*buf.Channels(); i++ {
+			buf.SetSample(i, buf.Sample(i)*gain)
+		}
+		if l != nil {
+			err = l.write(w, buf, n)
+		} else {
+			_, err = w.Write(buf.Slice(0, n))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if l != nil {
+		if err := l.flush(w); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
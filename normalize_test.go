@@ -0,0 +1,87 @@
+package wav_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestNormalize(t *testing.T) {
+	halfDB := 20 * math.Log10(0.5)
+	tests := []struct {
+		samples  []float64
+		options  []wav.Option
+		expected []int16
+	}{
+		{
+			samples:  []float64{0.25, -0.125, 0},
+			expected: []int16{16383, -8192, 0},
+		},
+		{
+			samples:  []float64{-0.75, 0.375, 0},
+			expected: []int16{-16384, 8191, 0},
+		},
+		{
+			samples:  []float64{-0.75, 0.375, 0},
+			options:  []wav.Option{wav.NormalizeBoostOnly()},
+			expected: []int16{-24576, 12287, 0},
+		},
+		{
+			samples:  []float64{0, 0},
+			expected: []int16{0, 0},
+		},
+	}
+	for _, test := range tests {
+		var in, out buffer
+		transcode(t, floatSource(8000, 1, test.samples), wav.Sink(&in, signal.BitDepth24))
+		if err := wav.Normalize(bytes.NewReader(in.data), &out, halfDB, signal.BitDepth16, test.options...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if samples := int16Samples(out.data); !reflect.DeepEqual(samples, test.expected) {
+			t.Errorf("expected %v got %v", test.expected, samples)
+		}
+	}
+}
+
+func TestLoudnessNormalize(t *testing.T) {
+	var in buffer
+	transcode(t, floatSource(48000, 2, sine(48000, 2, 1000, 0.05, 96000)), wav.Sink(&in, signal.BitDepth24))
+	var out buffer
+	if err := wav.LoudnessNormalize(bytes.NewReader(in.data), &out, -16, signal.BitDepth24); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loudness, err := wav.MeasureLoudness(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(loudness+16) > 0.01 {
+		t.Errorf("expected -16 LUFS got %v", loudness)
+	}
+
+	// boosted peaks are limited.
+	out = buffer{}
+	ceiling := math.Pow(10, -1.0/20)
+	if err := wav.LoudnessNormalize(bytes.NewReader(in.data), &out, -2, signal.BitDepth24, wav.WithTruePeakLimit(-1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, err := wav.NewReader(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	samples := readAll(t, r)
+	if len(samples) != 2*96000 {
+		t.Fatalf("expected %d samples got %d", 2*96000, len(samples))
+	}
+	var peak float64
+	for _, v := range samples {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	if peak > ceiling+1e-6 || peak < 0.8*ceiling {
+		t.Errorf("expected peak below %v got %v", ceiling, peak)
+	}
+}
@@ -0,0 +1,199 @@
+package wav
+
+import (
+	"encoding/binary"
+	"time"
+
+	"pipelined.dev/signal"
+)
+
+// Option configures Source and Sink. Options that don't apply to the
+// component are ignored.
+type Option func(*options)
+
+// options holds the configuration of Source and Sink.
+type options struct {
+	rounding  Rounding
+	overflow  Overflow
+	gains     []float64
+	warnings  *[]Warning
+	lenient   bool
+	maxFrames int64
+	id3       []byte
+	bext      *Bext
+	preroll   time.Duration
+	midSide   bool
+	analysis  *Analysis
+	chunkFunc ChunkFunc
+	events    EventFunc
+	planar    bool
+	// Source trusts the headers of the stream.
+	skipValidation bool
+	// JUNK placeholder for ds64 chunk is written by Sink.
+	reserveRF64 bool
+	// interval of Sink checkpoints, zero if disabled.
+	checkpoints time.Duration
+	// bufferFunc processes every buffer of Source and Sink.
+	bufferFunc BufferFunc
+	// ADM chunks written by Sink.
+	admTracks []ADMTrack
+	axml      []byte
+	// 8-bit samples are signed.
+	signed8  bool
+	counters *Counters
+	// sample rate of decoded frames, zero if not resampled.
+	resample signal.Frequency
+	verify   bool
+	// size fields of the header written by SinkStream.
+	placeholder Placeholder
+	// decoded channels, all if empty.
+	channels []int
+	disp     string
+	// float samples and the range they're clamped to, nil if not clamped.
+	float bool
+	clamp *[2]float64
+	// extension of fmt chunk, nil if format isn't extensible.
+	extensible *Extensible
+	boostOnly  bool
+	// ceiling of LoudnessNormalize limiter, nil if not limited.
+	truePeakLimit *float64
+	// form type of RIFF header, nil for "WAVE".
+	formType *[4]byte
+	// number of channels written by Sink, zero if not padded.
+	paddedChannels int
+	// Sink warns if output bit depth exceeds the effective depth of input.
+	upconversion bool
+	// Sink writes the checksum of data.
+	checksum bool
+	// justification of 32-bit fixed-point frames, nil if frames have
+	// stream bit depth.
+	justification *Justification
+	// window size of meter in frames.
+	meterWindow int
+	meter       MeterFunc
+	// attenuation of decoded samples in decibels.
+	headroom float64
+	// offset and length of embedded wav data, nil if the whole stream is
+	// read.
+	embedded *[2]int64
+	// Source fills the final buffer with silence.
+	padFinalBuffer bool
+	// maximum number of bytes before RIFF header.
+	leadingLimit int
+	// justification of 24-bit samples in 4-byte containers, nil if
+	// samples are packed in 3 bytes.
+	container24 *Justification
+	// Source validates the sizes of chunks.
+	chunkSizes bool
+	// Sink writes only fmt and data chunks.
+	minimal bool
+	// Source verifies the number of decoded frames.
+	strictFrames bool
+	// interpolation kernel of resampling.
+	resampleQuality ResampleQuality
+	// minimum run of silent frames that Sink writes as slnt chunk, zero
+	// if silence is written as samples.
+	silenceChunks int
+	// Source flushes denormal samples to zero.
+	flushDenormals bool
+	// byte order of raw PCM samples, nil if little-endian.
+	byteOrder binary.ByteOrder
+	// exact length of Sink output in frames, nil if not limited.
+	exactLength *int
+	// preserved chunks are read by Source and written by Sink.
+	preserved *Chunks
+	// chunks written by Sink before and after data.
+	before []rawChunk
+	after  []rawChunk
+}
+
+// Lenient makes Source recover files with common defects instead of
+// failing or decoding them incorrectly. Applied recoveries are reported as
+// warnings:
+//   - channel count that doesn't match block align is inferred from block
+//     align.
+//   - fmt chunk placed after data chunk is read before decoding data.
+func Lenient() Option {
+	return func(o *options) {
+		o.lenient = true
+	}
+}
+
+// SkipValidation makes Source trust the headers of the stream instead of
+// validating it before decoding, e.g. when it was already checked with
+// Probe. Only the headers up to fmt chunk are read. This saves the
+// validation cost, which includes the scan of the whole stream if fmt
+// chunk doesn't declare the byte rate. Invalid streams might fail during
+// decoding or produce wrong frames.
+func SkipValidation() Option {
+	return func(o *options) {
+		o.skipValidation = true
+	}
+}
+
+// newOptions applies provided options on top of defaults.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// inspect returns true if Source needs to read the container layout.
+func (o *options) inspect() bool {
+	return o.warnings != nil || o.lenient || o.preserved != nil || o.maxFrames > 0 || o.midSide || o.chunkFunc != nil || o.events != nil || o.container24 != nil || o.chunkSizes || o.strictFrames
+}
+
+// leadingChunks returns chunks that Sink writes between fmt and data of
+// the stream with provided format.
+func (o *options) leadingChunks(f Format) []rawChunk {
+	var chunks []rawChunk
+	if o.midSide {
+		chunks = append(chunks, o.midSideChunk())
+	}
+	if o.signed8 && f.BitDepth == signal.BitDepth8 {
+		chunks = append(chunks, rawChunk{ID: signed8ID, Payload: []byte{8, 0}})
+	}
+	chunks = append(chunks, o.admChunks()...)
+	if o.disp != "" {
+		chunks = append(chunks, rawChunk{ID: dispID, Payload: encodeDisp(o.disp)})
+	}
+	if o.preserved != nil {
+		chunks = append(chunks, o.preserved.before...)
+	}
+	return append(chunks, o.before...)
+}
+
+// trailingChunks returns chunks that Sink writes after data.
+func (o *options) trailingChunks() []rawChunk {
+	var chunks []rawChunk
+	if o.id3 != nil {
+		chunks = append(chunks, rawChunk{ID: id3ID, Payload: o.id3})
+	}
+	if o.preserved != nil {
+		chunks = append(chunks, o.preserved.after...)
+	}
+	return append(chunks, o.after...)
+}
+
+// validateChunks checks the chunks that Sink writes as provided.
+func (o *options) validateChunks() error {
+	for _, chunks := range [][]rawChunk{o.before, o.after} {
+		for _, c := range chunks {
+			if err := validateChunkID(c.ID); err != nil {
+				return err
+			}
+		}
+	}
+	if err := o.validateFormType(); err != nil {
+		return err
+	}
+	if err := validateChna(o.admTracks); err != nil {
+		return err
+	}
+	if o.bext != nil {
+		return o.bext.validate()
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestLenientChannels(t *testing.T) {
+	// stereo 16-bit data with mono in fmt chunk.
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 4, 8000)),
+		chunk("data", []byte{1, 0, 2, 0, 3, 0, 4, 0}),
+	)
+
+	var warnings []wav.Warning
+	var out buffer
+	transcode(t,
+		wav.Source(bytes.NewReader(data), wav.Lenient(), wav.WithWarnings(&warnings)),
+		wav.Sink(&out, signal.BitDepth16),
+	)
+	if channels := binary.LittleEndian.Uint16(out.data[22:]); channels != 2 {
+		t.Errorf("expected 2 channels got %d", channels)
+	}
+	if samples := int16Samples(out.data); len(samples) != 4 || samples[3] != 4 {
+		t.Errorf("unexpected samples: %v", samples)
+	}
+	if len(warnings) == 0 {
+		t.Errorf("expected warnings")
+	}
+}
+
+func TestLenientFormatOrder(t *testing.T) {
+	// odd-sized data chunk before fmt chunk.
+	data := riff(
+		chunk("LIST", []byte("INFOabc")),
+		chunk("data", []byte{0x80, 0xC0, 0x40}),
+		chunk("fmt ", fmtPayload(1, 8, 1, 8000)),
+	)
+
+	var warnings []wav.Warning
+	var out buffer
+	transcode(t,
+		wav.Source(bytes.NewReader(data), wav.Lenient(), wav.WithWarnings(&warnings)),
+		wav.Sink(&out, signal.BitDepth8),
+	)
+	if pcm := out.data[44:]; !bytes.Equal(pcm, []byte{0x80, 0xC0, 0x40}) {
+		t.Errorf("unexpected PCM data: %v", pcm)
+	}
+	if size := binary.LittleEndian.Uint32(out.data[40:]); size != 3 {
+		t.Errorf("expected 3 bytes of data got %d", size)
+	}
+	if len(warnings) == 0 {
+		t.Errorf("expected warnings")
+	}
+}
+
+func TestSkipValidation(t *testing.T) {
+	// fmt chunk doesn't declare byte rate.
+	format := fmtPayload(1, 16, 2, 8000)
+	binary.LittleEndian.PutUint32(format[8:], 0)
+	data := riff(chunk("fmt ", format), chunk("data", []byte{0x00, 0x40, 0x00, 0xC0}))
+
+	if _, err := wav.NewReader(bytes.NewReader(data)); err == nil {
+		t.Errorf("expected validation error")
+	}
+	r, err := wav.NewReader(bytes.NewReader(data), wav.SkipValidation())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 1, Length: 4, Capacity: 4}.Float64()
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if n != 2 || buf.Sample(0) <= 0.49 || buf.Sample(1) != -0.5 {
+		t.Errorf("unexpected frames: %d %v %v", n, buf.Sample(0), buf.Sample(1))
+	}
+}
@@ -0,0 +1,95 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"pipelined.dev/signal"
+)
+
+// containerSize is the size in bytes of 24-bit sample packed into 4 bytes.
+const containerSize = 4
+
+// With24In32 makes Sink write every 24-bit sample in 4 bytes instead of
+// the standard packed 3 bytes, with provided justification: left-justified
+// samples occupy high bytes and have zero low byte, right-justified
+// samples occupy low bytes with sign-extended high byte. The fmt chunk
+// declares 24 bits per sample and block align of 4 bytes per channel.
+// Source and Reader with the same option decode such streams: their block
+// align must match 4-byte containers. The option applies only to 24-bit
+// streams.
+func With24In32(j Justification) Option {
+	return func(o *options) {
+		o.container24 = &j
+	}
+}
+
+// errContainer24 is returned when 4-byte containers are used for other
+// bit depths than 24.
+var errContainer24 = errors.New("4-byte containers require 24-bit samples")
+
+// validateContainer checks that samples of provided format can be written
+// in 4-byte containers.
+func (o *options) validateContainer(f Format) error {
+	if o.container24 == nil {
+		return nil
+	}
+	if f.BitDepth != signal.BitDepth24 || o.float {
+		return errContainer24
+	}
+	return nil
+}
+
+// bytesPerSample returns the number of bytes that Sink writes for a
+// sample of provided format.
+func (o *options) bytesPerSample(f Format) int {
+	if o.container24 != nil {
+		return containerSize
+	}
+	return int(f.BitDepth) / 8
+}
+
+// blockAlign returns the size in bytes of a frame that Sink writes.
+func (o *options) blockAlign(f Format) int {
+	return f.Channels * o.bytesPerSample(f)
+}
+
+// putContainer sets the block align and byte rate of 4-byte containers in
+// the payload of fmt chunk.
+func (o *options) putContainer(payload []byte, f Format) {
+	if o.container24 == nil {
+		return
+	}
+	blockAlign := o.blockAlign(f)
+	binary.LittleEndian.PutUint32(payload[8:], uint32(int(f.SampleRate)*blockAlign))
+	binary.LittleEndian.PutUint16(payload[12:], uint16(blockAlign))
+}
+
+// pack places the samples into 4-byte containers as go-audio encoder
+// writes them with 32-bit depth.
+func (w *Writer) pack(data []int) {
+	if w.container24 == nil || *w.container24 != LeftJustified {
+		return
+	}
+	for i := range data {
+		data[i] <<= 8
+	}
+}
+
+// checkContainer24 returns an error if the stream doesn't have 24-bit
+// samples in 4-byte containers.
+func (o *options) checkContainer24(f format) error {
+	if blockAlign := containerSize * int(f.Channels); f.BitsPerSample != 24 || int(f.BlockAlign) != blockAlign {
+		return fmt.Errorf("block align %d of %d bits doesn't match 4-byte containers of %d channels", f.BlockAlign, f.BitsPerSample, f.Channels)
+	}
+	return nil
+}
+
+// unpack returns the 24-bit sample of 4-byte container.
+func unpack(v int, j Justification) int {
+	if j == LeftJustified {
+		return v >> 8
+	}
+	return int(int32(v<<8) >> 8)
+}
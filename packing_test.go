@@ -0,0 +1,72 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestWith24In32(t *testing.T) {
+	samples := []float64{-1, 1, 0.25, -0.25, 0, 1.0 / 8388607}
+	var packed buffer
+	transcode(t, floatSource(48000, 2, samples), wav.Sink(&packed, signal.BitDepth24))
+	expected := readAll(t, mustReader(t, packed.data))
+
+	tests := []struct {
+		justification wav.Justification
+		// bytes of the first two samples: -1 and 1.
+		first []byte
+	}{
+		{
+			justification: wav.LeftJustified,
+			first:         []byte{0x00, 0x00, 0x00, 0x80, 0x00, 0xFF, 0xFF, 0x7F},
+		},
+		{
+			justification: wav.RightJustified,
+			first:         []byte{0x00, 0x00, 0x80, 0xFF, 0xFF, 0xFF, 0x7F, 0x00},
+		},
+	}
+	for _, test := range tests {
+		var out readableBuffer
+		transcode(t, floatSource(48000, 2, samples), wav.Sink(&out, signal.BitDepth24, wav.With24In32(test.justification), wav.WithVerify()))
+		if bitsPerSample, blockAlign := binary.LittleEndian.Uint16(out.data[34:]), binary.LittleEndian.Uint16(out.data[32:]); bitsPerSample != 24 || blockAlign != 8 {
+			t.Errorf("justification %v: unexpected %d bits and block align %d", test.justification, bitsPerSample, blockAlign)
+		}
+		if byteRate := binary.LittleEndian.Uint32(out.data[28:]); byteRate != 8*48000 {
+			t.Errorf("justification %v: unexpected byte rate %d", test.justification, byteRate)
+		}
+		if size := binary.LittleEndian.Uint32(out.data[40:]); size != uint32(4*len(samples)) {
+			t.Errorf("justification %v: unexpected data size %d", test.justification, size)
+		}
+		if first := out.data[44:52]; !bytes.Equal(first, test.first) {
+			t.Errorf("justification %v: expected bytes %x got %x", test.justification, test.first, first)
+		}
+
+		r, err := wav.NewReader(bytes.NewReader(out.data), wav.With24In32(test.justification))
+		if err != nil {
+			t.Fatalf("justification %v: unexpected error: %v", test.justification, err)
+		}
+		if r.Format().BitDepth != signal.BitDepth24 {
+			t.Errorf("justification %v: unexpected format %+v", test.justification, r.Format())
+		}
+		if result := readAll(t, r); !reflect.DeepEqual(result, expected) {
+			t.Errorf("justification %v: expected %v got %v", test.justification, expected, result)
+		}
+	}
+
+	// block align of packed 3 bytes doesn't match.
+	if _, err := wav.NewReader(bytes.NewReader(packed.data), wav.With24In32(wav.LeftJustified)); err == nil {
+		t.Errorf("expected error for packed stream")
+	}
+	sink := wav.Sink(&buffer{}, signal.BitDepth16, wav.With24In32(wav.LeftJustified))
+	if _, err := sink(mutable.Context{}, bufferSize, pipe.SignalProperties{SampleRate: 48000, Channels: 2}); err == nil {
+		t.Errorf("expected error for 16-bit samples")
+	}
+}
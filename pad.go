@@ -0,0 +1,68 @@
+package wav
+
+import (
+	"fmt"
+
+	"pipelined.dev/signal"
+)
+
+// WithPaddedChannels makes Sink write provided number of channels, e.g. to
+// feed the hardware that requires 2, 4 or 8 channels. Input channels take
+// the lowest indices and the rest of channels are filled with digital
+// silence. Channel count in the header is the padded one. Input with more
+// channels than provided results in error.
+func WithPaddedChannels(channels int) Option {
+	return func(o *options) {
+		o.paddedChannels = channels
+	}
+}
+
+// paddedFormat returns the format of the stream with padded channels.
+func (o *options) paddedFormat(f Format) (Format, error) {
+	if o.paddedChannels == 0 {
+		return f, nil
+	}
+	if o.paddedChannels < f.Channels {
+		return Format{}, fmt.Errorf("%d channels can't be padded to %d", f.Channels, o.paddedChannels)
+	}
+	f.Channels = o.paddedChannels
+	return f, nil
+}
+
+// channelPadder copies frames into the buffer with more channels.
+type channelPadder struct {
+	buf signal.Floating
+}
+
+func newChannelPadder(channels, bufferSize int) *channelPadder {
+	return &channelPadder{
+		buf: signal.Allocator{
+			Channels: channels,
+			Length:   bufferSize,
+			Capacity: bufferSize,
+		}.Float64(),
+	}
+}
+
+// pad returns the frames of provided buffer with added silent channels.
+func (p *channelPadder) pad(src signal.Floating) signal.Floating {
+	if src.Length() > p.buf.Length() {
+		p.buf = signal.Allocator{
+			Channels: p.buf.Channels(),
+			Length:   src.Length(),
+			Capacity: src.Length(),
+		}.Float64()
+	}
+	dst := p.buf.Slice(0, src.Length())
+	channels, padded := src.Channels(), dst.Channels()
+	for i := 0; i < src.Length(); i++ {
+		for c := 0; c < padded; c++ {
+			var v float64
+			if c < channels {
+				v = src.Sample(i*channels + c)
+			}
+			dst.SetSample(i*padded+c, v)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,40 @@
+package wav_test
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestWithPaddedChannels(t *testing.T) {
+	samples := []float64{
+		0.5, -0.5, 0.25,
+		-0.25, 0.125, -0.125,
+	}
+	var out buffer
+	transcode(t, floatSource(48000, 3, samples), wav.Sink(&out, signal.BitDepth16, wav.WithPaddedChannels(4)))
+	if channels := binary.LittleEndian.Uint16(out.data[22:]); channels != 4 {
+		t.Errorf("expected 4 channels got %d", channels)
+	}
+	if blockAlign := binary.LittleEndian.Uint16(out.data[32:]); blockAlign != 8 {
+		t.Errorf("expected block align 8 got %d", blockAlign)
+	}
+	expected := []int16{
+		16383, -16384, 8191, 0,
+		-8192, 4095, -4096, 0,
+	}
+	if result := int16Samples(out.data); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v got %v", expected, result)
+	}
+
+	props := pipe.SignalProperties{SampleRate: 48000, Channels: 3}
+	if _, err := wav.Sink(&out, signal.BitDepth16, wav.WithPaddedChannels(2))(mutable.Context{}, bufferSize, props); err == nil {
+		t.Errorf("expected error for fewer padded channels")
+	}
+}
@@ -0,0 +1,44 @@
+package wav
+
+import (
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// PadFinalBuffer makes Source fill the final partial buffer with silence
+// up to the buffer length, so every buffer is full. By default the final
+// buffer is short. The padding isn't audio of the stream: the number of
+// real frames of every buffer is reported by EventBuffer events with
+// the number of silent frames that follow them in Padding field, see
+// WithEvents. BufferFunc, Analysis and meter receive only real frames.
+func PadFinalBuffer() Option {
+	return func(o *options) {
+		o.padFinalBuffer = true
+	}
+}
+
+// padBuffer fills the buffer after provided number of frames with silence.
+// It returns the number of silent frames.
+func padBuffer(floats signal.Floating, frames int) int {
+	for i := frames * floats.Channels(); i < floats.Len(); i++ {
+		floats.SetSample(i, 0)
+	}
+	return floats.Length() - frames
+}
+
+// fill reads the frames after provided number of frames until the buffer
+// is full or the stream is done, so only the final buffer is padded.
+func (r *Reader) fill(floats signal.Floating, frames int) (int, error) {
+	for frames < floats.Length() {
+		n, err := r.readFrames(floats.Slice(frames, floats.Length()))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		frames += n
+	}
+	return frames, nil
+}
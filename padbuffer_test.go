@@ -0,0 +1,55 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestPadFinalBuffer(t *testing.T) {
+	data := riff(chunk("fmt ", fmtPayload(2, 16, 4, 44100)), chunk("data", rampData(20)))
+	var events []wav.Event
+	r, err := wav.NewReader(bytes.NewReader(data), wav.PadFinalBuffer(), wav.WithEvents(func(e wav.Event) {
+		if e.Kind == wav.EventBuffer {
+			events = append(events, e)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 2, Length: 4, Capacity: 4}.Float64()
+	for _, expected := range []int{4, 4} {
+		if n, err := r.Read(buf); n != expected || err != nil {
+			t.Fatalf("expected %d frames got %d: %v", expected, n, err)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		buf.SetSample(i, 1)
+	}
+	if n, err := r.Read(buf); n != 4 || err != nil {
+		t.Fatalf("expected padded buffer got %d: %v", n, err)
+	}
+	for i, expected := range []float64{16.0 / 32767, 17.0 / 32767, 18.0 / 32767, 19.0 / 32767, 0, 0, 0, 0} {
+		if v := buf.Sample(i); v != expected {
+			t.Errorf("sample %d: expected %v got %v", i, expected, v)
+		}
+	}
+	if _, err := r.Read(buf); err != io.EOF {
+		t.Errorf("expected EOF got %v", err)
+	}
+	if last := events[len(events)-1]; len(events) != 3 || last.Frames != 2 || last.Padding != 2 {
+		t.Errorf("unexpected buffer events %+v", events)
+	}
+
+	// planar buffers are padded in every channel.
+	r, _ = wav.NewReader(bytes.NewReader(data), wav.PadFinalBuffer(), wav.Planar())
+	buf = signal.Allocator{Channels: 2, Length: 8, Capacity: 8}.Float64()
+	r.Read(buf)
+	if n, err := r.Read(buf); n != 8 || err != nil || buf.Sample(1) != 18.0/32767 || buf.Sample(2) != 0 || buf.Sample(8) != 17.0/32767 || buf.Sample(10) != 0 {
+		t.Errorf("unexpected planar buffer %d: %v", n, err)
+	}
+}
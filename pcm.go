@@ -0,0 +1,204 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// pcmBufferSize is the number of frames decoded at once by PCM.
+const pcmBufferSize = 4096
+
+// WithByteOrder makes SourcePCM and ChannelReader write and NewRawReader
+// read raw PCM samples in provided byte order, binary.BigEndian or
+// binary.LittleEndian, the default. It has no effect on wav streams, which
+// are always little-endian, and on 8-bit samples.
+func WithByteOrder(order binary.ByteOrder) Option {
+	return func(o *options) {
+		o.byteOrder = order
+	}
+}
+
+// PCM is a decoded stream that reads its frames as raw PCM samples. It
+// implements io.WriterTo, so io.Copy encodes the samples straight into the
+// writer without intermediate buffers.
+type PCM struct {
+	reader  *Reader
+	format  Format
+	frames  signal.Floating
+	encoder *streamWriter
+	// encoded bytes that weren't read yet.
+	pending bytes.Buffer
+}
+
+// SourcePCM returns the stream of rs that is decoded and encoded again with
+// provided bit depth as signed integers, or unsigned for 8 bits, the way
+// they're stored in data chunk, unless WithByteOrder is provided. All
+// formats that Reader decodes are supported. Options are applied to
+// decoding, only rounding, overflow, Signed8Bit and WithByteOrder apply to
+// encoding as well.
+func SourcePCM(rs io.ReadSeeker, bitDepth signal.BitDepth, options ...Option) (*PCM, error) {
+	r, err := NewReader(rs, options...)
+	if err != nil {
+		return nil, err
+	}
+	p := PCM{
+		reader: r,
+		format: Format{
+			SampleRate: r.format.SampleRate,
+			Channels:   r.format.Channels,
+			BitDepth:   bitDepth,
+		},
+		frames: signal.Allocator{Channels: r.format.Channels, Length: pcmBufferSize, Capacity: pcmBufferSize}.Float64(),
+	}
+	opts := newOptions(options).encodingOptions()
+	if p.encoder, err = opts.newStreamWriter(nil, p.format, pcmBufferSize); err != nil {
+		return nil, err
+	}
+	// only samples are written.
+	p.encoder.header = nil
+	p.encoder.bigEndian = opts.byteOrder == binary.BigEndian
+	return &p, nil
+}
+
+// Format returns the format of written samples.
+func (p *PCM) Format() Format {
+	return p.format
+}
+
+// Read implements io.Reader.
+func (p *PCM) Read(b []byte) (int, error) {
+	for p.pending.Len() == 0 {
+		if err := p.encode(&p.pending); err != nil {
+			return 0, err
+		}
+	}
+	return p.pending.Read(b)
+}
+
+// WriteTo implements io.WriterTo. It writes the samples of all frames
+// that weren't read yet and returns the number of written bytes.
+func (p *PCM) WriteTo(w io.Writer) (int64, error) {
+	written := countingWriter{w: w}
+	if _, err := p.pending.WriteTo(&written); err != nil {
+		return written.n, fmt.Errorf("error writing PCM buffer: %w", err)
+	}
+	for {
+		err := p.encode(&written)
+		if err == io.EOF {
+			return written.n, nil
+		}
+		if err != nil {
+			return written.n, err
+		}
+	}
+}
+
+// encode decodes the next frames and writes their samples to provided
+// writer.
+func (p *PCM) encode(w io.Writer) error {
+	n, err := p.reader.Read(p.frames)
+	if err != nil {
+		return err
+	}
+	p.encoder.w = w
+	return p.encoder.write(p.frames.Slice(0, n))
+}
+
+// NewRawReader returns a new reader of raw PCM samples without headers,
+// e.g. the output of SourcePCM. Samples have provided format and are
+// stored as signed integers, or unsigned for 8 bits unless Signed8Bit is
+// provided, in the byte order of WithByteOrder. Trailing bytes of partial
+// frame are ignored. Other options are applied as NewReader does.
+func NewRawReader(rs io.ReadSeeker, f Format, options ...Option) (*Reader, error) {
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("error seeking raw PCM end: %w", err)
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking raw PCM start: %w", err)
+	}
+	size -= size % int64(f.BlockAlign())
+	if size > math.MaxUint32-canonicalHeaderSize {
+		return nil, fmt.Errorf("raw PCM of %d bytes doesn't fit wav stream", size)
+	}
+	opts := newOptions(options)
+	if sampleSize := int(f.BitDepth) / 8; opts.byteOrder == binary.BigEndian && sampleSize > 1 {
+		rs = &swappedStream{rs: rs, sampleSize: int64(sampleSize)}
+	}
+
+	// canonical header of the samples.
+	header := make([]byte, canonicalHeaderSize-12)
+	copy(header[0:], fmtID[:])
+	binary.LittleEndian.PutUint32(header[4:], 16)
+	putFormat(header[8:], f)
+	copy(header[24:], dataID[:])
+	binary.LittleEndian.PutUint32(header[28:], uint32(size))
+	stream := newVirtualStream(rs, []segment{
+		riffSegment(int64(len(header)) + size),
+		{data: header},
+		{offset: 0, size: size},
+	})
+	return NewReader(stream, options...)
+}
+
+// swappedStream reverses the bytes of every sample of big-endian stream.
+type swappedStream struct {
+	rs         io.ReadSeeker
+	sampleSize int64
+	offset     int64
+	buf        []byte
+}
+
+// Read implements io.Reader. Whole samples are read from the stream, so
+// reads don't have to be aligned.
+func (s *swappedStream) Read(p []byte) (int, error) {
+	start := s.offset - s.offset%s.sampleSize
+	end := s.offset + int64(len(p))
+	end += (s.sampleSize - end%s.sampleSize) % s.sampleSize
+	if _, err := s.rs.Seek(start, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("error seeking raw PCM: %w", err)
+	}
+	if size := int(end - start); cap(s.buf) < size {
+		s.buf = make([]byte, size)
+	}
+	buf := s.buf[:end-start]
+	n, err := io.ReadFull(s.rs, buf)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	buf = buf[:int64(n)-int64(n)%s.sampleSize]
+	for i := int64(0); i < int64(len(buf)); i += s.sampleSize {
+		sample := buf[i : i+s.sampleSize]
+		for a, b := 0, len(sample)-1; a < b; a, b = a+1, b-1 {
+			sample[a], sample[b] = sample[b], sample[a]
+		}
+	}
+	if skip := s.offset - start; int64(len(buf)) > skip {
+		n = copy(p, buf[skip:])
+	} else {
+		n = 0
+	}
+	s.offset += int64(n)
+	if n == 0 && err == nil {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (s *swappedStream) Seek(offset int64, whence int) (int64, error) {
+	offset, err := s.rs.Seek(offset, whence)
+	if err != nil {
+		return s.offset, err
+	}
+	s.offset = offset
+	return offset, nil
+}
@@ -0,0 +1,165 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestSourcePCM(t *testing.T) {
+	const frames = 5000
+	samples := make([]float64, 2*frames)
+	for i := range samples {
+		samples[i] = float64(i%200)/100 - 1
+	}
+	var in buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&in, signal.BitDepth16))
+
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24, signal.BitDepth32} {
+		var expected buffer
+		transcode(t, wav.Source(bytes.NewReader(in.data)), wav.Sink(&expected, bitDepth))
+
+		p, err := wav.SourcePCM(bytes.NewReader(in.data), bitDepth)
+		if err != nil {
+			t.Fatalf("%d bits: unexpected error: %v", bitDepth, err)
+		}
+		if f := p.Format(); f.Channels != 2 || f.SampleRate != 44100 || f.BitDepth != bitDepth {
+			t.Errorf("%d bits: unexpected format %+v", bitDepth, f)
+		}
+		var result bytes.Buffer
+		n, err := io.Copy(&result, p)
+		if err != nil {
+			t.Fatalf("%d bits: unexpected copy error: %v", bitDepth, err)
+		}
+		if pcm := expected.data[44:]; n != int64(len(pcm)) || !bytes.Equal(pcm, result.Bytes()) {
+			t.Errorf("%d bits: got %d bytes that differ from %d bytes of Sink", bitDepth, n, len(pcm))
+		}
+		if n, err := p.WriteTo(&result); n != 0 || err != nil {
+			t.Errorf("%d bits: expected drained stream got %d bytes: %v", bitDepth, n, err)
+		}
+	}
+
+	// bytes that were read aren't written again.
+	p, err := wav.SourcePCM(bytes.NewReader(in.data), signal.BitDepth16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	head := make([]byte, 7)
+	if _, err := io.ReadFull(p, head); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	var tail bytes.Buffer
+	if _, err := p.WriteTo(&tail); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if result := append(head, tail.Bytes()...); !bytes.Equal(in.data[44:], result) {
+		t.Errorf("read and written bytes differ from the stream")
+	}
+
+	// options of decoding aren't applied to encoding.
+	var resampled buffer
+	transcode(t, wav.Source(bytes.NewReader(in.data), wav.WithResample(48000), wav.MidSide()), wav.Sink(&resampled, signal.BitDepth24))
+	if p, err = wav.SourcePCM(bytes.NewReader(in.data), signal.BitDepth24, wav.WithResample(48000), wav.MidSide()); err != nil {
+		t.Fatalf("unexpected error with decoding options: %v", err)
+	}
+	result, err := ioutil.ReadAll(p)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if pcm := resampled.data[44:]; !bytes.Equal(pcm, result) {
+		t.Errorf("got %d resampled bytes that differ from %d bytes of Sink", len(result), len(pcm))
+	}
+
+	if _, err := wav.SourcePCM(bytes.NewReader(in.data), signal.BitDepth(12)); err == nil {
+		t.Errorf("expected error for invalid bit depth")
+	}
+}
+
+func TestWithByteOrder(t *testing.T) {
+	samples := []float64{0.5, -0.25, 0.125, -1, 0.75, 0.0625}
+	var in buffer
+	transcode(t, floatSource(48000, 2, samples), wav.Sink(&in, signal.BitDepth16))
+
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth16, signal.BitDepth24, signal.BitDepth32} {
+		var little, big bytes.Buffer
+		for order, out := range map[binary.ByteOrder]*bytes.Buffer{binary.LittleEndian: &little, binary.BigEndian: &big} {
+			p, err := wav.SourcePCM(bytes.NewReader(in.data), bitDepth, wav.WithByteOrder(order))
+			if err != nil {
+				t.Fatalf("%d bits: unexpected error: %v", bitDepth, err)
+			}
+			if _, err := p.WriteTo(out); err != nil {
+				t.Fatalf("%d bits: unexpected write error: %v", bitDepth, err)
+			}
+		}
+		// big-endian samples reversed back to little-endian.
+		size := int(bitDepth) / 8
+		reversed := big.Bytes()
+		for i := 0; i < len(reversed); i += size {
+			for a, b := i, i+size-1; a < b; a, b = a+1, b-1 {
+				reversed[a], reversed[b] = reversed[b], reversed[a]
+			}
+		}
+		if !bytes.Equal(little.Bytes(), reversed) {
+			t.Errorf("%d bits: big-endian samples don't match little-endian ones", bitDepth)
+		}
+	}
+
+	// big-endian 16-bit samples of a single channel read back.
+	r, err := wav.ChannelReader(bytes.NewReader(in.data), 0, signal.BitDepth16, wav.WithByteOrder(binary.BigEndian))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pcm, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := int16Samples(in.data)
+	if len(pcm) != len(expected) {
+		t.Fatalf("expected %d bytes got %d", len(expected), len(pcm))
+	}
+	for i := 0; i < len(pcm)/2; i++ {
+		if v := int16(binary.BigEndian.Uint16(pcm[2*i:])); v != expected[2*i] {
+			t.Errorf("sample %d: expected %d got %d", i, expected[2*i], v)
+		}
+	}
+
+	// raw samples read back in both byte orders.
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24, signal.BitDepth32} {
+		var stream buffer
+		transcode(t, wav.Source(bytes.NewReader(in.data)), wav.Sink(&stream, bitDepth))
+		decoded, err := wav.NewReader(bytes.NewReader(stream.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := readAll(t, decoded)
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			p, err := wav.SourcePCM(bytes.NewReader(in.data), bitDepth, wav.WithByteOrder(order))
+			if err != nil {
+				t.Fatalf("%d bits: unexpected error: %v", bitDepth, err)
+			}
+			var raw bytes.Buffer
+			if _, err := p.WriteTo(&raw); err != nil {
+				t.Fatalf("%d bits: unexpected write error: %v", bitDepth, err)
+			}
+			// partial frame is ignored.
+			raw.WriteByte(0)
+			r, err := wav.NewRawReader(bytes.NewReader(raw.Bytes()), p.Format(), wav.WithByteOrder(order))
+			if err != nil {
+				t.Fatalf("%d bits: unexpected error: %v", bitDepth, err)
+			}
+			if f := r.Format(); f != p.Format() {
+				t.Errorf("%d bits: expected format %+v got %+v", bitDepth, p.Format(), f)
+			}
+			if result := readAll(t, r); !reflect.DeepEqual(expected, result) {
+				t.Errorf("%d bits %v: expected samples %v got %v", bitDepth, order, expected, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package wav
+
+import "pipelined.dev/signal"
+
+// Planar makes Reader fill buffers in planar layout: all samples of the
+// first channel, then all samples of the second channel and so on. Every
+// channel takes the length of the buffer, so channel c starts at sample
+// c*dst.Length(). If fewer frames than the buffer length are read, only
+// the first frames of every channel are set. Source always produces
+// interleaved buffers and ignores this option.
+func Planar() Option {
+	return func(o *options) {
+		o.planar = true
+	}
+}
+
+// deinterleave copies provided number of frames of interleaved buffer
+// into planar buffer.
+func deinterleave(src, dst signal.Floating, frames int) {
+	channels, length := src.Channels(), dst.Length()
+	for i := 0; i < frames; i++ {
+		for c := 0; c < channels; c++ {
+			dst.SetSample(c*length+i, src.Sample(i*channels+c))
+		}
+	}
+}
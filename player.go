@@ -0,0 +1,156 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// seekBufferSize is the number of frames discarded at once by Player.Seek.
+const seekBufferSize = 1024
+
+// Player decodes frames of wav stream from any position. It keeps the
+// position between calls, so it can be seeked and read repeatedly, e.g.
+// for interactive playback. It accepts the same options as Reader.
+type Player struct {
+	rs       io.ReadSeeker
+	options  []Option
+	reader   *Reader
+	length   int64
+	position int64
+}
+
+// NewPlayer returns a new player of wav stream positioned at the first
+// frame.
+func NewPlayer(rs io.ReadSeeker, options ...Option) (*Player, error) {
+	p := Player{
+		rs:      rs,
+		options: options,
+	}
+	if err := p.rewind(); err != nil {
+		return nil, err
+	}
+	length, err := p.reader.Frames()
+	if err != nil {
+		return nil, err
+	}
+	p.length = length
+	return &p, nil
+}
+
+// rewind decodes the stream from the first frame.
+func (p *Player) rewind() error {
+	if _, err := p.rs.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking stream: %w", err)
+	}
+	r, err := NewReader(p.rs, p.options...)
+	if err != nil {
+		return err
+	}
+	p.reader = r
+	p.position = 0
+	return nil
+}
+
+// Seek sets the position to provided offset in frames, interpreted
+// according to whence as io.Seeker does, and returns the new position.
+// Linear PCM and float streams are seeked to the offset of the frame in
+// data chunk. Frames of compressed and resampled streams or streams with
+// selected channels are decoded from the first frame and discarded
+// before the position, so it works with every option.
+func (p *Player) Seek(offset int64, whence int) (int64, error) {
+	frame := offset
+	switch whence {
+	case io.SeekCurrent:
+		frame += p.position
+	case io.SeekEnd:
+		frame += p.length
+	}
+	if frame < 0 || frame > p.length {
+		return p.position, fmt.Errorf("frame %d is out of range 0-%d", frame, p.length)
+	}
+	seeked, err := p.reader.seekFrame(frame)
+	if err != nil {
+		return p.position, err
+	}
+	if !seeked {
+		if frame < p.position {
+			if err := p.rewind(); err != nil {
+				return p.position, err
+			}
+		}
+		if err := p.reader.skip(frame-p.position, seekBufferSize); err != nil {
+			return p.position, err
+		}
+	}
+	p.position = frame
+	return frame, nil
+}
+
+// seekFrame seeks the data of linear PCM or float stream to provided
+// frame. It returns false if the stream can't be seeked and frames have
+// to be decoded.
+func (r *Reader) seekFrame(frame int64) (bool, error) {
+	var data io.Reader
+	switch {
+	case r.float != nil:
+		data = r.float.r
+	case r.codec == nil && r.filter == nil && r.decoder.PCMChunk != nil:
+		data = r.decoder.PCMChunk.R
+	}
+	// frames of resampler depend on the previous ones.
+	limited, ok := data.(*io.LimitedReader)
+	if !ok || r.resampler != nil {
+		return false, nil
+	}
+	c, err := readContainer(r.rs)
+	if err != nil {
+		return false, err
+	}
+	f, err := readFormat(r.rs, c)
+	if err != nil {
+		return false, err
+	}
+	h, ok := c.find(dataID)
+	if !ok {
+		return false, nil
+	}
+	size := int64(h.Size)
+	if available := c.Size - h.Offset; available < size {
+		size = available
+	}
+	offset := frame * int64(f.BlockAlign)
+	if offset > size {
+		offset = size
+	}
+	if _, err := r.rs.Seek(h.Offset+offset, io.SeekStart); err != nil {
+		return false, fmt.Errorf("error seeking frame %d: %w", frame, err)
+	}
+	limited.N = size - offset
+	r.decodedFrames = frame
+	return true, nil
+}
+
+// Read decodes frames from current position into provided buffer. It
+// returns the number of frames read and io.EOF when the stream is done.
+func (p *Player) Read(dst signal.Floating) (int, error) {
+	n, err := p.reader.Read(dst)
+	p.position += int64(n)
+	return n, err
+}
+
+// Len returns the number of frames declared by the stream.
+func (p *Player) Len() int64 {
+	return p.length
+}
+
+// Position returns the index of the next decoded frame.
+func (p *Player) Position() int64 {
+	return p.position
+}
+
+// Format returns the format of decoded frames.
+func (p *Player) Format() Format {
+	return p.reader.Format()
+}
@@ -0,0 +1,128 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestPlayer(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 44100)),
+		chunk("data", rampData(3000)),
+	)
+	p, err := wav.NewPlayer(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Len() != 3000 || p.Position() != 0 {
+		t.Errorf("unexpected length %d and position %d", p.Len(), p.Position())
+	}
+	if f := p.Format(); f.SampleRate != 44100 || f.Channels != 1 || f.BitDepth != signal.BitDepth16 {
+		t.Errorf("unexpected format %+v", f)
+	}
+
+	buf := signal.Allocator{Channels: 1, Length: 10, Capacity: 10}.Float64()
+	// forward, backward and repeated seeks.
+	for _, frame := range []int64{0, 2500, 1200, 1200, 2995} {
+		if position, err := p.Seek(frame, io.SeekStart); err != nil || position != frame {
+			t.Fatalf("seek %d: unexpected position %d: %v", frame, position, err)
+		}
+		if p.Position() != frame {
+			t.Errorf("seek %d: position %d", frame, p.Position())
+		}
+		n, err := p.Read(buf)
+		if err != nil {
+			t.Fatalf("seek %d: unexpected read error: %v", frame, err)
+		}
+		expected := 10
+		if remaining := int(p.Len() - frame); remaining < expected {
+			expected = remaining
+		}
+		if n != expected {
+			t.Errorf("seek %d: expected %d frames got %d", frame, expected, n)
+		}
+		for i := 0; i < n; i++ {
+			if v := buf.Sample(i); v != float64(frame+int64(i))/32767 {
+				t.Fatalf("seek %d: frame %d has sample %v", frame, i, v)
+			}
+		}
+		if p.Position() != frame+int64(n) {
+			t.Errorf("seek %d: position %d after read", frame, p.Position())
+		}
+	}
+
+	// relative seeks.
+	if position, err := p.Seek(-5, io.SeekCurrent); err != nil || position != 3000-5 {
+		t.Errorf("expected position %d got %d: %v", 3000-5, position, err)
+	}
+	if position, err := p.Seek(0, io.SeekEnd); err != nil || position != p.Len() {
+		t.Fatalf("expected position %d got %d: %v", p.Len(), position, err)
+	}
+	if _, err := p.Read(buf); err != io.EOF {
+		t.Errorf("expected EOF at the end got %v", err)
+	}
+	for _, frame := range []int64{-1, 3001} {
+		if _, err := p.Seek(frame, io.SeekStart); err == nil {
+			t.Errorf("expected error seeking frame %d", frame)
+		}
+	}
+
+	// linear PCM is seeked without decoding the frames before position.
+	counter := readCounter{rs: bytes.NewReader(data)}
+	if p, err = wav.NewPlayer(&counter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, frame := range []int64{2990, 100, 2000} {
+		counter.n = 0
+		if _, err := p.Seek(frame, io.SeekStart); err != nil {
+			t.Fatalf("seek %d: unexpected error: %v", frame, err)
+		}
+		if n, err := p.Read(buf); err != nil || n != 10 {
+			t.Fatalf("seek %d: expected 10 frames got %d: %v", frame, n, err)
+		}
+		if v := buf.Sample(0); v != float64(frame)/32767 {
+			t.Errorf("seek %d: unexpected sample %v", frame, v)
+		}
+		if counter.n > 200 {
+			t.Errorf("seek %d: expected byte seek got %d bytes read", frame, counter.n)
+		}
+	}
+
+	// float stream is seeked as well.
+	pcm := make([]byte, 8*100)
+	for i := 0; i < 100; i++ {
+		binary.LittleEndian.PutUint64(pcm[8*i:], math.Float64bits(float64(i)/100))
+	}
+	if p, err = wav.NewPlayer(bytes.NewReader(float64Stream(pcm))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.Seek(95, io.SeekStart); err != nil {
+		t.Fatalf("unexpected float seek error: %v", err)
+	}
+	if n, err := p.Read(buf); err != nil || n != 5 || buf.Sample(0) != 0.95 {
+		t.Errorf("expected 5 float frames from 0.95 got %d frames from %v: %v", n, buf.Sample(0), err)
+	}
+}
+
+// readCounter counts the bytes read from the stream.
+type readCounter struct {
+	rs io.ReadSeeker
+	n  int
+}
+
+func (c *readCounter) Read(p []byte) (int, error) {
+	n, err := c.rs.Read(p)
+	c.n += n
+	return n, err
+}
+
+func (c *readCounter) Seek(offset int64, whence int) (int64, error) {
+	return c.rs.Seek(offset, whence)
+}
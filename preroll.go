@@ -0,0 +1,49 @@
+package wav
+
+import (
+	"time"
+
+	"pipelined.dev/signal"
+)
+
+// silenceBlockSize is the number of silence frames written at once if
+// writer has no buffers allocated yet.
+const silenceBlockSize = 4096
+
+// WithPreroll makes Sink write digital silence of provided duration
+// before the first buffer. The duration is converted into a whole number
+// of frames at the sample rate of the stream. Silence frames are counted
+// in the data chunk size as any other frames.
+func WithPreroll(d time.Duration) Option {
+	return func(o *options) {
+		o.preroll = d
+	}
+}
+
+// writeSilence writes provided number of silent frames.
+func (w *Writer) writeSilence(frames int) error {
+	// reuse the size of allocated buffers.
+	size := len(w.pcm.Data) / w.encoder.format.Channels
+	if size == 0 {
+		size = silenceBlockSize
+	}
+	if frames < size {
+		size = frames
+	}
+	silence := signal.Allocator{
+		Channels: w.encoder.format.Channels,
+		Length:   size,
+		Capacity: size,
+	}.Float64()
+	for frames > 0 {
+		n := size
+		if frames < n {
+			n = frames
+		}
+		if _, err := w.write(silence.Slice(0, n)); err != nil {
+			return err
+		}
+		frames -= n
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package wav_test
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestPreroll(t *testing.T) {
+	tests := []struct {
+		bitDepth signal.BitDepth
+		preroll  time.Duration
+		silence  []byte
+	}{
+		{
+			bitDepth: signal.BitDepth16,
+			preroll:  time.Second,
+			silence:  []byte{0, 0},
+		},
+		{
+			bitDepth: signal.BitDepth8,
+			preroll:  250 * time.Millisecond,
+			silence:  []byte{0x80},
+		},
+	}
+	for _, test := range tests {
+		var out buffer
+		transcode(t,
+			floatSource(1000, 2, []float64{0.5, 0.5}),
+			wav.Sink(&out, test.bitDepth, wav.WithPreroll(test.preroll)),
+		)
+		frameSize := 2 * len(test.silence)
+		frames := int(test.preroll / time.Millisecond)
+		expectedSize := (frames + 1) * frameSize
+		if size := binary.LittleEndian.Uint32(out.data[40:]); int(size) != expectedSize {
+			t.Errorf("preroll %v: expected data size %d got %d", test.preroll, expectedSize, size)
+		}
+		pcm := out.data[44:]
+		for i := 0; i < frames*frameSize; i++ {
+			if pcm[i] != test.silence[i%len(test.silence)] {
+				t.Fatalf("preroll %v: byte %d isn't silent: %d", test.preroll, i, pcm[i])
+			}
+		}
+		if pcm[frames*frameSize] == test.silence[0] {
+			t.Errorf("preroll %v: program audio is missing", test.preroll)
+		}
+	}
+
+	// preroll is written even without buffers.
+	var out buffer
+	transcode(t,
+		floatSource(1000, 1, nil),
+		wav.Sink(&out, signal.BitDepth16, wav.WithPreroll(10*time.Millisecond)),
+	)
+	if size := binary.LittleEndian.Uint32(out.data[40:]); size != 20 {
+		t.Errorf("expected data size 20 got %d", size)
+	}
+}
@@ -0,0 +1,47 @@
+package wav
+
+import "io"
+
+// Chunks holds the chunks that Source preserved for Sink. Zero value is
+// ready to use.
+type Chunks struct {
+	// chunks located before and after data chunk.
+	before []rawChunk
+	after  []rawChunk
+}
+
+// PreserveUnknownChunks makes Source keep the chunks that this package
+// doesn't interpret, for example project references of Pro Tools or
+// Logic. Payloads are kept as is. Sink with the same option writes them
+// back: chunks found before data are written between fmt and data chunks,
+// chunks found after data are written after data chunk.
+func PreserveUnknownChunks(c *Chunks) Option {
+	return func(o *options) {
+		o.preserved = c
+	}
+}
+
+// preserve reads unknown chunks of the container.
+func (c *Chunks) preserve(rs io.ReadSeeker, ct container) error {
+	c.before, c.after = nil, nil
+	afterData := false
+	for _, h := range ct.chunks {
+		if h.ID == dataID {
+			afterData = true
+			continue
+		}
+		if knownChunks[h.ID] {
+			continue
+		}
+		payload, err := readPayload(rs, h)
+		if err != nil {
+			return err
+		}
+		if afterData {
+			c.after = append(c.after, rawChunk{ID: h.ID, Payload: payload})
+		} else {
+			c.before = append(c.before, rawChunk{ID: h.ID, Payload: payload})
+		}
+	}
+	return nil
+}
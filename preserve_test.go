@@ -0,0 +1,66 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestPreserveUnknownChunks(t *testing.T) {
+	minf := chunk("minf", []byte{1, 2, 3})
+	elm1 := chunk("elm1", []byte{4, 5, 6, 7})
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 8000)),
+		minf,
+		chunk("data", []byte{1, 0, 2, 0}),
+		elm1,
+	)
+
+	var chunks wav.Chunks
+	var out buffer
+	transcode(t,
+		wav.Source(bytes.NewReader(data), wav.PreserveUnknownChunks(&chunks)),
+		wav.Sink(&out, signal.BitDepth16, wav.PreserveUnknownChunks(&chunks)),
+	)
+	expected := []string{"fmt ", "minf", "data", "elm1"}
+	if ids := chunkIDs(out.data); !reflect.DeepEqual(expected, ids) {
+		t.Fatalf("expected chunks %v got %v", expected, ids)
+	}
+	if !bytes.Contains(out.data, minf) || !bytes.Contains(out.data, elm1) {
+		t.Errorf("chunks payload isn't preserved")
+	}
+
+	var without buffer
+	transcode(t,
+		wav.Source(bytes.NewReader(data)),
+		wav.Sink(&without, signal.BitDepth16),
+	)
+	if ids := chunkIDs(without.data); !reflect.DeepEqual([]string{"fmt ", "data"}, ids) {
+		t.Errorf("unexpected chunks without preserve: %v", ids)
+	}
+}
+
+func TestPreserveLogicChunks(t *testing.T) {
+	lgwv := chunk("LGWV", []byte("project reference"))
+	resu := chunk("ResU", []byte{0x78, 0x9C, 0x01, 0x02, 0x03})
+	data := riff(
+		chunk("fmt ", fmtPayload(2, 16, 4, 44100)),
+		lgwv,
+		chunk("data", []byte{1, 0, 2, 0, 3, 0, 4, 0}),
+		resu,
+	)
+
+	var chunks wav.Chunks
+	var out buffer
+	transcode(t,
+		wav.Source(bytes.NewReader(data), wav.PreserveUnknownChunks(&chunks)),
+		wav.Sink(&out, signal.BitDepth16, wav.PreserveUnknownChunks(&chunks)),
+	)
+	if !bytes.Equal(data, out.data) {
+		t.Errorf("expected:\n%v\ngot:\n%v", data, out.data)
+	}
+}
@@ -0,0 +1,112 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"pipelined.dev/signal"
+)
+
+// Info describes wav stream as declared by its headers.
+type Info struct {
+	Format
+	// AudioFormat is the format code of fmt chunk.
+	AudioFormat uint16
+	// ValidBits is the number of meaningful bits of every sample. It's
+	// less than BitDepth if extensible format declares padded samples,
+	// e.g. 20 valid bits in 24-bit container. This is informational,
+	// samples are always decoded with the full width of BitDepth.
+	ValidBits int
+	// BlockAlign is the size of every block in bytes. It's the size of
+	// frame for linear formats.
+	BlockAlign int
+	// SamplesPerBlock is the number of frames in every block of compressed
+	// format, as declared by fmt extension. It's zero for linear formats
+	// and if the extension doesn't declare it.
+	SamplesPerBlock int
+	// DataSize is the size of data chunk in bytes.
+	DataSize int64
+	// Frames is the number of frames in data chunk. For compressed
+	// formats it's the number of frames in all blocks, the last block
+	// might contain fewer frames.
+	Frames int64
+}
+
+// Probe returns the information about wav stream without decoding the
+// samples. Only chunk headers and fmt chunk are read, so the values are
+// declared by the file and not verified. The stream is rewinded to the
+// start afterwards.
+func Probe(rs io.ReadSeeker) (Info, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return Info{}, err
+	}
+	f, err := readFormat(rs, c)
+	if err != nil {
+		return Info{}, err
+	}
+	if c, err = waveListContainer(rs, c); err != nil {
+		return Info{}, err
+	}
+	return c.info(f), nil
+}
+
+// info returns the information of container with provided format.
+func (c container) info(f format) Info {
+	info := Info{
+		Format: Format{
+			SampleRate: signal.Frequency(f.SampleRate),
+			Channels:   int(f.Channels),
+			BitDepth:   signal.BitDepth(f.BitsPerSample),
+		},
+		AudioFormat:     f.AudioFormat,
+		ValidBits:       f.validBits(),
+		BlockAlign:      int(f.BlockAlign),
+		SamplesPerBlock: f.samplesPerBlock(),
+	}
+	if h, ok := c.find(dataID); ok {
+		info.DataSize = int64(h.Size)
+		if f.BlockAlign > 0 {
+			info.Frames = info.DataSize / int64(f.BlockAlign)
+			if info.SamplesPerBlock > 0 {
+				info.Frames *= int64(info.SamplesPerBlock)
+			}
+		}
+	}
+	return info
+}
+
+// codecNames are the names of audio formats.
+var codecNames = map[uint16]string{
+	formatPCM:        "PCM",
+	formatFloat:      "IEEE float",
+	formatIMAADPCM:   "IMA ADPCM",
+	formatGSM:        "GSM 6.10",
+	formatExtensible: "extensible",
+}
+
+// Codec returns the name of the audio format.
+func (i Info) Codec() string {
+	if name, ok := codecNames[i.AudioFormat]; ok {
+		return name
+	}
+	return fmt.Sprintf("format 0x%04X", i.AudioFormat)
+}
+
+// Duration returns the duration of frames at the declared sample rate.
+func (i Info) Duration() time.Duration {
+	return framesDuration(i.Frames, i.SampleRate)
+}
+
+// framesDuration returns the duration of frames at provided sample rate.
+// Integer math is used, so the result is exact for any whole rate, up to
+// the truncation to nanoseconds.
+func framesDuration(frames int64, sampleRate signal.Frequency) time.Duration {
+	rate := int64(sampleRate)
+	if rate == 0 {
+		return 0
+	}
+	seconds, rest := frames/rate, frames%rate
+	return time.Duration(seconds)*time.Second + time.Duration(rest*int64(time.Second)/rate)
+}
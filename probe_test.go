@@ -0,0 +1,123 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestProbe(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(2, 16, 4, 48000)),
+		chunk("data", make([]byte, 40)),
+	)
+	info, err := wav.Probe(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := wav.Info{
+		Format:      wav.Format{SampleRate: 48000, Channels: 2, BitDepth: signal.BitDepth16},
+		AudioFormat: 1,
+		ValidBits:   16,
+		BlockAlign:  4,
+		DataSize:    40,
+		Frames:      10,
+	}
+	if info != expected {
+		t.Errorf("expected %+v got %+v", expected, info)
+	}
+
+	// extensible format with 20 valid bits.
+	extensible := fmtPayload(1, 24, 3, 48000)
+	binary.LittleEndian.PutUint16(extensible[0:], 0xFFFE)
+	extension := make([]byte, 24)
+	binary.LittleEndian.PutUint16(extension[0:], 20)
+	extensible = append(append(extensible, 22, 0), extension...)
+	info, err = wav.Probe(bytes.NewReader(riff(
+		chunk("fmt ", extensible),
+		chunk("data", make([]byte, 6)),
+	)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.BitDepth != signal.BitDepth24 || info.ValidBits != 20 || info.Frames != 2 {
+		t.Errorf("unexpected info of extensible format: %+v", info)
+	}
+
+	// IMA ADPCM declares samples per block.
+	info, err = wav.Probe(bytes.NewReader(riff(
+		chunk("fmt ", imaFmtPayload(2, 256, 22050)),
+		chunk("data", make([]byte, 3*256)),
+	)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.BlockAlign != 256 || info.SamplesPerBlock != 249 || info.Frames != 3*249 {
+		t.Errorf("unexpected info of IMA ADPCM format: %+v", info)
+	}
+
+	f, err := os.Open(notWav)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	if _, err := wav.Probe(f); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %v", err)
+	}
+}
+
+func TestOddSampleRates(t *testing.T) {
+	for _, rate := range []uint32{47952, 44056} {
+		frames := 3*rate + 1
+		expected := 3*time.Second + time.Second/time.Duration(rate)
+		data := riff(
+			chunk("fmt ", fmtPayload(1, 16, 2, rate)),
+			chunk("data", make([]byte, 2*frames)),
+		)
+		info, err := wav.Probe(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.SampleRate != signal.Frequency(rate) || info.Frames != int64(frames) {
+			t.Errorf("rate %d: unexpected info %+v", rate, info)
+		}
+		if d := info.Duration(); d != expected {
+			t.Errorf("rate %d: expected duration %v got %v", rate, expected, d)
+		}
+
+		r, err := wav.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Format().SampleRate != signal.Frequency(rate) {
+			t.Errorf("rate %d: reader has rate %v", rate, r.Format().SampleRate)
+		}
+		if d, err := r.Duration(); err != nil || d != expected {
+			t.Errorf("rate %d: expected reader duration %v got %v: %v", rate, expected, d, err)
+		}
+
+		var out buffer
+		transcode(t, wav.Source(bytes.NewReader(data)), wav.Sink(&out, signal.BitDepth16))
+		if written := binary.LittleEndian.Uint32(out.data[24:]); written != rate {
+			t.Errorf("rate %d: written rate %d", rate, written)
+		}
+		if byteRate := binary.LittleEndian.Uint32(out.data[28:]); byteRate != 2*rate {
+			t.Errorf("rate %d: written byte rate %d", rate, byteRate)
+		}
+
+		// an hour since midnight is the whole number of frames.
+		tc := wav.Timecode{Hours: 1, FPS: 24}
+		if frame := wav.FrameAtTimecode(tc, signal.Frequency(rate)); frame != 3600*int64(rate) {
+			t.Errorf("rate %d: unexpected frame of %v: %d", rate, tc, frame)
+		}
+		if result := wav.TimecodeAtFrame(3600*int64(rate), signal.Frequency(rate), 24, false); result != tc {
+			t.Errorf("rate %d: expected timecode %v got %v", rate, tc, result)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"pipelined.dev/signal"
+)
+
+// Profile is the set of requirements for delivered wav files, as defined
+// by QC specifications. Empty lists and zero values are not checked.
+type Profile struct {
+	AudioFormats []uint16
+	SampleRates  []signal.Frequency
+	BitDepths    []signal.BitDepth
+	Channels     []int
+	MinDuration  time.Duration
+	MaxDuration  time.Duration
+	// RequireBext requires the broadcast extension chunk.
+	RequireBext bool
+}
+
+// Violation is the requirement of profile that file doesn't meet.
+type Violation struct {
+	// Field is the name of Profile field that is violated.
+	Field   string
+	Message string
+}
+
+// String returns the violation message with its field.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// ValidateProfile checks the headers of wav stream against provided
+// profile and returns all found violations. Samples are not decoded. An
+// error is returned if the stream can't be probed. The stream is rewinded
+// to the start afterwards.
+func ValidateProfile(rs io.ReadSeeker, p Profile) ([]Violation, error) {
+	info, err := Probe(rs)
+	if err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	violate := func(field, format string, args ...interface{}) {
+		violations = append(violations, Violation{
+			Field:   field,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	if len(p.AudioFormats) > 0 && !containsUint16(p.AudioFormats, info.AudioFormat) {
+		violate("AudioFormats", "audio format %s isn't allowed", info.Codec())
+	}
+	if len(p.SampleRates) > 0 && !containsFrequency(p.SampleRates, info.SampleRate) {
+		violate("SampleRates", "sample rate %v isn't allowed", info.SampleRate)
+	}
+	if len(p.BitDepths) > 0 && !containsBitDepth(p.BitDepths, info.BitDepth) {
+		violate("BitDepths", "bit depth %v isn't allowed", info.BitDepth)
+	}
+	if len(p.Channels) > 0 && !containsInt(p.Channels, info.Channels) {
+		violate("Channels", "%d channels aren't allowed", info.Channels)
+	}
+	if d := info.Duration(); p.MinDuration > 0 && d < p.MinDuration {
+		violate("MinDuration", "duration %v is shorter than %v", d, p.MinDuration)
+	}
+	if d := info.Duration(); p.MaxDuration > 0 && d > p.MaxDuration {
+		violate("MaxDuration", "duration %v is longer than %v", d, p.MaxDuration)
+	}
+	if p.RequireBext {
+		b, err := ReadBext(rs)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			violate("RequireBext", "bext chunk is missing")
+		}
+	}
+	return violations, nil
+}
+
+func containsUint16(values []uint16, v uint16) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFrequency(values []signal.Frequency, v signal.Frequency) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsBitDepth(values []signal.BitDepth, v signal.BitDepth) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
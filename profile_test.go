@@ -0,0 +1,55 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestValidateProfile(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(2, 16, 4, 44100)),
+		chunk("data", make([]byte, 4*4410)),
+	)
+	deliverable := wav.Profile{
+		AudioFormats: []uint16{1},
+		SampleRates:  []signal.Frequency{48000},
+		BitDepths:    []signal.BitDepth{signal.BitDepth24},
+		Channels:     []int{1, 2},
+		MinDuration:  time.Second,
+		RequireBext:  true,
+	}
+	violations, err := wav.ValidateProfile(bytes.NewReader(data), deliverable)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fields []string
+	for _, v := range violations {
+		fields = append(fields, v.Field)
+	}
+	expected := []string{"SampleRates", "BitDepths", "MinDuration", "RequireBext"}
+	if len(fields) != len(expected) {
+		t.Fatalf("expected violations %v got %v", expected, violations)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("expected violations %v got %v", expected, violations)
+		}
+	}
+
+	violations, err = wav.ValidateProfile(bytes.NewReader(data), wav.Profile{
+		SampleRates: []signal.Frequency{44100},
+		MaxDuration: time.Second,
+	})
+	if err != nil || len(violations) != 0 {
+		t.Errorf("unexpected violations %v: %v", violations, err)
+	}
+
+	if _, err := wav.ValidateProfile(bytes.NewReader([]byte("not wav")), deliverable); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %v", err)
+	}
+}
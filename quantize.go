@@ -0,0 +1,208 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"pipelined.dev/signal"
+)
+
+// Rounding defines how scaled floating-point samples are rounded to PCM
+// integer values by Sink.
+type Rounding int
+
+const (
+	// RoundTruncate discards the fractional part, rounding towards zero.
+	// This is the default mode.
+	RoundTruncate Rounding = iota
+	// RoundHalfUp rounds to the nearest integer, ties are rounded towards
+	// positive infinity.
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest integer, ties are rounded to the
+	// even integer.
+	RoundHalfEven
+)
+
+// WithRounding sets the rounding mode that Sink applies to the samples
+// before writing PCM. Default is RoundTruncate.
+func WithRounding(r Rounding) Option {
+	return func(o *options) {
+		o.rounding = r
+	}
+}
+
+// roundFunc returns a function that implements the rounding mode.
+func (r Rounding) roundFunc() func(float64) float64 {
+	switch r {
+	case RoundHalfUp:
+		return func(v float64) float64 {
+			return math.Floor(v + 0.5)
+		}
+	case RoundHalfEven:
+		return math.RoundToEven
+	default:
+		return math.Trunc
+	}
+}
+
+// Overflow defines how Sink handles samples that exceed the full scale
+// range of output bit depth.
+type Overflow int
+
+const (
+	// OverflowClamp limits the samples to the full scale range. This is
+	// the default mode.
+	OverflowClamp Overflow = iota
+	// OverflowWrap wraps the samples around the full scale range as
+	// two's complement integers do, e.g. 16-bit sample 1.5 is written as
+	// -16386 and -1.5 as 16384. This is the behavior of integer
+	// conversion that doesn't check the range.
+	OverflowWrap
+	// OverflowError makes Sink fail on the first sample that exceeds the
+	// full scale range.
+	OverflowError
+)
+
+// ErrSampleOverflow is returned by Sink when the sample exceeds the full
+// scale range and OverflowError mode is used.
+var ErrSampleOverflow = errors.New("sample overflow")
+
+// WithOverflow sets the mode that Sink applies to the samples beyond the
+// full scale range. OverflowClamp is the default since this option was
+// added: samples beyond the range are limited to it, so hot signals
+// distort instead of turning into loud clicks. Streams that depend on
+// wrapped samples have to request OverflowWrap explicitly now.
+func WithOverflow(m Overflow) Option {
+	return func(o *options) {
+		o.overflow = m
+	}
+}
+
+// WithChannelGains makes Sink multiply samples of every channel by the
+// corresponding gain before quantization. The number of gains must match
+// the number of channels.
+func WithChannelGains(gains []float64) Option {
+	return func(o *options) {
+		o.gains = gains
+	}
+}
+
+// quantizer converts floating-point samples into PCM integers.
+type quantizer struct {
+	round    func(float64) float64
+	overflow Overflow
+	midSide  bool
+	// per-channel gains, nil if not applied.
+	gains []float64
+	// range of float samples, nil if not clamped.
+	clamp *[2]float64
+}
+
+// newQuantizer returns quantizer configured by Sink options.
+func (o *options) newQuantizer(channels int) (quantizer, error) {
+	if o.gains != nil && len(o.gains) != channels {
+		return quantizer{}, fmt.Errorf("%d channel gains provided for %d channels", len(o.gains), channels)
+	}
+	if o.midSide && channels != 2 {
+		return quantizer{}, fmt.Errorf("mid/side requires 2 channels, got %d", channels)
+	}
+	return quantizer{
+		round:    o.rounding.roundFunc(),
+		overflow: o.overflow,
+		midSide:  o.midSide,
+		gains:    o.gains,
+		clamp:    o.clamp,
+	}, nil
+}
+
+// floatingAsSigned converts floating-point samples into signed
+// fixed-point. The scaling is the same as in signal.FloatingAsSigned.
+// Returns a number of samples written per channel.
+func (q quantizer) floatingAsSigned(src signal.Floating, dst signal.Signed) (int, error) {
+	length := src.Len()
+	if dst.Len() < length {
+		length = dst.Len()
+	}
+	msv := float64(dst.BitDepth().MaxSignedValue())
+	for i := 0; i < length; i++ {
+		sample, err := q.quantize(q.sample(src, i), msv)
+		if err != nil {
+			return 0, fmt.Errorf("sample %d of channel %d: %w", i/dst.Channels(), i%dst.Channels(), err)
+		}
+		dst.SetSample(i, sample)
+	}
+	if q.midSide {
+		for i := 0; i+1 < length; i += 2 {
+			mid, side := encodeMidSideInt(dst.Sample(i), dst.Sample(i+1), dst.BitDepth())
+			dst.SetSample(i, mid)
+			dst.SetSample(i+1, side)
+		}
+	}
+	return signal.ChannelLength(length, dst.Channels()), nil
+}
+
+// floatingAsUnsigned converts floating-point samples into unsigned
+// fixed-point. The scaling is the same as in signal.FloatingAsUnsigned.
+// Returns a number of samples written per channel.
+func (q quantizer) floatingAsUnsigned(src signal.Floating, dst signal.Unsigned) (int, error) {
+	length := src.Len()
+	if dst.Len() < length {
+		length = dst.Len()
+	}
+	msv := float64(dst.BitDepth().MaxSignedValue())
+	offset := int64(msv) + 1
+	for i := 0; i < length; i++ {
+		sample, err := q.quantize(q.sample(src, i), msv)
+		if err != nil {
+			return 0, fmt.Errorf("sample %d of channel %d: %w", i/dst.Channels(), i%dst.Channels(), err)
+		}
+		dst.SetSample(i, uint64(sample+offset))
+	}
+	if q.midSide {
+		for i := 0; i+1 < length; i += 2 {
+			mid, side := encodeMidSideInt(int64(dst.Sample(i))-offset, int64(dst.Sample(i+1))-offset, dst.BitDepth())
+			dst.SetSample(i, uint64(mid+offset))
+			dst.SetSample(i+1, uint64(side+offset))
+		}
+	}
+	return signal.ChannelLength(length, dst.Channels()), nil
+}
+
+// sample returns the sample with applied gain.
+func (q quantizer) sample(src signal.Floating, i int) float64 {
+	v := src.Sample(i)
+	if q.gains == nil {
+		return v
+	}
+	return v * q.gains[i%len(q.gains)]
+}
+
+// quantize scales the sample to the signed range defined by maximum signed
+// value and rounds it. Values beyond the range are handled according to
+// overflow mode.
+func (q quantizer) quantize(f, msv float64) (int64, error) {
+	if f > 0 {
+		f = q.round(snap(f * msv))
+	} else {
+		f = q.round(snap(f * (msv + 1)))
+	}
+	if f <= msv && f >= -(msv+1) {
+		return int64(f), nil
+	}
+	switch q.overflow {
+	case OverflowWrap:
+		span := 2 * (msv + 1)
+		f = math.Mod(f+msv+1, span)
+		if f < 0 {
+			f += span
+		}
+		return int64(f - msv - 1), nil
+	case OverflowError:
+		return 0, fmt.Errorf("%w: %v", ErrSampleOverflow, f)
+	}
+	if f > msv {
+		return int64(msv), nil
+	}
+	return -int64(msv) - 1, nil
+}
@@ -0,0 +1,111 @@
+package wav_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestRounding(t *testing.T) {
+	// values at 0.5 LSB boundaries of 16-bit.
+	samples := []float64{
+		0.5 / 32767,
+		1.5 / 32767,
+		2.5 / 32767,
+		-0.5 / 32768,
+		-1.5 / 32768,
+		-2.5 / 32768,
+	}
+	tests := []struct {
+		rounding wav.Rounding
+		expected []int16
+	}{
+		{
+			rounding: wav.RoundTruncate,
+			expected: []int16{0, 1, 2, 0, -1, -2},
+		},
+		{
+			rounding: wav.RoundHalfUp,
+			expected: []int16{1, 2, 3, 0, -1, -2},
+		},
+		{
+			rounding: wav.RoundHalfEven,
+			expected: []int16{0, 2, 2, 0, -2, -2},
+		},
+	}
+	for _, test := range tests {
+		var out buffer
+		transcode(t,
+			floatSource(44100, 1, samples),
+			wav.Sink(&out, signal.BitDepth16, wav.WithRounding(test.rounding)),
+		)
+		if result := int16Samples(out.data); !reflect.DeepEqual(test.expected, result) {
+			t.Errorf("rounding %v: expected %v got %v", test.rounding, test.expected, result)
+		}
+	}
+}
+
+func TestChannelGains(t *testing.T) {
+	var out buffer
+	transcode(t,
+		floatSource(44100, 2, []float64{0.5, 0.5, -0.5, -0.5}),
+		wav.Sink(&out, signal.BitDepth16, wav.WithChannelGains([]float64{1, 0.5})),
+	)
+	expected := []int16{16383, 8191, -16384, -8192}
+	if result := int16Samples(out.data); !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected %v got %v", expected, result)
+	}
+
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Source: floatSource(44100, 2, []float64{0.5, 0.5}),
+		Sink:   wav.Sink(&out, signal.BitDepth16, wav.WithChannelGains([]float64{1})),
+	})
+	if err == nil {
+		t.Errorf("expected error for gains mismatch, got pipe: %v", p)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	samples := []float64{1.5, -1.5, 0.5}
+	tests := []struct {
+		overflow wav.Overflow
+		expected []int16
+	}{
+		{
+			overflow: wav.OverflowClamp,
+			expected: []int16{32767, -32768, 16383},
+		},
+		{
+			overflow: wav.OverflowWrap,
+			expected: []int16{-16386, 16384, 16383},
+		},
+	}
+	for _, test := range tests {
+		var out buffer
+		transcode(t,
+			floatSource(44100, 1, samples),
+			wav.Sink(&out, signal.BitDepth16, wav.WithOverflow(test.overflow)),
+		)
+		if result := int16Samples(out.data); !reflect.DeepEqual(test.expected, result) {
+			t.Errorf("overflow %v: expected %v got %v", test.overflow, test.expected, result)
+		}
+	}
+
+	var out buffer
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Source: floatSource(44100, 1, samples),
+		Sink:   wav.Sink(&out, signal.BitDepth16, wav.WithOverflow(wav.OverflowError)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected pipe error: %v", err)
+	}
+	if err := pipe.Wait(p.Start(context.Background())); !errors.Is(err, wav.ErrSampleOverflow) {
+		t.Errorf("expected overflow error, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"pipelined.dev/pipe"
+)
+
+// rangeBlockSize is the minimal number of bytes requested per range.
+const rangeBlockSize = 64 * 1024
+
+// RangeFunc returns a reader for length bytes of the resource starting at
+// offset. It's called by range source for every block that must be
+// fetched, for example to perform HTTP range request.
+type RangeFunc func(offset, length int64) (io.ReadCloser, error)
+
+// SourceRanges reads wav data from the resource of provided size with
+// provided options, as Source does. Bytes are fetched lazily with
+// RangeFunc: only the header and the blocks that are actually decoded are
+// requested.
+func SourceRanges(fetch RangeFunc, size int64, options ...Option) pipe.SourceAllocatorFunc {
+	return Source(NewRangeReader(fetch, size), options...)
+}
+
+// RangeReader is io.ReadSeeker that fetches the resource with RangeFunc.
+// Seek doesn't fetch any data, so it's cheap to skip the parts of the
+// resource that are not needed. The last fetched block is cached.
+type RangeReader struct {
+	fetch       RangeFunc
+	size        int64
+	offset      int64
+	block       []byte
+	blockOffset int64
+}
+
+// NewRangeReader returns a new reader for the resource of provided size.
+func NewRangeReader(fetch RangeFunc, size int64) *RangeReader {
+	return &RangeReader{
+		fetch: fetch,
+		size:  size,
+	}
+}
+
+// Read implements io.Reader.
+func (r *RangeReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.offset < r.blockOffset || r.offset >= r.blockOffset+int64(len(r.block)) {
+		if err := r.fetchBlock(int64(len(p))); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.block[r.offset-r.blockOffset:])
+	r.offset += int64(n)
+	return n, nil
+}
+
+// fetchBlock requests the block that starts at current offset.
+func (r *RangeReader) fetchBlock(length int64) error {
+	if length < rangeBlockSize {
+		length = rangeBlockSize
+	}
+	if remaining := r.size - r.offset; length > remaining {
+		length = remaining
+	}
+	rc, err := r.fetch(r.offset, length)
+	if err != nil {
+		return fmt.Errorf("error fetching range %d-%d: %w", r.offset, r.offset+length-1, err)
+	}
+	defer rc.Close()
+
+	if int64(cap(r.block)) < length {
+		r.block = make([]byte, length)
+	}
+	r.block = r.block[:length]
+	n, err := io.ReadFull(rc, r.block)
+	r.block = r.block[:n]
+	r.blockOffset = r.offset
+	// short block is fine as long as it has some data.
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading range %d-%d: %w", r.offset, r.offset+length-1, err)
+	}
+	return nil
+}
+
+// Seek implements io.Seeker.
+func (r *RangeReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return r.offset, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return r.offset, errors.New("negative position")
+	}
+	r.offset = offset
+	return offset, nil
+}
@@ -0,0 +1,124 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+	"pipelined.dev/signal"
+)
+
+func TestSourceRanges(t *testing.T) {
+	data, err := ioutil.ReadFile(wavSample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// every byte is fetched once, blocks are requested in order.
+	var fetched int64
+	fetch := func(offset, length int64) (io.ReadCloser, error) {
+		if offset != fetched {
+			t.Errorf("fetched range at %d instead of %d", offset, fetched)
+		}
+		fetched += length
+		return ioutil.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
+	}
+
+	var expected, result buffer
+	transcode(t, wav.Source(bytes.NewReader(data)), wav.Sink(&expected, signal.BitDepth16))
+	transcode(t, wav.SourceRanges(fetch, int64(len(data))), wav.Sink(&result, signal.BitDepth16))
+	if !bytes.Equal(expected.data, result.data) {
+		t.Errorf("range source output doesn't match")
+	}
+	if fetched != int64(len(data)) {
+		t.Errorf("fetched %d bytes of %d", fetched, len(data))
+	}
+
+	// options are applied to the source.
+	leading := append(make([]byte, 100), data...)
+	fetch = func(offset, length int64) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(leading[offset : offset+length])), nil
+	}
+	var skipped buffer
+	transcode(t, wav.SourceRanges(fetch, int64(len(leading)), wav.SkipLeadingBytes(200)), wav.Sink(&skipped, signal.BitDepth16))
+	if !bytes.Equal(expected.data, skipped.data) {
+		t.Errorf("range source output with leading bytes doesn't match")
+	}
+}
+
+func TestRangeReaderPlayer(t *testing.T) {
+	const (
+		frames = 200000
+		start  = 150000
+		length = 1000
+	)
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 44100)),
+		chunk("data", rampData(frames)),
+	)
+	var ranges [][2]int64
+	r := wav.NewRangeReader(func(offset, length int64) (io.ReadCloser, error) {
+		ranges = append(ranges, [2]int64{offset, offset + length})
+		return ioutil.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
+	}, int64(len(data)))
+
+	p, err := wav.NewPlayer(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.Seek(start, io.SeekStart); err != nil {
+		t.Fatalf("unexpected seek error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 1, Length: length, Capacity: length}.Float64()
+	if n, err := p.Read(buf); err != nil || n != length {
+		t.Fatalf("expected %d frames got %d: %v", length, n, err)
+	}
+	for i := 0; i < length; i++ {
+		sample, scale := int16(start+i), 32767.0
+		if sample < 0 {
+			scale = 32768
+		}
+		if v := buf.Sample(i); v != float64(sample)/scale {
+			t.Fatalf("frame %d has sample %v", start+i, v)
+		}
+	}
+
+	// only the header block and the block of read frames are fetched.
+	regionStart, regionEnd := int64(44+2*start), int64(44+2*(start+length))
+	for _, rng := range ranges {
+		if rng[0] != 0 && (rng[0] >= regionEnd || rng[1] <= regionStart) {
+			t.Errorf("fetched range %d-%d outside of header and frames %d-%d", rng[0], rng[1], regionStart, regionEnd)
+		}
+	}
+	if last := ranges[len(ranges)-1]; last[0] != regionStart {
+		t.Errorf("expected frames fetched from %d got %d", regionStart, last[0])
+	}
+}
+
+func TestRangeReaderSeek(t *testing.T) {
+	data := []byte("0123456789")
+	var requests int
+	r := wav.NewRangeReader(func(offset, length int64) (io.ReadCloser, error) {
+		requests++
+		return ioutil.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
+	}, int64(len(data)))
+
+	if _, err := r.Seek(-3, io.SeekEnd); err != nil {
+		t.Fatalf("unexpected seek error: %v", err)
+	}
+	p := make([]byte, 5)
+	n, err := r.Read(p)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if string(p[:n]) != "789" {
+		t.Errorf("unexpected read: %q", p[:n])
+	}
+	if _, err := r.Read(p); err != io.EOF {
+		t.Errorf("expected EOF, got: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected single request, got: %d", requests)
+	}
+}
@@ -0,0 +1,434 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// Reader decodes frames of wav stream without pipe. It accepts the same
+// options as Source.
+type Reader struct {
+	rs      io.ReadSeeker
+	decoder *wav.Decoder
+	format  Format
+	// PCM buffer for wav decoder.
+	pcm audio.IntBuffer
+	// 8-bits wav audio is encoded as unsigned signal, unless signed8 is
+	// set.
+	signed   signal.Signed
+	unsigned signal.Unsigned
+	signed8  bool
+	// decoder of compressed formats, nil for linear PCM.
+	codec *blockDecoder
+	// decoder of IEEE float samples, nil for integer samples.
+	float *floatDecoder
+	// midSide is true if stream has mid and side channels, midSideInt if
+	// they're integer samples of reversible transform.
+	midSide    bool
+	midSideInt bool
+	analysis   *Analysis
+	events     *emitter
+	process    BufferFunc
+	resampler  *resampler
+	// interleaved buffer for planar output, nil if output is interleaved.
+	interleaved signal.Floating
+	// filter of decoded channels, nil if all channels are decoded.
+	filter *channelFilter
+	// justification of 32-bit fixed-point frames, nil if frames have
+	// stream bit depth.
+	justification *Justification
+	meter         *meter
+	// headroom in decibels and its linear gain, zero if not attenuated.
+	headroom     float64
+	headroomGain float64
+	// final buffer is filled with silence.
+	padFinalBuffer bool
+	// justification of 24-bit samples in 4-byte containers, nil if
+	// samples are packed in 3 bytes.
+	container24 *Justification
+	// frames declared by data chunk, -1 if not verified, and the number
+	// of decoded frames.
+	declaredFrames int64
+	decodedFrames  int64
+	// denormal samples are flushed to zero.
+	denormals bool
+}
+
+// NewReader returns a new reader of wav stream. The stream is validated
+// and its headers are read.
+func NewReader(rs io.ReadSeeker, options ...Option) (*Reader, error) {
+	opts := newOptions(options)
+	r, err := opts.newReader(rs, 0)
+	if err != nil {
+		return nil, err
+	}
+	if opts.planar {
+		r.interleaved = signal.Allocator{Channels: r.format.Channels}.Float64()
+	}
+	return r, nil
+}
+
+// newReader returns a new reader with buffers allocated for bufferSize
+// frames.
+func (o *options) newReader(rs io.ReadSeeker, bufferSize int) (*Reader, error) {
+	if err := o.validateHeadroom(); err != nil {
+		return nil, err
+	}
+	if o.embedded != nil {
+		embedded, err := o.embed(rs)
+		if err != nil {
+			return nil, err
+		}
+		rs = embedded
+	}
+	if o.leadingLimit > 0 {
+		skipped, err := o.skipLeading(rs)
+		if err != nil {
+			return nil, err
+		}
+		rs = skipped
+	}
+	var (
+		c container
+		// container with expanded wave list, its data chunk declares the
+		// frames of the stream.
+		expanded container
+		f        format
+		err      error
+	)
+	if o.inspect() {
+		if c, err = readContainer(rs); err != nil {
+			return nil, err
+		}
+		if err := o.checkChunkSizes(&c); err != nil {
+			return nil, err
+		}
+		o.checkContainer(c)
+		o.notifyChunks(c)
+		if o.preserved != nil {
+			if err := o.preserved.preserve(rs, c); err != nil {
+				return nil, err
+			}
+		}
+		if f, err = readFormat(rs, c); err == nil {
+			o.checkFormat(f, c)
+			if expanded, err = waveListContainer(rs, c); err != nil {
+				return nil, err
+			}
+			if err := o.checkFrames(expanded, f); err != nil {
+				return nil, err
+			}
+		}
+		if fmtIdx, dataIdx := c.index(fmtID), c.index(dataID); o.lenient && fmtIdx > dataIdx && dataIdx != -1 {
+			rs = c.canonicalOrder(rs)
+		}
+	}
+
+	r, err := o.newDecoder(rs, f, bufferSize)
+	if err != nil {
+		return nil, err
+	}
+	if h, ok := c.find(midSideID); o.midSide && ok {
+		if r.format.Channels != 2 {
+			return nil, fmt.Errorf("mid/side stream has %d channels", r.format.Channels)
+		}
+		r.midSide = true
+		if r.float == nil {
+			if r.midSideInt, err = midSideInt(rs, h); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if r.unsignedSamples() && !o.skipValidation {
+		if r.signed8, err = signed8Marked(rs, c, o.inspect()); err != nil {
+			return nil, err
+		}
+		if r.signed8 {
+			r.allocate(bufferSize)
+		}
+	}
+	if o.container24 != nil {
+		if err := o.checkContainer24(f); err != nil {
+			return nil, err
+		}
+		r.container24 = o.container24
+	}
+	if channels := o.channels; len(channels) > 0 || r.container24 != nil {
+		if len(channels) == 0 {
+			channels = allChannels(r.format.Channels)
+		}
+		if err := r.filterChannels(channels); err != nil {
+			return nil, err
+		}
+		r.allocate(bufferSize)
+	}
+	if o.resample != 0 {
+		if r.resampler, err = newResampler(r.format, o.resample, o.resampleQuality); err != nil {
+			return nil, err
+		}
+		r.format.SampleRate = o.resample
+	}
+	if o.analysis != nil {
+		o.analysis.reset(r.format.Channels)
+		r.analysis = o.analysis
+	}
+	if o.headroom != 0 {
+		r.headroom = o.headroom
+		r.headroomGain = math.Pow(10, o.headroom/20)
+	}
+	r.justification = o.justification
+	r.padFinalBuffer = o.padFinalBuffer
+	r.denormals = o.flushDenormals
+	r.declaredFrames = o.declaredFrames(expanded, f)
+	r.meter = o.newMeter(r.format.Channels)
+	r.process = o.bufferFunc
+	r.events = o.newEmitter(ComponentSource)
+	r.events.start(r.format)
+	r.events.chunks(c)
+	return r, nil
+}
+
+// newDecoder returns a new reader that decodes the format of the stream.
+func (o *options) newDecoder(rs io.ReadSeeker, f format, bufferSize int) (*Reader, error) {
+	decoder := wav.NewDecoder(rs)
+	valid := o.validDecoder(decoder)
+	if !valid || decoder.WavAudioFormat == formatExtensible || decoder.WavAudioFormat == formatFloat {
+		// go-audio decodes linear integer PCM only, float samples are
+		// decoded as integers.
+		r, err := newCodecReader(rs, bufferSize)
+		if err == nil {
+			r.rs = rs
+			return r, nil
+		}
+		if valid && err == ErrInvalidWav {
+			// integer extensible format, decoder consumed the headers.
+			if _, err := rs.Seek(0, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("error seeking stream start: %w", err)
+			}
+			decoder = wav.NewDecoder(rs)
+			valid = o.validDecoder(decoder)
+		}
+		if !valid {
+			return nil, err
+		}
+	}
+	// stream without data chunk might have wave list.
+	if decoder.FwdToPCM() != nil {
+		expanded, err := o.expandWaveList(rs)
+		if err != nil {
+			return nil, err
+		}
+		if expanded != rs {
+			return o.newDecoder(expanded, f, bufferSize)
+		}
+	}
+
+	channels := decoder.Format().NumChannels
+	if o.lenient {
+		if inferred := f.inferChannels(); inferred != channels {
+			o.warn(f.offset, "inferred %d channels from block align instead of declared %d", inferred, channels)
+			channels = inferred
+			decoder.NumChans = uint16(inferred)
+		}
+	}
+
+	r := Reader{
+		rs:      rs,
+		decoder: decoder,
+		format: Format{
+			SampleRate: signal.Frequency(decoder.SampleRate),
+			Channels:   channels,
+			BitDepth:   signal.BitDepth(decoder.BitDepth),
+		},
+		signed8: o.signed8,
+	}
+	r.pcm = audio.IntBuffer{
+		Format:         decoder.Format(),
+		SourceBitDepth: int(r.format.BitDepth),
+	}
+	r.allocate(bufferSize)
+	return &r, nil
+}
+
+// validDecoder returns true if the stream can be decoded by go-audio
+// decoder.
+func (o *options) validDecoder(d *wav.Decoder) bool {
+	if !o.skipValidation {
+		return d.IsValidFile()
+	}
+	d.ReadInfo()
+	return d.Err() == nil && d.NumChans > 0 && d.BitDepth >= 8
+}
+
+// Format returns the format of decoded stream.
+func (r *Reader) Format() Format {
+	return r.format
+}
+
+// allocate makes the buffers fit provided number of frames.
+func (r *Reader) allocate(frames int) {
+	alloc := signal.Allocator{
+		Channels: r.format.Channels,
+		Capacity: frames,
+		Length:   frames,
+	}
+	r.pcm.Data = make([]int, frames*r.format.Channels)
+	if r.unsignedSamples() {
+		r.unsigned = alloc.Uint8(r.format.BitDepth)
+	} else {
+		r.signed = alloc.Int64(r.format.BitDepth)
+	}
+}
+
+// Read decodes frames into provided buffer. It returns the number of
+// frames read and io.EOF when the stream is done. The buffer must have the
+// same number of channels as the stream.
+func (r *Reader) Read(dst signal.Floating) (int, error) {
+	if dst.Channels() != r.format.Channels {
+		return 0, fmt.Errorf("buffer has %d channels instead of %d", dst.Channels(), r.format.Channels)
+	}
+	out := dst
+	if r.interleaved != nil {
+		if r.interleaved.Length() != dst.Length() {
+			r.interleaved = signal.Allocator{Channels: r.format.Channels, Length: dst.Length(), Capacity: dst.Length()}.Float64()
+		}
+		out = r.interleaved
+	}
+	n, err := r.readFrames(out)
+	if err == nil && r.padFinalBuffer {
+		n, err = r.fill(out, n)
+	}
+	if err == io.EOF {
+		if frameErr := r.checkFrameCount(); frameErr != nil {
+			err = frameErr
+		}
+	}
+	if err != nil {
+		if err == io.EOF {
+			r.meter.flush()
+			r.events.end()
+		}
+		return 0, err
+	}
+	r.attenuate(out, n)
+	r.flushDenormals(out, n)
+	if r.analysis != nil {
+		r.analysis.update(out, n)
+	}
+	r.process.process(out, n)
+	r.meter.update(out, n)
+	var padding int
+	if r.padFinalBuffer {
+		padding = padBuffer(out, n)
+	}
+	if r.interleaved != nil {
+		deinterleave(out, dst, n+padding)
+	}
+	r.events.paddedBuffer(n, padding)
+	return n + padding, nil
+}
+
+// readFrames reads the frames of output sample rate into provided buffer.
+func (r *Reader) readFrames(dst signal.Floating) (int, error) {
+	if r.resampler != nil {
+		return r.resampler.read(dst, r.decodeFrames)
+	}
+	return r.decodeFrames(dst)
+}
+
+// decodeFrames decodes the frames of the stream into provided buffer.
+func (r *Reader) decodeFrames(dst signal.Floating) (int, error) {
+	r.sizePCM(dst.Length())
+	n, err := r.decode(dst)
+	if err != nil {
+		return 0, err
+	}
+	if r.midSide && !r.midSideInt {
+		decodeMidSide(dst, n)
+	}
+	r.decodedFrames += int64(n)
+	return n, nil
+}
+
+// sizePCM makes PCM buffer fit provided number of frames.
+func (r *Reader) sizePCM(frames int) {
+	if length := frames * r.format.Channels; length > cap(r.pcm.Data) {
+		r.allocate(frames)
+	} else {
+		r.pcm.Data = r.pcm.Data[:length]
+	}
+}
+
+// decode reads the frames with the decoder of stream format.
+func (r *Reader) decode(dst signal.Floating) (int, error) {
+	if r.float != nil {
+		return r.float.read(dst)
+	}
+	if r.codec != nil {
+		return r.readCodec(dst)
+	}
+	if r.unsignedSamples() {
+		return r.readUnsigned(dst)
+	}
+	return r.readSigned(dst)
+}
+
+func (r *Reader) readCodec(floating signal.Floating) (int, error) {
+	read, err := r.codec.read(r.signed, floating.Length())
+	if err != nil {
+		return 0, err
+	}
+	if r.midSideInt {
+		for i := 0; i+1 < read*2; i += 2 {
+			left, right := decodeMidSideInt(r.signed.Sample(i), r.signed.Sample(i+1), r.format.BitDepth)
+			r.signed.SetSample(i, left)
+			r.signed.SetSample(i+1, right)
+		}
+	}
+	return signal.SignedAsFloating(r.signed.Slice(0, read), floating), nil
+}
+
+func (r *Reader) readSigned(floating signal.Floating) (int, error) {
+	// read new buffer, io.EOF is never returned here.
+	read, err := r.readPCM(&r.pcm)
+	if err != nil {
+		return 0, fmt.Errorf("error reading PCM buffer: %w", err)
+	}
+	if read == 0 {
+		return 0, io.EOF
+	}
+
+	if r.signed8 && r.format.BitDepth == signal.BitDepth8 {
+		signed8Samples(r.pcm.Data[:read])
+	}
+	if r.midSideInt {
+		decodeMidSidePCM(r.pcm.Data[:read], r.format.BitDepth, 0)
+	}
+	read = signal.WriteInt(r.pcm.Data[:read], r.signed)
+	return signal.SignedAsFloating(r.signed.Slice(0, read), floating), nil
+}
+
+func (r *Reader) readUnsigned(floating signal.Floating) (int, error) {
+	// read new buffer, io.EOF is never returned here.
+	read, err := r.readPCM(&r.pcm)
+	if err != nil {
+		return 0, fmt.Errorf("error reading PCM buffer: %w", err)
+	}
+	if read == 0 {
+		return 0, io.EOF
+	}
+
+	if r.midSideInt {
+		decodeMidSidePCM(r.pcm.Data[:read], r.format.BitDepth, 1<<7)
+	}
+	for i := 0; i < read; i++ {
+		r.unsigned.SetSample(i, uint64(r.pcm.Data[i]))
+	}
+	return unsignedAsFloating(r.unsigned.Slice(0, signal.ChannelLength(read, r.format.Channels)), floating), nil
+}
@@ -0,0 +1,176 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestReader(t *testing.T) {
+	samples := []float64{0, 0.5, -0.5, 1, 0.25, -0.25, -1, 0}
+	var in buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&in, signal.BitDepth16))
+
+	r, err := wav.NewReader(bytes.NewReader(in.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedFormat := wav.Format{SampleRate: 44100, Channels: 2, BitDepth: signal.BitDepth16}
+	if format := r.Format(); format != expectedFormat {
+		t.Errorf("expected format %+v got %+v", expectedFormat, format)
+	}
+
+	// buffer isn't aligned to the number of frames.
+	buf := signal.Allocator{Channels: 2, Length: 3, Capacity: 3}.Float64()
+	var result []float64
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		for i := 0; i < n*2; i++ {
+			result = append(result, buf.Sample(i))
+		}
+	}
+	if len(result) != len(samples) {
+		t.Fatalf("expected %d samples got %d", len(samples), len(result))
+	}
+	for i := range samples {
+		if d := samples[i] - result[i]; d > 1.0/32767 || d < -1.0/32767 {
+			t.Errorf("sample %d: expected %v got %v", i, samples[i], result[i])
+		}
+	}
+
+	mono := signal.Allocator{Channels: 1, Length: 3, Capacity: 3}.Float64()
+	if _, err := r.Read(mono); err == nil {
+		t.Errorf("expected error for channels mismatch")
+	}
+}
+
+func TestReaderUnsigned(t *testing.T) {
+	var in buffer
+	transcode(t, floatSource(8000, 1, []float64{0, 0.5, -0.5}), wav.Sink(&in, signal.BitDepth8))
+	r, err := wav.NewReader(bytes.NewReader(in.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 1, Length: 8, Capacity: 8}.Float64()
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 frames got %d", n)
+	}
+	for i, expected := range []float64{0, 0.5, -0.5} {
+		if d := expected - buf.Sample(i); d > 1.0/127 || d < -1.0/127 {
+			t.Errorf("sample %d: expected %v got %v", i, expected, buf.Sample(i))
+		}
+	}
+}
+
+func TestReaderInvalid(t *testing.T) {
+	f, err := os.Open(notWav)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	if _, err := wav.NewReader(f); err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %v", err)
+	}
+}
+
+func TestReaderPlanar(t *testing.T) {
+	samples := []float64{0, 0.5, -0.5, 1, 0.25, -0.25, -1, 0, 0.75, -0.75}
+	var in buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&in, signal.BitDepth16))
+
+	r, err := wav.NewReader(bytes.NewReader(in.data), wav.Planar())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the last buffer is partial.
+	buf := signal.Allocator{Channels: 2, Length: 3, Capacity: 3}.Float64()
+	var left, right []float64
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			left = append(left, buf.Sample(i))
+			right = append(right, buf.Sample(buf.Length()+i))
+		}
+	}
+	if len(left) != 5 || len(right) != 5 {
+		t.Fatalf("expected 5 frames got %d and %d", len(left), len(right))
+	}
+	for i := 0; i < 5; i++ {
+		if d := samples[2*i] - left[i]; d > 1.0/32767 || d < -1.0/32767 {
+			t.Errorf("left %d: expected %v got %v", i, samples[2*i], left[i])
+		}
+		if d := samples[2*i+1] - right[i]; d > 1.0/32767 || d < -1.0/32767 {
+			t.Errorf("right %d: expected %v got %v", i, samples[2*i+1], right[i])
+		}
+	}
+}
+
+func TestReaderManyChannels(t *testing.T) {
+	const channels, frames = 16, 5
+	pcm := make([]byte, channels*frames*2)
+	for i := 0; i < channels*frames; i++ {
+		// every sample encodes its frame and channel.
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16((i/channels+1)*1000+i%channels))
+	}
+	data := riff(chunk("fmt ", fmtPayload(channels, 16, 2*channels, 48000)), chunk("data", pcm))
+
+	r, err := wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := r.Format().Channels; c != channels {
+		t.Fatalf("expected %d channels got %d", channels, c)
+	}
+	buf := signal.Allocator{Channels: channels, Length: 2, Capacity: 2}.Float64()
+	read := 0
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			for c := 0; c < channels; c++ {
+				expected := (read+i+1)*1000 + c
+				if v := int(math.Round(buf.Sample(i*channels+c) * 32767)); v != expected {
+					t.Errorf("frame %d channel %d: expected %d got %d", read+i, c, expected, v)
+				}
+			}
+		}
+		read += n
+	}
+	if read != frames {
+		t.Errorf("expected %d frames got %d", frames, read)
+	}
+
+	var out buffer
+	transcode(t, wav.Source(bytes.NewReader(data)), wav.Sink(&out, signal.BitDepth16))
+	if !bytes.Equal(pcm, out.data[44:]) {
+		t.Errorf("samples are changed by transcoding")
+	}
+}
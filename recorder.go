@@ -0,0 +1,86 @@
+package wav
+
+import (
+	"io"
+	"strings"
+)
+
+// recorderChunks are the identifiers of device-specific chunks and the
+// manufacturers that write them.
+var recorderChunks = map[[4]byte]string{
+	{'o', 'l', 'y', 'm'}: "Olympus",
+}
+
+// recorderPrefixes are the prefixes of bext originator that identify the
+// manufacturers of recorders.
+var recorderPrefixes = []struct {
+	prefix       string
+	manufacturer string
+}{
+	{"zoom", "Zoom"},
+	{"tascam", "Tascam"},
+	{"olympus", "Olympus"},
+	{"sony", "Sony"},
+	{"sound devices", "Sound Devices"},
+	{"roland", "Roland"},
+	{"marantz", "Marantz"},
+}
+
+// Recorder describes the device that recorded the stream.
+type Recorder struct {
+	// Manufacturer is recognized from the originator of bext chunk or
+	// the device-specific chunks, empty if unknown.
+	Manufacturer string
+	// Model is the originator of bext chunk, where recorders store their
+	// model, e.g. "ZOOM H5".
+	Model string
+	// Chunks are the payloads of device-specific chunks, e.g. "olym" of
+	// Olympus recorders. They are proprietary, so payloads aren't decoded.
+	Chunks map[[4]byte][]byte
+}
+
+// ReadRecorder returns the recorder of the stream. Zoom, Tascam and
+// similar recorders store settings in bext and iXML chunks, which are
+// read with ReadBext and ReadChunk. Nil is returned if neither bext
+// originator nor device-specific chunks are found. The stream is rewinded
+// to the start afterwards.
+func ReadRecorder(rs io.ReadSeeker) (*Recorder, error) {
+	c, err := readContainer(rs)
+	if err != nil {
+		return nil, err
+	}
+	var r Recorder
+	for _, h := range c.chunks {
+		manufacturer, ok := recorderChunks[h.ID]
+		if !ok {
+			continue
+		}
+		payload, err := readPayload(rs, h)
+		if err != nil {
+			return nil, err
+		}
+		if r.Chunks == nil {
+			r.Chunks = make(map[[4]byte][]byte)
+		}
+		r.Chunks[h.ID] = payload
+		r.Manufacturer = manufacturer
+	}
+	b, err := ReadBext(rs)
+	if err != nil {
+		return nil, err
+	}
+	if b != nil {
+		r.Model = strings.TrimSpace(b.Originator)
+		model := strings.ToLower(r.Model)
+		for _, p := range recorderPrefixes {
+			if strings.HasPrefix(model, p.prefix) {
+				r.Manufacturer = p.manufacturer
+				break
+			}
+		}
+	}
+	if r.Model == "" && r.Chunks == nil {
+		return nil, nil
+	}
+	return &r, nil
+}
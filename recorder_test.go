@@ -0,0 +1,57 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestReadRecorder(t *testing.T) {
+	pcm := make([]byte, 4)
+	bext := func(originator string) []byte {
+		b := make([]byte, 602)
+		copy(b[256:288], originator)
+		return b
+	}
+	olym := []byte("LS-P4 settings")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *wav.Recorder
+	}{
+		{
+			name: "none",
+			data: riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("data", pcm)),
+		},
+		{
+			name:     "zoom",
+			data:     riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("bext", bext("ZOOM H5")), chunk("data", pcm)),
+			expected: &wav.Recorder{Manufacturer: "Zoom", Model: "ZOOM H5"},
+		},
+		{
+			name:     "unknown originator",
+			data:     riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("bext", bext("Studio A")), chunk("data", pcm)),
+			expected: &wav.Recorder{Model: "Studio A"},
+		},
+		{
+			name: "olympus",
+			data: riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("olym", olym), chunk("data", pcm)),
+			expected: &wav.Recorder{
+				Manufacturer: "Olympus",
+				Chunks:       map[[4]byte][]byte{{'o', 'l', 'y', 'm'}: olym},
+			},
+		},
+	}
+	for _, test := range tests {
+		result, err := wav.ReadRecorder(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.expected, result) {
+			t.Errorf("%s: expected %+v got %+v", test.name, test.expected, result)
+		}
+	}
+}
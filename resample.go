@@ -0,0 +1,247 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"pipelined.dev/signal"
+)
+
+// resampleBlockSize is the number of frames decoded at once by resampler.
+const resampleBlockSize = 1024
+
+// WithResample makes Source and Reader convert decoded frames to provided
+// sample rate. Sink converts the frames before encoding and declares
+// provided rate in the header. The rate must be a whole number of hertz,
+// the ratio of rates can be any. Frames are interpolated with linear
+// interpolation unless other quality is set with WithResampleQuality.
+// The stream of N frames is converted into ceil(N*rate/sourceRate)
+// frames, so the duration is preserved.
+func WithResample(rate signal.Frequency) Option {
+	return func(o *options) {
+		o.resample = rate
+	}
+}
+
+// resampler converts the rate of decoded frames with the kernel of
+// resample quality. Rates are integers, so the positions are exact.
+type resampler struct {
+	channels int
+	in, out  int64
+	kernel   kernel
+	// number of decoded frames on each side of output position that the
+	// kernel needs.
+	radius int64
+	// weights of decoded frames of the current output frame.
+	weights []float64
+	// decoded interleaved frames, window[0] is the frame with index
+	// start.
+	window []float64
+	start  int64
+	block  signal.Floating
+	eof    bool
+	// index of the next output frame.
+	next int64
+	// frames written by Sink and the buffer of resampled frames.
+	written int64
+	output  signal.Floating
+}
+
+// newResampler returns resampler from the format rate to provided rate.
+func newResampler(f Format, rate signal.Frequency, q ResampleQuality) (*resampler, error) {
+	if rate < 1 || rate != signal.Frequency(math.Trunc(float64(rate))) {
+		return nil, fmt.Errorf("invalid resample rate: %v", rate)
+	}
+	k, err := q.kernel(float64(rate) / float64(f.SampleRate))
+	if err != nil {
+		return nil, err
+	}
+	radius := int64(math.Ceil(k.radius))
+	return &resampler{
+		channels: f.Channels,
+		in:       int64(f.SampleRate),
+		out:      int64(rate),
+		kernel:   k,
+		radius:   radius,
+		weights:  make([]float64, 2*radius),
+		block:    signal.Allocator{Channels: f.Channels, Length: resampleBlockSize, Capacity: resampleBlockSize}.Float64(),
+	}, nil
+}
+
+// resampledFrames returns the number of frames after resampling.
+func (s *resampler) resampledFrames(frames int64) int64 {
+	return (frames*s.out + s.in - 1) / s.in
+}
+
+// frames returns the number of decoded frames in the window.
+func (s *resampler) frames() int64 {
+	return int64(len(s.window) / s.channels)
+}
+
+// read fills the buffer with resampled frames. The frames are decoded
+// with provided function.
+func (s *resampler) read(dst signal.Floating, decode func(signal.Floating) (int, error)) (int, error) {
+	n := 0
+	for n < dst.Length() {
+		i := s.next * s.in / s.out
+		// interpolation needs frames up to i+radius.
+		for !s.eof && i+s.radius >= s.start+s.frames() {
+			if err := s.fill(decode, i); err != nil {
+				return 0, err
+			}
+		}
+		if i >= s.start+s.frames() {
+			break
+		}
+		s.interpolate(dst, n, i)
+		n++
+		s.next++
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// interpolate sets the frame n of the buffer to the next output frame,
+// which lies between decoded frames i and i+1. The frames are weighted
+// from i-radius+1 to i+radius, the first and the last decoded frames are
+// held beyond the edges.
+func (s *resampler) interpolate(dst signal.Floating, n int, i int64) {
+	fraction := float64(s.next*s.in%s.out) / float64(s.out)
+	var sum float64
+	for k := range s.weights {
+		w := s.kernel.weight(float64(int64(k)-s.radius+1) - fraction)
+		s.weights[k] = w
+		sum += w
+	}
+	last := s.start + s.frames() - 1
+	for c := 0; c < s.channels; c++ {
+		var v float64
+		for k, w := range s.weights {
+			j := i - s.radius + 1 + int64(k)
+			if j < s.start {
+				j = s.start
+			} else if j > last {
+				j = last
+			}
+			v += w * s.window[(j-s.start)*int64(s.channels)+int64(c)]
+		}
+		// weights are normalized, so constant signal is kept exactly.
+		dst.SetSample(n*s.channels+c, v/sum)
+	}
+}
+
+// write appends provided buffer to the window and returns the output
+// frames that can be interpolated from it. The returned buffer is reused
+// by the next call.
+func (s *resampler) write(src signal.Floating) signal.Floating {
+	for j := 0; j < src.Len(); j++ {
+		s.window = append(s.window, src.Sample(j))
+	}
+	s.written += int64(src.Length())
+	// interpolation needs frames up to i+radius.
+	return s.emit(func(i int64) bool { return i+s.radius < s.start+s.frames() })
+}
+
+// flush returns the remaining output frames, the last frame is held.
+func (s *resampler) flush() signal.Floating {
+	total := s.resampledFrames(s.written)
+	return s.emit(func(i int64) bool { return s.next < total && i < s.start+s.frames() })
+}
+
+// emit interpolates output frames while their decoded frames are ready
+// and drops the frames that are not needed anymore.
+func (s *resampler) emit(ready func(i int64) bool) signal.Floating {
+	if s.output == nil {
+		s.grow()
+	}
+	n := 0
+	for ; ready(s.next * s.in / s.out); n++ {
+		if n == s.output.Length() {
+			s.grow()
+		}
+		s.interpolate(s.output, n, s.next*s.in/s.out)
+		s.next++
+	}
+	s.drop(s.next * s.in / s.out)
+	return s.output.Slice(0, n)
+}
+
+// drop removes the frames that interpolation at index i doesn't need.
+func (s *resampler) drop(i int64) {
+	if drop := i - s.radius + 1 - s.start; drop > 0 {
+		if frames := s.frames(); drop > frames {
+			drop = frames
+		}
+		s.window = s.window[:copy(s.window, s.window[drop*int64(s.channels):])]
+		s.start += drop
+	}
+}
+
+// grow allocates output buffer or doubles its length.
+func (s *resampler) grow() {
+	if s.output == nil {
+		s.output = signal.Allocator{Channels: s.channels, Length: resampleBlockSize, Capacity: resampleBlockSize}.Float64()
+		return
+	}
+	length := 2 * s.output.Length()
+	grown := signal.Allocator{Channels: s.channels, Length: length, Capacity: length}.Float64()
+	for j := 0; j < s.output.Len(); j++ {
+		grown.SetSample(j, s.output.Sample(j))
+	}
+	s.output = grown
+}
+
+// fill drops the frames that interpolation at index i doesn't need and
+// decodes the next block.
+func (s *resampler) fill(decode func(signal.Floating) (int, error), i int64) error {
+	s.drop(i)
+	read, err := decode(s.block)
+	if err == io.EOF {
+		s.eof = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for j := 0; j < read*s.channels; j++ {
+		s.window = append(s.window, s.block.Sample(j))
+	}
+	return nil
+}
+
+// Frames returns the number of frames that reader produces as declared by
+// the stream, accounting for resampling. The headers are read again, so
+// it can be called at any time.
+func (r *Reader) Frames() (int64, error) {
+	position, err := r.rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, fmt.Errorf("error seeking stream: %w", err)
+	}
+	defer r.rs.Seek(position, io.SeekStart)
+	c, err := readContainer(r.rs)
+	if err != nil {
+		return 0, err
+	}
+	f, err := readFormat(r.rs, c)
+	if err != nil {
+		return 0, err
+	}
+	frames := c.info(f).Frames
+	if r.resampler != nil {
+		frames = r.resampler.resampledFrames(frames)
+	}
+	return frames, nil
+}
+
+// Duration returns the duration of the frames that reader produces.
+func (r *Reader) Duration() (time.Duration, error) {
+	frames, err := r.Frames()
+	if err != nil {
+		return 0, err
+	}
+	return framesDuration(frames, r.format.SampleRate), nil
+}
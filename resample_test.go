@@ -0,0 +1,223 @@
+package wav_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestResample(t *testing.T) {
+	const frames = 4410
+	samples := make([]float64, 2*frames)
+	for i := 0; i < frames; i++ {
+		// ramp in the first channel and constant in the second.
+		samples[2*i] = float64(i) / frames
+		samples[2*i+1] = 0.25
+	}
+	var in buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&in, signal.BitDepth32))
+
+	r, err := wav.NewReader(bytes.NewReader(in.data), wav.WithResample(48000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rate := r.Format().SampleRate; rate != 48000 {
+		t.Errorf("expected rate 48000 got %v", rate)
+	}
+	expected, err := r.Frames()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected != 4800 {
+		t.Errorf("expected 4800 frames got %d", expected)
+	}
+	if d, _ := r.Duration(); d != 100*time.Millisecond {
+		t.Errorf("expected 100ms got %v", d)
+	}
+
+	buf := signal.Allocator{Channels: 2, Length: 333, Capacity: 333}.Float64()
+	produced := 0
+	for {
+		n, err := r.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			k := produced + i
+			position := math.Min(float64(k)*44100/48000, frames-1)
+			if v := buf.Sample(2 * i); math.Abs(v-position/frames) > 1e-6 {
+				t.Fatalf("frame %d: expected %v got %v", k, position/frames, v)
+			}
+			if v := buf.Sample(2*i + 1); math.Abs(v-0.25) > 1e-6 {
+				t.Fatalf("frame %d: expected 0.25 got %v", k, v)
+			}
+		}
+		produced += n
+	}
+	if int64(produced) != expected {
+		t.Errorf("reported %d frames, produced %d", expected, produced)
+	}
+
+	// frames reported after reading.
+	if frames, err := r.Frames(); err != nil || frames != expected {
+		t.Errorf("expected %d frames got %d %v", expected, frames, err)
+	}
+
+	var out buffer
+	transcode(t, wav.Source(bytes.NewReader(in.data), wav.WithResample(48000)), wav.Sink(&out, signal.BitDepth16))
+	info, err := wav.Probe(bytes.NewReader(out.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.SampleRate != 48000 || info.Frames != expected {
+		t.Errorf("expected %d frames at 48000 got %d at %v", expected, info.Frames, info.SampleRate)
+	}
+
+	if _, err := wav.NewReader(bytes.NewReader(in.data), wav.WithResample(44100.5)); err == nil {
+		t.Errorf("expected error for fractional rate")
+	}
+}
+
+func TestSinkResample(t *testing.T) {
+	const frames = 9600
+	samples := make([]float64, 2*frames)
+	for i := 0; i < frames; i++ {
+		// 100Hz sine in the first channel and constant in the second.
+		samples[2*i] = 0.5 * math.Sin(2*math.Pi*100*float64(i)/96000)
+		samples[2*i+1] = 0.25
+	}
+	var down buffer
+	transcode(t, floatSource(96000, 2, samples), wav.Sink(&down, signal.BitDepth32, wav.WithResample(44100)))
+	info, err := wav.Probe(bytes.NewReader(down.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// ceil(9600*44100/96000) frames.
+	if info.SampleRate != 44100 || info.Frames != 4410 {
+		t.Errorf("expected 4410 frames at 44100 got %d at %v", info.Frames, info.SampleRate)
+	}
+	if byteRate := binary.LittleEndian.Uint32(down.data[28:]); byteRate != 44100*8 {
+		t.Errorf("expected byte rate %d got %d", 44100*8, byteRate)
+	}
+
+	var up buffer
+	transcode(t, wav.Source(bytes.NewReader(down.data)), wav.Sink(&up, signal.BitDepth32, wav.WithResample(96000)))
+	r, err := wav.NewReader(bytes.NewReader(up.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, err := r.Frames(); err != nil || n != frames {
+		t.Fatalf("expected %d frames got %d: %v", frames, n, err)
+	}
+	result := readAll(t, r)
+	for i := 0; i < frames; i++ {
+		// linear interpolation of 100Hz is accurate to 1e-4, except the
+		// frames after the last frame of 44100 that hold it.
+		if v := result[2*i]; i*44100 <= 4409*96000 && math.Abs(v-samples[2*i]) > 1e-4 {
+			t.Fatalf("frame %d: expected %v got %v", i, samples[2*i], v)
+		}
+		if v := result[2*i+1]; math.Abs(v-0.25) > 1e-6 {
+			t.Fatalf("frame %d: expected 0.25 got %v", i, v)
+		}
+	}
+
+	var stream bytes.Buffer
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Source: floatSource(96000, 2, samples),
+		Sink:   wav.SinkStream(&stream, signal.BitDepth16, wav.WithResample(44100)),
+	})
+	if err == nil {
+		err = pipe.Wait(p.Start(context.Background()))
+	}
+	if err == nil {
+		t.Errorf("expected error for resampled stream")
+	}
+}
+
+// sweep returns frames of mono sine sweep from f0 to f1 hertz.
+func sweep(sampleRate, f0, f1 float64, frames int) []float64 {
+	samples := make([]float64, frames)
+	duration := float64(frames) / sampleRate
+	for i := range samples {
+		t := float64(i) / sampleRate
+		// phase of linear chirp.
+		phase := 2 * math.Pi * (f0*t + (f1-f0)*t*t/(2*duration))
+		samples[i] = 0.5 * math.Sin(phase)
+	}
+	return samples
+}
+
+// rms returns root mean square of the samples.
+func rms(samples []float64) float64 {
+	var sum float64
+	for _, v := range samples {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+func TestResampleQuality(t *testing.T) {
+	const frames = 9600
+	// the sweep is above Nyquist frequency of 44100, so ideal
+	// downsampling produces silence and anything else is aliasing.
+	samples := sweep(96000, 26000, 40000, frames)
+	aliasing := make(map[wav.ResampleQuality]float64)
+	for _, q := range []wav.ResampleQuality{wav.ResampleLinear, wav.ResampleCubic, wav.ResampleSincLow, wav.ResampleSincHigh} {
+		var out buffer
+		transcode(t, floatSource(96000, 1, samples), wav.Sink(&out, signal.BitDepth32, wav.WithResample(44100), wav.WithResampleQuality(q)))
+		r, err := wav.NewReader(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("quality %d: unexpected error: %v", q, err)
+		}
+		result := readAll(t, r)
+		if len(result) != 4410 {
+			t.Fatalf("quality %d: expected 4410 frames got %d", q, len(result))
+		}
+		// edges are excluded, the sweep starts and ends abruptly.
+		aliasing[q] = rms(result[200 : len(result)-200])
+	}
+	// input has RMS of 0.35.
+	if aliasing[wav.ResampleLinear] < 0.1 {
+		t.Errorf("expected aliasing of linear interpolation got %v", aliasing[wav.ResampleLinear])
+	}
+	if high := aliasing[wav.ResampleSincHigh]; high > 1e-3 || high > aliasing[wav.ResampleSincLow] {
+		t.Errorf("unexpected aliasing of high quality %v, low quality has %v", high, aliasing[wav.ResampleSincLow])
+	}
+
+	// 1kHz is kept by every quality of Source.
+	const tone = 1000
+	var in buffer
+	transcode(t, wav.SourceSine(tone, 96000, 1, frames), wav.Sink(&in, signal.BitDepth32))
+	for _, q := range []wav.ResampleQuality{wav.ResampleLinear, wav.ResampleCubic, wav.ResampleSincLow, wav.ResampleSincHigh} {
+		r, err := wav.NewReader(bytes.NewReader(in.data), wav.WithResample(44100), wav.WithResampleQuality(q))
+		if err != nil {
+			t.Fatalf("quality %d: unexpected error: %v", q, err)
+		}
+		result := readAll(t, r)
+		if len(result) != 4410 {
+			t.Fatalf("quality %d: expected 4410 frames got %d", q, len(result))
+		}
+		for i := 200; i < len(result)-200; i++ {
+			expected := math.Sin(2 * math.Pi * tone * float64(i) / 44100)
+			if math.Abs(result[i]-expected) > 1e-3 {
+				t.Fatalf("quality %d: frame %d: expected %v got %v", q, i, expected, result[i])
+			}
+		}
+	}
+
+	if _, err := wav.NewReader(bytes.NewReader(in.data), wav.WithResample(44100), wav.WithResampleQuality(42)); err == nil {
+		t.Errorf("expected error for invalid quality")
+	}
+}
@@ -0,0 +1,109 @@
+package wav
+
+import (
+	"fmt"
+	"math"
+)
+
+// ResampleQuality defines the interpolation kernel of WithResample. Higher
+// qualities weight more decoded frames per output frame, so they cost
+// proportionally more CPU.
+type ResampleQuality int
+
+const (
+	// ResampleLinear interpolates between two neighbouring frames. It's
+	// the fastest, but it doesn't filter the frequencies above the
+	// Nyquist frequency of the lower rate, so downsampling aliases and
+	// high frequencies are attenuated.
+	ResampleLinear ResampleQuality = iota
+	// ResampleCubic interpolates four frames with Catmull-Rom spline. It
+	// costs about twice as much as linear and keeps high frequencies
+	// better, but it aliases the same way.
+	ResampleCubic
+	// ResampleSincLow filters with Blackman-windowed sinc of 8 zero
+	// crossings on each side. Frequencies above the lower Nyquist
+	// frequency are attenuated, so downsampling doesn't alias, except in
+	// a wide transition band. It's an order of magnitude slower than
+	// linear.
+	ResampleSincLow
+	// ResampleSincHigh filters with Blackman-windowed sinc of 32 zero
+	// crossings on each side. The transition band is four times narrower
+	// than of ResampleSincLow at four times the cost. It suits mastering
+	// and offline conversion.
+	ResampleSincHigh
+)
+
+// WithResampleQuality makes Source, Reader and Sink resample with provided
+// quality. Default is ResampleLinear.
+func WithResampleQuality(q ResampleQuality) Option {
+	return func(o *options) {
+		o.resampleQuality = q
+	}
+}
+
+// kernel weights decoded frames by their distance from output position.
+type kernel struct {
+	// radius is the distance in decoded frames where weights end.
+	radius float64
+	weight func(distance float64) float64
+}
+
+// kernel returns the kernel of the quality for provided ratio of output
+// and input rates.
+func (q ResampleQuality) kernel(ratio float64) (kernel, error) {
+	switch q {
+	case ResampleLinear:
+		return kernel{radius: 1, weight: linearWeight}, nil
+	case ResampleCubic:
+		return kernel{radius: 2, weight: cubicWeight}, nil
+	case ResampleSincLow:
+		return sincKernel(8, ratio), nil
+	case ResampleSincHigh:
+		return sincKernel(32, ratio), nil
+	}
+	return kernel{}, fmt.Errorf("invalid resample quality: %d", q)
+}
+
+// linearWeight is the triangular kernel of linear interpolation.
+func linearWeight(d float64) float64 {
+	if d = math.Abs(d); d < 1 {
+		return 1 - d
+	}
+	return 0
+}
+
+// cubicWeight is the kernel of Catmull-Rom spline.
+func cubicWeight(d float64) float64 {
+	d = math.Abs(d)
+	switch {
+	case d < 1:
+		return 1.5*d*d*d - 2.5*d*d + 1
+	case d < 2:
+		return -0.5*d*d*d + 2.5*d*d - 4*d + 2
+	}
+	return 0
+}
+
+// sincKernel returns Blackman-windowed sinc kernel with provided number
+// of zero crossings on each side. When downsampling, the cutoff is
+// lowered to the output Nyquist frequency and the kernel is stretched
+// accordingly.
+func sincKernel(zeroCrossings int, ratio float64) kernel {
+	cutoff := math.Min(1, ratio)
+	radius := float64(zeroCrossings) / cutoff
+	return kernel{
+		radius: radius,
+		weight: func(d float64) float64 {
+			if math.Abs(d) >= radius {
+				return 0
+			}
+			x := math.Pi * cutoff * d
+			sinc := 1.0
+			if x != 0 {
+				sinc = math.Sin(x) / x
+			}
+			w := math.Pi * d / radius
+			return sinc * (0.42 + 0.5*math.Cos(w) + 0.08*math.Cos(2*w))
+		},
+	}
+}
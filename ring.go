@@ -0,0 +1,123 @@
+package wav
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// SinkRing keeps the last frames of provided duration in memory and writes
+// them to WriteSeeker as Sink does when it's flushed. The oldest frames
+// are discarded as new ones arrive, so the output is the tail of the
+// stream, e.g. for instant replay of live capture. Nothing is written
+// before the flush. If Sink writes bext chunk, its time reference is
+// moved forward by the number of discarded frames.
+func SinkRing(ws io.WriteSeeker, bitDepth signal.BitDepth, retain time.Duration, options ...Option) pipe.SinkAllocatorFunc {
+	opts := newOptions(options)
+	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
+		if err := validateBufferSize(bufferSize); err != nil {
+			return pipe.Sink{}, err
+		}
+		frames := props.SampleRate.Events(retain)
+		if frames <= 0 {
+			return pipe.Sink{}, fmt.Errorf("invalid ring duration %v", retain)
+		}
+		r := ring{
+			samples:  make([]float64, frames*props.Channels),
+			channels: props.Channels,
+		}
+		return pipe.Sink{
+			SinkFunc: func(floats signal.Floating) error {
+				r.write(floats)
+				return nil
+			},
+			FlushFunc: func(ctx context.Context) error {
+				ringOptions := options
+				if opts.bext != nil {
+					b := *opts.bext
+					b.TimeReference += uint64(r.discarded())
+					ringOptions = append(append([]Option(nil), options...), WithBext(&b))
+				}
+				sink, err := Sink(ws, bitDepth, ringOptions...)(mctx, bufferSize, props)
+				if err != nil {
+					return err
+				}
+				if err := r.flush(sink.SinkFunc, bufferSize); err != nil {
+					return err
+				}
+				return sink.FlushFunc(ctx)
+			},
+		}, nil
+	}
+}
+
+// ring is the circular buffer of interleaved samples.
+type ring struct {
+	samples  []float64
+	channels int
+	// total number of frames written to the ring.
+	written int64
+}
+
+// size returns the capacity of the ring in frames.
+func (r *ring) size() int {
+	return len(r.samples) / r.channels
+}
+
+// discarded returns the number of frames that were overwritten.
+func (r *ring) discarded() int64 {
+	if d := r.written - int64(r.size()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// write stores the frames of provided buffer over the oldest ones.
+func (r *ring) write(floats signal.Floating) {
+	size := int64(r.size())
+	for i := 0; i < floats.Length(); i++ {
+		pos := int(r.written%size) * r.channels
+		for c := 0; c < r.channels; c++ {
+			r.samples[pos+c] = floats.Sample(i*r.channels + c)
+		}
+		r.written++
+	}
+}
+
+// flush passes the retained frames to the sink function in order, in
+// buffers of provided size.
+func (r *ring) flush(fn pipe.SinkFunc, bufferSize int) error {
+	size := int64(r.size())
+	start, retained := int64(0), r.written
+	if retained > size {
+		start, retained = r.written%size, size
+	}
+	buf := signal.Allocator{
+		Channels: r.channels,
+		Length:   bufferSize,
+		Capacity: bufferSize,
+	}.Float64()
+	for retained > 0 {
+		// frames up to the end of the ring or buffer.
+		frames := int64(bufferSize)
+		if frames > retained {
+			frames = retained
+		}
+		if end := size - start; frames > end {
+			frames = end
+		}
+		out := buf.Slice(0, int(frames))
+		signal.WriteFloat64(r.samples[start*int64(r.channels):(start+frames)*int64(r.channels)], out)
+		if err := fn(out); err != nil {
+			return err
+		}
+		start = (start + frames) % size
+		retained -= frames
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestSinkRing(t *testing.T) {
+	samples := make([]float64, 25)
+	for i := range samples {
+		samples[i] = float64(i) / 100
+	}
+	tests := []struct {
+		name     string
+		samples  []float64
+		expected []int16
+		timeRef  uint64
+	}{
+		{name: "wrapped", samples: samples, expected: ramp(15, 25), timeRef: 115},
+		{name: "short", samples: samples[:4], expected: ramp(0, 4), timeRef: 100},
+	}
+	for _, test := range tests {
+		var out buffer
+		bext := wav.Bext{TimeReference: 100}
+		transcode(t, floatSource(1000, 1, test.samples), wav.SinkRing(&out, signal.BitDepth16, 10*time.Millisecond, wav.WithBext(&bext)))
+
+		r, err := wav.NewReader(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		var result []int16
+		for _, v := range readAll(t, r) {
+			result = append(result, int16(v*100+0.5))
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, result)
+		}
+		b, err := wav.ReadBext(bytes.NewReader(out.data))
+		if err != nil || b == nil || b.TimeReference != test.timeRef {
+			t.Errorf("%s: expected time reference %d got %+v: %v", test.name, test.timeRef, b, err)
+		}
+		if bext.TimeReference != 100 {
+			t.Errorf("%s: provided bext is changed", test.name)
+		}
+	}
+}
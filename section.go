@@ -0,0 +1,75 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// SourceRange reads frames of wav data from start to end, excluding end.
+// The frames before start are decoded and discarded, so it works with
+// compressed formats too. If the stream ends before end, it's done
+// earlier.
+func SourceRange(rs io.ReadSeeker, start, end int64, options ...Option) pipe.SourceAllocatorFunc {
+	opts := newOptions(options)
+	return func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+		if err := validateBufferSize(bufferSize); err != nil {
+			return pipe.Source{}, err
+		}
+		if start < 0 || end <= start {
+			return pipe.Source{}, fmt.Errorf("invalid range of frames %d-%d", start, end)
+		}
+		r, err := opts.newReader(rs, bufferSize)
+		if err != nil {
+			return pipe.Source{}, err
+		}
+		if err := r.skip(start, bufferSize); err != nil {
+			return pipe.Source{}, err
+		}
+		remaining := end - start
+		return pipe.Source{
+			SourceFunc: func(out signal.Floating) (int, error) {
+				if remaining == 0 {
+					return 0, io.EOF
+				}
+				if int64(out.Length()) > remaining {
+					out = out.Slice(0, int(remaining))
+				}
+				n, err := r.Read(out)
+				remaining -= int64(n)
+				return n, err
+			},
+			SignalProperties: pipe.SignalProperties{
+				SampleRate: r.format.SampleRate,
+				Channels:   r.format.Channels,
+			},
+		}, nil
+	}
+}
+
+// skip decodes and discards provided number of frames.
+func (r *Reader) skip(frames int64, bufferSize int) error {
+	buf := signal.Allocator{
+		Channels: r.format.Channels,
+		Length:   bufferSize,
+		Capacity: bufferSize,
+	}.Float64()
+	for frames > 0 {
+		out := buf
+		if int64(out.Length()) > frames {
+			out = out.Slice(0, int(frames))
+		}
+		n, err := r.readFrames(out)
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("range starts after the end of stream")
+			}
+			return err
+		}
+		frames -= int64(n)
+	}
+	return nil
+}
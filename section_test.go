@@ -0,0 +1,61 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// rampData returns payload of 16-bit mono data chunk with samples equal
+// to their index.
+func rampData(samples int) []byte {
+	data := make([]byte, 2*samples)
+	for i := 0; i < samples; i++ {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(i))
+	}
+	return data
+}
+
+// ramp returns 16-bit samples from start to end, excluding end.
+func ramp(start, end int) []int16 {
+	samples := make([]int16, 0, end-start)
+	for i := start; i < end; i++ {
+		samples = append(samples, int16(i))
+	}
+	return samples
+}
+
+func TestSourceRange(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 44100)),
+		chunk("data", rampData(2000)),
+	)
+	tests := []struct {
+		start, end int64
+		expected   []int16
+	}{
+		{0, 10, ramp(0, 10)},
+		{700, 1300, ramp(700, 1300)},
+		{1990, 3000, ramp(1990, 2000)},
+	}
+	for _, test := range tests {
+		var out buffer
+		transcode(t, wav.SourceRange(bytes.NewReader(data), test.start, test.end), wav.Sink(&out, signal.BitDepth16))
+		if samples := int16Samples(out.data); !reflect.DeepEqual(samples, test.expected) {
+			t.Errorf("range %d-%d: unexpected samples %v", test.start, test.end, samples)
+		}
+	}
+
+	if _, err := wav.SourceRange(bytes.NewReader(data), 10, 5)(mutable.Context{}, bufferSize); err == nil {
+		t.Errorf("expected error for invalid range")
+	}
+	if _, err := wav.SourceRange(bytes.NewReader(data), 3000, 4000)(mutable.Context{}, bufferSize); err == nil {
+		t.Errorf("expected error for range after the end")
+	}
+}
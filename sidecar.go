@@ -0,0 +1,66 @@
+package wav
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// SinkSidecar writes wav header and raw PCM data to separate writers, for
+// players that keep metadata apart from audio. PCM is written to pcm
+// writer as it's received. The header has the sizes of data, so it needs
+// the length of the stream: it's written to header writer on flush, when
+// all frames are known. The header ends with data chunk header, so the
+// header followed by PCM is a valid wav stream. Chunks are written before
+// data, the chunks that Sink writes after data are ignored. BitDepth is
+// output bit depth. Supported values: 8, 16, 24 and 32.
+func SinkSidecar(header, pcm io.Writer, bitDepth signal.BitDepth, options ...Option) pipe.SinkAllocatorFunc {
+	opts := newOptions(options)
+	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
+		if err := validateBufferSize(bufferSize); err != nil {
+			return pipe.Sink{}, err
+		}
+		f := Format{
+			SampleRate: props.SampleRate,
+			Channels:   props.Channels,
+			BitDepth:   bitDepth,
+		}
+		data := countingWriter{w: pcm}
+		s, err := opts.newStreamWriter(&data, f, bufferSize)
+		if err != nil {
+			return pipe.Sink{}, err
+		}
+		h := s.header
+		// only samples are written to pcm writer.
+		s.header = nil
+		return pipe.Sink{
+			SinkFunc: s.write,
+			FlushFunc: func(context.Context) error {
+				binary.LittleEndian.PutUint32(h[4:], uint32(int64(len(h)-8)+data.n))
+				binary.LittleEndian.PutUint32(h[len(h)-4:], uint32(data.n))
+				if _, err := header.Write(h); err != nil {
+					return fmt.Errorf("error writing header: %w", err)
+				}
+				return nil
+			},
+		}, nil
+	}
+}
+
+// countingWriter counts the bytes written to underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
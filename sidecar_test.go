@@ -0,0 +1,39 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestSinkSidecar(t *testing.T) {
+	samples := make([]float64, 2*1001)
+	for i := range samples {
+		samples[i] = float64(i%13)/13 - 0.5
+	}
+	var expected buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&expected, signal.BitDepth24))
+
+	var header, pcm bytes.Buffer
+	transcode(t, floatSource(44100, 2, samples), wav.SinkSidecar(&header, &pcm, signal.BitDepth24))
+	if header.Len() != 44 {
+		t.Errorf("expected 44 bytes of header got %d", header.Len())
+	}
+	if !bytes.Equal(expected.data[44:], pcm.Bytes()) {
+		t.Errorf("PCM differs from data of Sink")
+	}
+	joined := append(header.Bytes(), pcm.Bytes()...)
+	if !bytes.Equal(expected.data, joined) {
+		t.Errorf("header and PCM differ from Sink output")
+	}
+	info, err := wav.Probe(bytes.NewReader(joined))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Frames != 1001 || info.SampleRate != 44100 || info.BitDepth != signal.BitDepth24 {
+		t.Errorf("unexpected info %+v", info)
+	}
+}
@@ -0,0 +1,63 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// signed8ID is the identifier of the chunk that marks signed 8-bit
+// stream. WAV has no standard way to declare signed 8-bit samples, so
+// this chunk is specific to this package.
+var signed8ID = [4]byte{'s', 'g', 'n', '8'}
+
+// Signed8Bit makes Source decode 8-bit samples as signed instead of
+// unsigned. 8-bit wav data is unsigned by convention, but some legacy
+// tools store signed samples, which are decoded with inverted halves of
+// the waveform and a large DC offset otherwise. Sink with the same option
+// writes signed 8-bit samples and marks the stream with "sgn8" chunk.
+// Source detects the marker, so such streams are decoded as signed without
+// the option, unless SkipValidation is used. The option doesn't affect
+// other bit depths.
+func Signed8Bit() Option {
+	return func(o *options) {
+		o.signed8 = true
+	}
+}
+
+// signed8Marked returns true if the stream has sgn8 chunk. The container
+// is read if it wasn't inspected yet, the position of the stream is kept.
+func signed8Marked(rs io.ReadSeeker, c container, inspected bool) (bool, error) {
+	if !inspected {
+		pos, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return false, fmt.Errorf("error seeking stream: %w", err)
+		}
+		if c, err = readContainer(rs); err != nil {
+			return false, err
+		}
+		if _, err := rs.Seek(pos, io.SeekStart); err != nil {
+			return false, fmt.Errorf("error seeking stream: %w", err)
+		}
+	}
+	return c.index(signed8ID) != -1, nil
+}
+
+// unsignedSamples returns true if the samples of the reader are unsigned.
+func (r *Reader) unsignedSamples() bool {
+	return r.format.BitDepth == signal.BitDepth8 && !r.signed8
+}
+
+// unsignedSamples returns true if the samples of the writer are unsigned.
+func (w *Writer) unsignedSamples() bool {
+	return w.encoder.format.BitDepth == signal.BitDepth8 && !w.signed8
+}
+
+// signed8Samples converts the bytes decoded as unsigned into signed
+// values.
+func signed8Samples(data []int) {
+	for i, v := range data {
+		data[i] = int(int8(uint8(v)))
+	}
+}
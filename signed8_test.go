@@ -0,0 +1,89 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestSigned8Bit(t *testing.T) {
+	// signed: 0, 64, -64, 127, -128, -1.
+	data := riff(chunk("fmt ", fmtPayload(1, 8, 1, 8000)), chunk("data", []byte{0x00, 0x40, 0xC0, 0x7F, 0x80, 0xFF}))
+
+	r, err := wav.NewReader(bytes.NewReader(data), wav.Signed8Bit())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := signal.Allocator{Channels: 1, Length: 8, Capacity: 8}.Float64()
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := []float64{0, 64.0 / 127, -0.5, 1, -1, -1.0 / 128}
+	if n != len(expected) {
+		t.Fatalf("expected %d frames got %d", len(expected), n)
+	}
+	for i, e := range expected {
+		if d := buf.Sample(i) - e; d > 1e-9 || d < -1e-9 {
+			t.Errorf("sample %d: expected %v got %v", i, e, buf.Sample(i))
+		}
+	}
+
+	// the same data decoded as unsigned.
+	r, err = wav.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if buf.Sample(0) != -1 {
+		t.Errorf("expected unsigned zero byte decoded as -1 got %v", buf.Sample(0))
+	}
+}
+
+func TestSinkSigned8Bit(t *testing.T) {
+	samples := []float64{0, 0.5, -0.5, 1, -1, -1.0 / 128}
+	var out buffer
+	transcode(t, floatSource(8000, 1, samples), wav.Sink(&out, signal.BitDepth8, wav.Signed8Bit()))
+	if ids := chunkIDs(out.data); !reflect.DeepEqual(ids, []string{"fmt ", "sgn8", "data"}) {
+		t.Fatalf("unexpected chunks: %v", ids)
+	}
+	pcm := out.data[len(out.data)-len(samples):]
+	expected := []byte{0x00, 0x3F, 0xC0, 0x7F, 0x80, 0xFF}
+	if !bytes.Equal(expected, pcm) {
+		t.Errorf("expected %v got %v", expected, pcm)
+	}
+
+	// round trip is exact.
+	var again buffer
+	transcode(t, wav.Source(bytes.NewReader(out.data), wav.Signed8Bit()), wav.Sink(&again, signal.BitDepth8, wav.Signed8Bit()))
+	if !bytes.Equal(out.data, again.data) {
+		t.Errorf("expected %v got %v", out.data, again.data)
+	}
+
+	// marked stream is decoded as signed without the option.
+	var detected buffer
+	transcode(t, wav.Source(bytes.NewReader(out.data)), wav.Sink(&detected, signal.BitDepth8, wav.Signed8Bit()))
+	if !bytes.Equal(out.data, detected.data) {
+		t.Errorf("expected %v got %v", out.data, detected.data)
+	}
+	r, err := wav.NewReader(bytes.NewReader(out.data), wav.WithWarnings(&[]wav.Warning{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := readAll(t, r); result[1] != 63.0/127 {
+		t.Errorf("expected marked sample decoded as signed got %v", result[1])
+	}
+
+	// other bit depths aren't marked.
+	var wide buffer
+	transcode(t, floatSource(8000, 1, samples), wav.Sink(&wide, signal.BitDepth16, wav.Signed8Bit()))
+	if ids := chunkIDs(wide.data); !reflect.DeepEqual(ids, []string{"fmt ", "data"}) {
+		t.Errorf("unexpected chunks: %v", ids)
+	}
+}
@@ -0,0 +1,214 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// WithSilenceChunks makes Sink write runs of at least minFrames silent
+// frames as slnt chunks instead of samples. The data is written as LIST
+// chunk of "wavl" type that alternates data and slnt chunks, which shrinks
+// streams with long silence. The frame is silent if all its samples are
+// encoded as exact silence. Source expands the silence back, so the round
+// trip is exact, but many decoders don't support wave lists and can't read
+// such streams. Checkpoints can't be written with wave list.
+func WithSilenceChunks(minFrames int) Option {
+	return func(o *options) {
+		o.silenceChunks = minFrames
+	}
+}
+
+// validateSilenceChunks checks that wave list can be written with
+// provided options.
+func (o *options) validateSilenceChunks() error {
+	if o.silenceChunks == 0 {
+		return nil
+	}
+	if o.silenceChunks < 0 {
+		return fmt.Errorf("invalid minimum silence %d: must be positive", o.silenceChunks)
+	}
+	if o.checkpoints != 0 {
+		return errors.New("checkpoints can't be written with silence chunks")
+	}
+	return nil
+}
+
+// waveList writes encoded samples as wave list of data and slnt chunks.
+type waveList struct {
+	minFrames int64
+	frameSize int
+	// encoded value of silent samples.
+	fill byte
+	buf  []byte
+	// offset of the wave list size.
+	listOffset int64
+	// offset of the open data chunk size, -1 if there is none, and the
+	// size of its samples.
+	dataOffset int64
+	dataSize   int64
+	// number of silent frames that aren't written yet.
+	pending int64
+}
+
+// newWaveList returns wave list of the stream with provided format, nil
+// if silence is written as samples.
+func (o *options) newWaveList(f Format) *waveList {
+	if o.silenceChunks == 0 {
+		return nil
+	}
+	l := waveList{
+		minFrames:  int64(o.silenceChunks),
+		frameSize:  f.Channels * o.bytesPerSample(f),
+		dataOffset: -1,
+	}
+	if f.BitDepth == signal.BitDepth8 && !o.signed8 && !o.float {
+		// silence of unsigned 8-bit samples.
+		l.fill = 0x80
+	}
+	return &l
+}
+
+// start writes the header of wave list.
+func (l *waveList) start(e *encoder) error {
+	if err := e.AddLE(listID); err != nil {
+		return fmt.Errorf("error writing wave list header: %w", err)
+	}
+	l.listOffset = int64(e.WrittenBytes)
+	// size is updated when the list is closed.
+	if err := e.AddLE(uint32(0)); err != nil {
+		return fmt.Errorf("error writing wave list header: %w", err)
+	}
+	if err := e.AddLE(wavlID); err != nil {
+		return fmt.Errorf("error writing wave list header: %w", err)
+	}
+	return nil
+}
+
+// write encodes samples as little-endian values of provided size and
+// writes silent runs as slnt chunks.
+func (l *waveList) write(e *encoder, data []int, bytesPerSample int) error {
+	l.buf = l.buf[:0]
+	for _, v := range data {
+		for b := 0; b < bytesPerSample; b++ {
+			l.buf = append(l.buf, byte(v>>(8*uint(b))))
+		}
+	}
+	frames := l.buf
+	for start := 0; start < len(frames); {
+		end := start
+		silent := l.silent(frames[start : start+l.frameSize])
+		for end < len(frames) && l.silent(frames[end:end+l.frameSize]) == silent {
+			end += l.frameSize
+		}
+		if silent {
+			l.pending += int64((end - start) / l.frameSize)
+		} else {
+			if err := l.writeSilence(e); err != nil {
+				return err
+			}
+			if err := l.writeData(e, frames[start:end]); err != nil {
+				return err
+			}
+		}
+		start = end
+	}
+	return nil
+}
+
+// close writes pending silence and updates the size of the list.
+func (l *waveList) close(e *encoder) error {
+	if err := l.writeSilence(e); err != nil {
+		return err
+	}
+	if err := l.closeData(e); err != nil {
+		return err
+	}
+	return l.update(e, l.listOffset, uint32(int64(e.WrittenBytes)-l.listOffset-4))
+}
+
+// silent returns true if all samples of encoded frame are silent.
+func (l *waveList) silent(frame []byte) bool {
+	for _, b := range frame {
+		if b != l.fill {
+			return false
+		}
+	}
+	return true
+}
+
+// writeSilence writes pending silent frames. Short runs are written as
+// samples of data chunk.
+func (l *waveList) writeSilence(e *encoder) error {
+	pending := l.pending
+	if pending == 0 {
+		return nil
+	}
+	l.pending = 0
+	if pending < l.minFrames {
+		silence := make([]byte, int(pending)*l.frameSize)
+		for i := range silence {
+			silence[i] = l.fill
+		}
+		return l.writeData(e, silence)
+	}
+	if err := l.closeData(e); err != nil {
+		return err
+	}
+	payload := make([]byte, 4)
+	binary.LittleEndian.PutUint32(payload, uint32(pending))
+	return writeChunk(e.Encoder, rawChunk{ID: slntID, Payload: payload})
+}
+
+// writeData writes samples to the open data chunk. The chunk is opened
+// if needed.
+func (l *waveList) writeData(e *encoder, samples []byte) error {
+	if l.dataOffset == -1 {
+		if err := e.AddLE(dataID); err != nil {
+			return fmt.Errorf("error writing data chunk header: %w", err)
+		}
+		l.dataOffset = int64(e.WrittenBytes)
+		l.dataSize = 0
+		// size is updated when the chunk is closed.
+		if err := e.AddLE(uint32(0)); err != nil {
+			return fmt.Errorf("error writing data chunk header: %w", err)
+		}
+	}
+	if err := e.AddLE(samples); err != nil {
+		return err
+	}
+	l.dataSize += int64(len(samples))
+	return nil
+}
+
+// closeData updates the size of the open data chunk and pads it.
+func (l *waveList) closeData(e *encoder) error {
+	if l.dataOffset == -1 {
+		return nil
+	}
+	if err := l.update(e, l.dataOffset, uint32(l.dataSize)); err != nil {
+		return err
+	}
+	l.dataOffset = -1
+	if l.dataSize%2 == 1 {
+		if err := e.AddLE(uint8(0)); err != nil {
+			return fmt.Errorf("error writing data chunk padding: %w", err)
+		}
+	}
+	return nil
+}
+
+// update overwrites the size at provided offset and seeks back to the end
+// of the stream.
+func (l *waveList) update(e *encoder, offset int64, size uint32) error {
+	if err := e.overwrite(offset, size); err != nil {
+		return fmt.Errorf("error updating wave list size: %w", err)
+	}
+	if _, err := e.ws.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("error seeking stream end: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestSilenceChunks(t *testing.T) {
+	const channels = 2
+	// runs of tone and silence, the short silence is kept as samples.
+	var samples []float64
+	for _, run := range []struct {
+		frames int
+		silent bool
+	}{
+		{1000, true}, {99, false}, {3, true}, {50, false}, {1500, true}, {7, false}, {600, true},
+	} {
+		for i := 0; i < run.frames*channels; i++ {
+			if run.silent {
+				samples = append(samples, 0)
+			} else {
+				samples = append(samples, 0.5-float64(i%11)/10)
+			}
+		}
+	}
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24} {
+		var plain, compressed buffer
+		transcode(t, floatSource(44100, channels, samples), wav.Sink(&plain, bitDepth))
+		transcode(t, floatSource(44100, channels, samples), wav.Sink(&compressed, bitDepth, wav.WithSilenceChunks(100)))
+
+		if ids := chunkIDs(compressed.data); !reflect.DeepEqual(ids, []string{"fmt ", "LIST"}) {
+			t.Errorf("%d bits: unexpected chunks %v", bitDepth, ids)
+		}
+		frameSize := channels * int(bitDepth) / 8
+		if saved := len(plain.data) - len(compressed.data); saved < 3000*frameSize {
+			t.Errorf("%d bits: expected silence to be compressed, saved %d bytes", bitDepth, saved)
+		}
+
+		expected, err := wav.NewReader(bytes.NewReader(plain.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r, err := wav.NewReader(bytes.NewReader(compressed.data))
+		if err != nil {
+			t.Fatalf("%d bits: unexpected error: %v", bitDepth, err)
+		}
+		if e, result := readAll(t, expected), readAll(t, r); !reflect.DeepEqual(e, result) {
+			t.Errorf("%d bits: samples differ after round trip", bitDepth)
+		}
+	}
+
+	var out buffer
+	transcode(t, floatSource(44100, 1, []float64{0.1, 0, 0}), wav.Sink(&out, signal.BitDepth16, wav.WithSilenceChunks(1), wav.WithChunk([4]byte{'n', 'o', 't', 'e'}, []byte("end"), wav.AfterData)))
+	if ids := chunkIDs(out.data); !reflect.DeepEqual(ids, []string{"fmt ", "LIST", "note"}) {
+		t.Errorf("unexpected chunks with trailing chunk %v", ids)
+	}
+
+	// options of Sink apply to wave list.
+	bext := wav.Bext{Description: "silence"}
+	var rich readableBuffer
+	transcode(t, floatSource(8000, channels, samples), wav.Sink(&rich, signal.BitDepth16,
+		wav.WithSilenceChunks(100),
+		wav.WithBext(&bext),
+		wav.WithChecksum(),
+		wav.WithVerify(),
+		wav.WithExactLength(4000),
+		wav.WithResample(16000),
+	))
+	if ids := chunkIDs(rich.data); !reflect.DeepEqual(ids, []string{"fmt ", "bext", "LIST", "ckrc"}) {
+		t.Errorf("unexpected chunks with Sink options %v", ids)
+	}
+	if b, err := wav.ReadBext(bytes.NewReader(rich.data)); err != nil || b == nil || b.Description != "silence" {
+		t.Errorf("unexpected bext %+v: %v", b, err)
+	}
+	if err := wav.VerifyChecksum(bytes.NewReader(rich.data)); err != nil {
+		t.Errorf("unexpected checksum error: %v", err)
+	}
+	r, err := wav.NewReader(bytes.NewReader(rich.data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f := r.Format(); f.SampleRate != 16000 {
+		t.Errorf("expected resampled rate 16000 got %v", f.SampleRate)
+	}
+	if frames := len(readAll(t, r)) / channels; frames != 4000 {
+		t.Errorf("expected exact length of 4000 frames got %d", frames)
+	}
+
+	// signed 8-bit silence.
+	var signed buffer
+	transcode(t, floatSource(8000, 1, make([]float64, 300)), wav.Sink(&signed, signal.BitDepth8, wav.WithSilenceChunks(100), wav.Signed8Bit()))
+	r, err = wav.NewReader(bytes.NewReader(signed.data), wav.Signed8Bit())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := readAll(t, r); !reflect.DeepEqual(result, make([]float64, 300)) {
+		t.Errorf("unexpected signed 8-bit silence %v", result)
+	}
+	if size := len(signed.data); size > 100 {
+		t.Errorf("expected signed silence to be compressed, got %d bytes", size)
+	}
+
+	props := pipe.SignalProperties{SampleRate: 8000, Channels: 1}
+	for _, options := range [][]wav.Option{
+		{wav.WithSilenceChunks(-1)},
+		{wav.WithSilenceChunks(100), wav.WithCheckpoints(time.Second)},
+	} {
+		if _, err := wav.Sink(&buffer{}, signal.BitDepth16, options...)(mutable.Context{}, bufferSize, props); err == nil {
+			t.Errorf("expected error for options %d", len(options))
+		}
+	}
+}
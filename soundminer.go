@@ -0,0 +1,79 @@
+package wav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Soundminer is the metadata of sound effects library as Soundminer and
+// similar tools write it in the USER section of iXML chunk, with the
+// fields of Universal Category System.
+type Soundminer struct {
+	Description    string `xml:"DESCRIPTION"`
+	Category       string `xml:"CATEGORY"`
+	SubCategory    string `xml:"SUBCATEGORY"`
+	CatID          string `xml:"CATID"`
+	FXName         string `xml:"FXNAME"`
+	Keywords       string `xml:"KEYWORDS"`
+	Microphone     string `xml:"MICROPHONE"`
+	MicPerspective string `xml:"MICPERSPECTIVE"`
+	RecMedium      string `xml:"RECMEDIUM"`
+	Designer       string `xml:"DESIGNER"`
+	Library        string `xml:"LIBRARY"`
+	Manufacturer   string `xml:"MANUFACTURER"`
+	Show           string `xml:"SHOW"`
+	Location       string `xml:"LOCATION"`
+	TrackTitle     string `xml:"TRACKTITLE"`
+	Notes          string `xml:"NOTES"`
+	URL            string `xml:"URL"`
+}
+
+// ReadSoundminer returns the Soundminer metadata of the stream. The
+// description of bext chunk is used if iXML doesn't have one. Nil is
+// returned if the stream has neither. The binary SMED chunk is
+// proprietary and isn't decoded. The stream is rewinded to the start
+// afterwards.
+func ReadSoundminer(rs io.ReadSeeker) (*Soundminer, error) {
+	var s Soundminer
+	payload, ok, err := ReadChunk(rs, ixmlID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		var ixml struct {
+			User Soundminer `xml:"USER"`
+		}
+		if err := xml.Unmarshal(payload, &ixml); err != nil {
+			return nil, fmt.Errorf("error decoding iXML chunk: %w", err)
+		}
+		s = ixml.User
+		s.trim()
+	}
+	if s.Description == "" {
+		b, err := ReadBext(rs)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			s.Description = b.Description
+		}
+	}
+	if s == (Soundminer{}) {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// trim removes the surrounding whitespace of every field.
+func (s *Soundminer) trim() {
+	for _, field := range []*string{
+		&s.Description, &s.Category, &s.SubCategory, &s.CatID, &s.FXName,
+		&s.Keywords, &s.Microphone, &s.MicPerspective, &s.RecMedium,
+		&s.Designer, &s.Library, &s.Manufacturer, &s.Show, &s.Location,
+		&s.TrackTitle, &s.Notes, &s.URL,
+	} {
+		*field = strings.TrimSpace(*field)
+	}
+}
@@ -0,0 +1,85 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestReadSoundminer(t *testing.T) {
+	pcm := make([]byte, 4)
+	ixml := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<BWFXML>
+	<IXML_VERSION>2.0</IXML_VERSION>
+	<USER>
+		<CATEGORY>DOORS</CATEGORY>
+		<SUBCATEGORY>WOOD</SUBCATEGORY>
+		<CATID>DOORWood</CATID>
+		<FXNAME>Door Slam</FXNAME>
+		<DESCRIPTION> Heavy wooden door slams shut </DESCRIPTION>
+		<KEYWORDS>door, slam, wood</KEYWORDS>
+		<MICROPHONE>MKH 416</MICROPHONE>
+		<MICPERSPECTIVE>CU</MICPERSPECTIVE>
+		<LIBRARY>Household</LIBRARY>
+	</USER>
+</BWFXML>`)
+	bext := make([]byte, 602)
+	copy(bext, "Door slam from bext")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *wav.Soundminer
+	}{
+		{
+			name: "none",
+			data: riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("data", pcm)),
+		},
+		{
+			name: "iXML",
+			data: riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("bext", bext), chunk("iXML", ixml), chunk("data", pcm)),
+			expected: &wav.Soundminer{
+				Description:    "Heavy wooden door slams shut",
+				Category:       "DOORS",
+				SubCategory:    "WOOD",
+				CatID:          "DOORWood",
+				FXName:         "Door Slam",
+				Keywords:       "door, slam, wood",
+				Microphone:     "MKH 416",
+				MicPerspective: "CU",
+				Library:        "Household",
+			},
+		},
+		{
+			name:     "bext",
+			data:     riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("bext", bext), chunk("data", pcm)),
+			expected: &wav.Soundminer{Description: "Door slam from bext"},
+		},
+		{
+			name:     "iXML without description",
+			data:     riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("bext", bext), chunk("iXML", []byte("<BWFXML><USER><CATEGORY>DOORS</CATEGORY></USER></BWFXML>")), chunk("data", pcm)),
+			expected: &wav.Soundminer{Description: "Door slam from bext", Category: "DOORS"},
+		},
+	}
+	for _, test := range tests {
+		result, err := wav.ReadSoundminer(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if test.expected == nil {
+			if result != nil {
+				t.Errorf("%s: expected nil got %+v", test.name, *result)
+			}
+			continue
+		}
+		if result == nil || *result != *test.expected {
+			t.Errorf("%s: expected %+v got %+v", test.name, *test.expected, result)
+		}
+	}
+
+	malformed := riff(chunk("fmt ", fmtPayload(1, 16, 2, 48000)), chunk("iXML", []byte("<BWFXML><USER>")), chunk("data", pcm))
+	if _, err := wav.ReadSoundminer(bytes.NewReader(malformed)); err == nil {
+		t.Errorf("expected error for malformed iXML")
+	}
+}
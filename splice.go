@@ -0,0 +1,78 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"pipelined.dev/signal"
+)
+
+// Splice writes the frames of base stream up to at, all frames of insert
+// stream and the remaining frames of base stream. Both streams must have
+// the same sample rate and number of channels, the output has the bit
+// depth of base stream. Options are applied to the writer. Samples of
+// the same bit depth are copied exactly.
+func Splice(base io.ReadSeeker, at int64, insert io.ReadSeeker, out io.WriteSeeker, options ...Option) error {
+	if at < 0 {
+		return fmt.Errorf("invalid splice position %d", at)
+	}
+	br, err := NewReader(base)
+	if err != nil {
+		return fmt.Errorf("base: %w", err)
+	}
+	ir, err := NewReader(insert)
+	if err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+	bf, inf := br.Format(), ir.Format()
+	if bf.SampleRate != inf.SampleRate || bf.Channels != inf.Channels {
+		return fmt.Errorf("insert format %+v doesn't match base format %+v", inf, bf)
+	}
+	w, err := NewWriter(out, bf, options...)
+	if err != nil {
+		return err
+	}
+	buf := signal.Allocator{
+		Channels: bf.Channels,
+		Length:   normalizeBufferSize,
+		Capacity: normalizeBufferSize,
+	}.Float64()
+	copied, err := copyFrames(w, br, buf, at)
+	if err != nil {
+		return fmt.Errorf("base: %w", err)
+	}
+	if copied < at {
+		return fmt.Errorf("splice position %d is after the end of base at %d", at, copied)
+	}
+	if _, err := copyFrames(w, ir, buf, -1); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+	if _, err := copyFrames(w, br, buf, -1); err != nil {
+		return fmt.Errorf("base: %w", err)
+	}
+	return w.Close()
+}
+
+// copyFrames copies up to limit frames from reader to writer, all frames
+// if limit is negative. It returns the number of copied frames.
+func copyFrames(w *Writer, r *Reader, buf signal.Floating, limit int64) (int64, error) {
+	var copied int64
+	for limit < 0 || copied < limit {
+		out := buf
+		if remaining := limit - copied; limit >= 0 && remaining < int64(out.Length()) {
+			out = out.Slice(0, int(remaining))
+		}
+		n, err := r.Read(out)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return copied, err
+		}
+		if _, err := w.Write(out.Slice(0, n)); err != nil {
+			return copied, err
+		}
+		copied += int64(n)
+	}
+	return copied, nil
+}
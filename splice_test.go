@@ -0,0 +1,47 @@
+package wav_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+)
+
+func TestSplice(t *testing.T) {
+	base := riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)), chunk("data", rampData(1000)))
+	insertData := make([]byte, 2*600)
+	for i := 0; i < 600; i++ {
+		insertData[2*i] = 0
+		insertData[2*i+1] = 0x80 // -32768
+	}
+	insert := riff(chunk("fmt ", fmtPayload(1, 16, 2, 8000)), chunk("data", insertData))
+
+	for _, at := range []int64{0, 300, 1000} {
+		var out buffer
+		if err := wav.Splice(bytes.NewReader(base), at, bytes.NewReader(insert), &out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := append([]int16{}, ramp(0, int(at))...)
+		for i := 0; i < 600; i++ {
+			expected = append(expected, -32768)
+		}
+		expected = append(expected, ramp(int(at), 1000)...)
+		if samples := int16Samples(out.data); !reflect.DeepEqual(samples, expected) {
+			t.Errorf("at %d: unexpected samples", at)
+		}
+	}
+
+	var out buffer
+	if err := wav.Splice(bytes.NewReader(base), 1001, bytes.NewReader(insert), &out); err == nil {
+		t.Errorf("expected error for position after the end")
+	}
+	stereo := riff(chunk("fmt ", fmtPayload(2, 16, 4, 8000)), chunk("data", make([]byte, 8)))
+	if err := wav.Splice(bytes.NewReader(base), 0, bytes.NewReader(stereo), &out); err == nil {
+		t.Errorf("expected error for channels mismatch")
+	}
+	other := riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", make([]byte, 8)))
+	if err := wav.Splice(bytes.NewReader(base), 0, bytes.NewReader(other), &out); err == nil {
+		t.Errorf("expected error for sample rate mismatch")
+	}
+}
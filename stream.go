@@ -0,0 +1,154 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// segment is a part of virtual stream. It contains either the bytes, the
+// range of underlying stream or size bytes of silence.
+type segment struct {
+	data []byte
+	// range of underlying stream, used if data is nil.
+	offset int64
+	size   int64
+	// silence repeats the fill byte instead of reading the range.
+	silence bool
+	fill    byte
+}
+
+// len returns the size of segment in bytes.
+func (s segment) len() int64 {
+	if s.data != nil {
+		return int64(len(s.data))
+	}
+	return s.size
+}
+
+// virtualStream is io.ReadSeeker that joins segments. It allows to decode
+// repaired streams without copying the samples.
+type virtualStream struct {
+	rs       io.ReadSeeker
+	segments []segment
+	size     int64
+	offset   int64
+}
+
+func newVirtualStream(rs io.ReadSeeker, segments []segment) *virtualStream {
+	var size int64
+	for _, s := range segments {
+		size += s.len()
+	}
+	return &virtualStream{
+		rs:       rs,
+		segments: segments,
+		size:     size,
+	}
+}
+
+// Read implements io.Reader.
+func (v *virtualStream) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	start := int64(0)
+	for _, s := range v.segments {
+		end := start + s.len()
+		if v.offset >= end {
+			start = end
+			continue
+		}
+		if remaining := end - v.offset; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+		if s.data != nil {
+			n := copy(p, s.data[v.offset-start:])
+			v.offset += int64(n)
+			return n, nil
+		}
+		if s.silence {
+			for i := range p {
+				p[i] = s.fill
+			}
+			v.offset += int64(len(p))
+			return len(p), nil
+		}
+		if _, err := v.rs.Seek(s.offset+v.offset-start, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("error seeking stream: %w", err)
+		}
+		n, err := v.rs.Read(p)
+		v.offset += int64(n)
+		if err == io.EOF && n > 0 {
+			err = nil
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+// Seek implements io.Seeker.
+func (v *virtualStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += v.offset
+	case io.SeekEnd:
+		offset += v.size
+	default:
+		return v.offset, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return v.offset, errors.New("negative position")
+	}
+	v.offset = offset
+	return offset, nil
+}
+
+// chunkSegment returns the segment of the chunk with its header and
+// padding. The segment doesn't exceed the stream.
+func (c container) chunkSegment(h chunkHeader) segment {
+	end := h.end()
+	if end > c.Size {
+		end = c.Size
+	}
+	return segment{
+		offset: h.Offset - 8,
+		size:   end - h.Offset + 8,
+	}
+}
+
+// riffSegment returns RIFF header for the chunks of provided size.
+func riffSegment(size int64) segment {
+	header := make([]byte, 12)
+	copy(header[0:], riffID[:])
+	binary.LittleEndian.PutUint32(header[4:], uint32(size+4))
+	copy(header[8:], waveID[:])
+	return segment{data: header}
+}
+
+// canonicalOrder returns the stream with fmt chunk moved to the beginning
+// and data chunk moved to the end. Go-audio decoder reads the padding of
+// odd-sized data chunk as a sample, so the padding is dropped as well.
+// Other chunks keep their order.
+func (c container) canonicalOrder(rs io.ReadSeeker) io.ReadSeeker {
+	fmtIdx, dataIdx := c.index(fmtID), c.index(dataID)
+	chunks := []segment{c.chunkSegment(c.chunks[fmtIdx])}
+	for i, h := range c.chunks {
+		if i != fmtIdx && i != dataIdx {
+			chunks = append(chunks, c.chunkSegment(h))
+		}
+	}
+	data := c.chunkSegment(c.chunks[dataIdx])
+	if declared := int64(c.chunks[dataIdx].Size) + 8; data.size > declared {
+		data.size = declared
+	}
+	chunks = append(chunks, data)
+
+	var size int64
+	for _, s := range chunks {
+		size += s.len()
+	}
+	return newVirtualStream(rs, append([]segment{riffSegment(size)}, chunks...))
+}
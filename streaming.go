@@ -0,0 +1,283 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// Placeholder defines the values of size fields written by SinkStream,
+// which can't update the header when the length is known. Decoders
+// differ in how they interpret such sizes, so the placeholder should
+// match the consumer of the stream.
+type Placeholder int
+
+const (
+	// PlaceholderMax writes 0xFFFFFFFF as both RIFF and data sizes. It's
+	// the most common convention for the stream of unknown length.
+	// FFmpeg and the players built on it, e.g. mpv and VLC, as well as
+	// libsndfile and the tools built on it, e.g. Audacity, read such
+	// data until the end of stream.
+	PlaceholderMax Placeholder = iota
+	// PlaceholderZero writes zero sizes. FFmpeg treats zero data size as
+	// data until the end of stream, but strict decoders, e.g. Python's
+	// wave module, decode such stream as empty.
+	PlaceholderZero
+	// PlaceholderLarge writes the largest data size below 2 GiB aligned
+	// to the frame size, as SoX does when its output can't seek. It suits
+	// SoX and decoders that store sizes as signed 32-bit values or expect
+	// aligned data size, e.g. the ones that reject 0xFFFFFFFF as larger
+	// than the stream.
+	PlaceholderLarge
+)
+
+// largePlaceholder is the limit of data size of PlaceholderLarge.
+const largePlaceholder = 0x7FFFF000
+
+// WithPlaceholder makes SinkStream use provided placeholder sizes.
+// Default is PlaceholderMax.
+func WithPlaceholder(p Placeholder) Option {
+	return func(o *options) {
+		o.placeholder = p
+	}
+}
+
+// sizes returns RIFF and data sizes for the stream with provided header
+// size.
+func (p Placeholder) sizes(f Format, headerSize int) (uint32, uint32) {
+	switch p {
+	case PlaceholderZero:
+		return 0, 0
+	case PlaceholderLarge:
+		blockAlign := uint32(f.BlockAlign())
+		data := largePlaceholder / blockAlign * blockAlign
+		return data + uint32(headerSize-8), data
+	}
+	return 0xFFFFFFFF, 0xFFFFFFFF
+}
+
+// SinkStream writes wav data to Writer that can't seek, e.g. a pipe or
+// network connection. The header is written before the first buffer with
+// placeholder sizes, see WithPlaceholder. Chunks are written before data,
+// the chunks that Sink writes after data are ignored. Bext chunk is
+// written with the values it has at the first buffer. WithExactLength,
+// WithChecksum, WithVerify, WithCheckpoints, WithSilenceChunks, WithEvents
+// and WithCounters need to finalize the stream, so they're rejected.
+// BitDepth is output bit depth. Supported values: 8, 16, 24 and 32.
+func SinkStream(w io.Writer, bitDepth signal.BitDepth, options ...Option) pipe.SinkAllocatorFunc {
+	opts := newOptions(options)
+	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
+		if err := validateBufferSize(bufferSize); err != nil {
+			return pipe.Sink{}, err
+		}
+		f := Format{
+			SampleRate: props.SampleRate,
+			Channels:   props.Channels,
+			BitDepth:   bitDepth,
+		}
+		s, err := opts.newStreamWriter(w, f, bufferSize)
+		if err != nil {
+			return pipe.Sink{}, err
+		}
+		return pipe.Sink{
+			SinkFunc: s.write,
+		}, nil
+	}
+}
+
+// streamWriter encodes frames without seeking.
+type streamWriter struct {
+	w         io.Writer
+	format    Format
+	quantizer quantizer
+	signed    signal.Signed
+	unsigned  signal.Unsigned
+	signed8   bool
+	// header is written before the first buffer, nil afterwards.
+	header []byte
+	buf    []byte
+	// samples are written in big-endian byte order.
+	bigEndian bool
+	// number of silence frames to write before the first buffer.
+	preroll    int
+	bufferSize int
+}
+
+// newStreamWriter returns a new writer that encodes frames of provided
+// format without seeking. The options that need to finalize the stream
+// are rejected.
+func (o *options) newStreamWriter(w io.Writer, f Format, bufferSize int) (*streamWriter, error) {
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	if o.container24 != nil {
+		return nil, errors.New("4-byte containers can't be streamed")
+	}
+	if o.resample != 0 {
+		return nil, errors.New("resampled frames can't be streamed")
+	}
+	if o.exactLength != nil {
+		return nil, errors.New("exact length can't be streamed")
+	}
+	if o.checksum {
+		return nil, errors.New("checksum can't be streamed")
+	}
+	if o.verify {
+		return nil, errors.New("verification can't be streamed")
+	}
+	if o.checkpoints != 0 {
+		return nil, errors.New("checkpoints can't be streamed")
+	}
+	if o.silenceChunks != 0 {
+		return nil, errors.New("silence chunks can't be streamed")
+	}
+	if o.events != nil || o.counters != nil {
+		return nil, errors.New("events and counters of streamed frames aren't reported")
+	}
+	if err := o.validateChunks(); err != nil {
+		return nil, err
+	}
+	if o.float {
+		return nil, errors.New("float samples can't be streamed")
+	}
+	q, err := o.newQuantizer(f.Channels)
+	if err != nil {
+		return nil, err
+	}
+	s := streamWriter{
+		w:          w,
+		format:     f,
+		quantizer:  q,
+		signed8:    o.signed8,
+		header:     o.streamHeader(f),
+		preroll:    f.SampleRate.Events(o.preroll),
+		bufferSize: bufferSize,
+	}
+	alloc := signal.Allocator{
+		Channels: f.Channels,
+		Capacity: bufferSize,
+		Length:   bufferSize,
+	}
+	if s.unsignedSamples() {
+		s.unsigned = alloc.Uint8(f.BitDepth)
+	} else {
+		s.signed = alloc.Int64(f.BitDepth)
+	}
+	return &s, nil
+}
+
+// streamHeader returns RIFF header, fmt chunk, bext chunk, leading chunks
+// and data chunk header with placeholder sizes.
+func (o *options) streamHeader(f Format) []byte {
+	header := make([]byte, 12, canonicalHeaderSize)
+	copy(header[0:], riffID[:])
+	formType := o.riffFormType()
+	copy(header[8:], formType[:])
+	chunks := []rawChunk{{ID: fmtID, Payload: o.formatPayload(f)}}
+	if o.bext != nil {
+		chunks = append(chunks, rawChunk{ID: bextID, Payload: o.bext.encode()})
+	}
+	chunks = append(chunks, o.leadingChunks(f)...)
+	for _, c := range chunks {
+		var h [8]byte
+		copy(h[:], c.ID[:])
+		binary.LittleEndian.PutUint32(h[4:], uint32(len(c.Payload)))
+		header = append(append(header, h[:]...), c.Payload...)
+		if len(c.Payload)%2 == 1 {
+			header = append(header, 0)
+		}
+	}
+	header = append(header, dataID[:]...)
+	header = append(header, 0, 0, 0, 0)
+	riffSize, dataSize := o.placeholder.sizes(f, len(header))
+	binary.LittleEndian.PutUint32(header[4:], riffSize)
+	binary.LittleEndian.PutUint32(header[len(header)-4:], dataSize)
+	return header
+}
+
+// unsignedSamples returns true if the samples of the writer are unsigned.
+func (s *streamWriter) unsignedSamples() bool {
+	return s.format.BitDepth == signal.BitDepth8 && !s.signed8
+}
+
+// write encodes frames of provided buffer. The header and preroll are
+// written before the first buffer.
+func (s *streamWriter) write(floats signal.Floating) error {
+	if s.header != nil {
+		if _, err := s.w.Write(s.header); err != nil {
+			return fmt.Errorf("error writing header: %w", err)
+		}
+		s.header = nil
+	}
+	if s.preroll > 0 {
+		frames := s.preroll
+		s.preroll = 0
+		if err := s.writeSilence(frames); err != nil {
+			return err
+		}
+	}
+	return s.encode(floats)
+}
+
+// writeSilence encodes provided number of silent frames.
+func (s *streamWriter) writeSilence(frames int) error {
+	size := s.bufferSize
+	if frames < size {
+		size = frames
+	}
+	silence := signal.Allocator{
+		Channels: s.format.Channels,
+		Capacity: size,
+		Length:   size,
+	}.Float64()
+	for frames > 0 {
+		n := size
+		if frames < n {
+			n = frames
+		}
+		if err := s.encode(silence.Slice(0, n)); err != nil {
+			return err
+		}
+		frames -= n
+	}
+	return nil
+}
+
+// encode writes the samples of provided buffer.
+func (s *streamWriter) encode(floats signal.Floating) error {
+	bytesPerSample := int(s.format.BitDepth) / 8
+	s.buf = s.buf[:0]
+	if s.unsignedSamples() {
+		n, err := s.quantizer.floatingAsUnsigned(floats, s.unsigned)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n*s.format.Channels; i++ {
+			s.buf = append(s.buf, byte(s.unsigned.Sample(i)))
+		}
+	} else {
+		n, err := s.quantizer.floatingAsSigned(floats, s.signed)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n*s.format.Channels; i++ {
+			v := s.signed.Sample(i)
+			for b := 0; b < bytesPerSample; b++ {
+				shift := b
+				if s.bigEndian {
+					shift = bytesPerSample - 1 - b
+				}
+				s.buf = append(s.buf, byte(v>>(8*uint(shift))))
+			}
+		}
+	}
+	if _, err := s.w.Write(s.buf); err != nil {
+		return fmt.Errorf("error writing PCM buffer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestSinkStream(t *testing.T) {
+	samples := []float64{0.1, 0.2, 0.3, -0.4, 0.5, -0.6}
+	var expected buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&expected, signal.BitDepth16))
+
+	tests := []struct {
+		placeholder wav.Placeholder
+		riffSize    uint32
+		dataSize    uint32
+	}{
+		{wav.PlaceholderMax, 0xFFFFFFFF, 0xFFFFFFFF},
+		{wav.PlaceholderZero, 0, 0},
+		{wav.PlaceholderLarge, 0x7FFFF000 + 36, 0x7FFFF000},
+	}
+	for _, test := range tests {
+		var stream bytes.Buffer
+		transcode(t, floatSource(44100, 2, samples), wav.SinkStream(&stream, signal.BitDepth16, wav.WithPlaceholder(test.placeholder)))
+		data := stream.Bytes()
+		if riffSize := binary.LittleEndian.Uint32(data[4:]); riffSize != test.riffSize {
+			t.Errorf("placeholder %d: expected RIFF size %x got %x", test.placeholder, test.riffSize, riffSize)
+		}
+		if dataSize := binary.LittleEndian.Uint32(data[40:]); dataSize != test.dataSize {
+			t.Errorf("placeholder %d: expected data size %x got %x", test.placeholder, test.dataSize, dataSize)
+		}
+		if !bytes.Equal(data[8:40], expected.data[8:40]) {
+			t.Errorf("placeholder %d: header differs from Sink", test.placeholder)
+		}
+		if !bytes.Equal(data[44:], expected.data[44:]) {
+			t.Errorf("placeholder %d: samples differ from Sink", test.placeholder)
+		}
+	}
+
+	// large placeholder is aligned to the frame size.
+	var stream bytes.Buffer
+	transcode(t, floatSource(44100, 3, []float64{0.1, 0.2, 0.3}), wav.SinkStream(&stream, signal.BitDepth24, wav.WithPlaceholder(wav.PlaceholderLarge)))
+	if dataSize := binary.LittleEndian.Uint32(stream.Bytes()[40:]); dataSize%9 != 0 || dataSize > 0x7FFFF000 {
+		t.Errorf("unaligned data size %x", dataSize)
+	}
+
+	// 8-bit samples are unsigned.
+	stream.Reset()
+	transcode(t, floatSource(44100, 1, []float64{0, 0}), wav.SinkStream(&stream, signal.BitDepth8))
+	if pcm := stream.Bytes()[44:]; !bytes.Equal(pcm, []byte{128, 128}) {
+		t.Errorf("unexpected 8-bit samples %v", pcm)
+	}
+
+	// signed 8-bit samples, bext and preroll are written as Sink does.
+	bext := wav.Bext{Description: "stream"}
+	options := []wav.Option{wav.Signed8Bit(), wav.WithBext(&bext), wav.WithPreroll(time.Millisecond)}
+	var sink buffer
+	transcode(t, floatSource(8000, 1, []float64{0, 0.5, -0.5}), wav.Sink(&sink, signal.BitDepth8, options...))
+	stream.Reset()
+	transcode(t, floatSource(8000, 1, []float64{0, 0.5, -0.5}), wav.SinkStream(&stream, signal.BitDepth8, options...))
+	if ids := chunkIDs(stream.Bytes()); !reflect.DeepEqual(ids, chunkIDs(sink.data)) {
+		t.Errorf("expected chunks %v got %v", chunkIDs(sink.data), ids)
+	}
+	if pcm, expected := stream.Bytes()[len(stream.Bytes())-11:], sink.data[len(sink.data)-11:]; !bytes.Equal(pcm, expected) {
+		t.Errorf("expected samples %v got %v", expected, pcm)
+	}
+
+	props := pipe.SignalProperties{SampleRate: 8000, Channels: 1}
+	for name, option := range map[string]wav.Option{
+		"exact length": wav.WithExactLength(10),
+		"checksum":     wav.WithChecksum(),
+		"verify":       wav.WithVerify(),
+		"checkpoints":  wav.WithCheckpoints(time.Second),
+		"silence":      wav.WithSilenceChunks(10),
+		"events":       wav.WithEvents(func(wav.Event) {}),
+		"counters":     wav.WithCounters(&wav.Counters{}),
+	} {
+		if _, err := wav.SinkStream(&bytes.Buffer{}, signal.BitDepth16, option)(mutable.Context{}, bufferSize, props); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFrameCount is returned by Source when the stream ends before the
+// number of frames declared by data chunk is read, see StrictFrameCount.
+var ErrFrameCount = errors.New("frame count mismatch")
+
+// StrictFrameCount makes Source verify that the number of decoded frames
+// matches the number declared by data chunk. ErrFrameCount is returned
+// instead of io.EOF if the stream is truncated. Only linear PCM and float
+// streams are checked, the last block of compressed stream can be
+// partial.
+func StrictFrameCount() Option {
+	return func(o *options) {
+		o.strictFrames = true
+	}
+}
+
+// declaredFrames returns the number of frames that strict reader expects,
+// -1 if the frames aren't checked.
+func (o *options) declaredFrames(c container, f format) int64 {
+	if !o.strictFrames || !f.linear() {
+		return -1
+	}
+	return c.info(f).Frames
+}
+
+// checkFrameCount returns error if strict reader decoded fewer frames
+// than declared.
+func (r *Reader) checkFrameCount() error {
+	if r.declaredFrames < 0 || r.decodedFrames == r.declaredFrames {
+		return nil
+	}
+	return fmt.Errorf("%w: decoded %d frames, data chunk declares %d", ErrFrameCount, r.decodedFrames, r.declaredFrames)
+}
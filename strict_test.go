@@ -0,0 +1,56 @@
+package wav_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+func TestStrictFrameCount(t *testing.T) {
+	valid := riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", rampData(2000)))
+	truncated := riff(chunk("fmt ", fmtPayload(1, 16, 2, 44100)), chunk("data", rampData(2000)))
+	// data chunk still declares 2000 frames.
+	truncated = truncated[:len(truncated)-1000]
+
+	tests := []struct {
+		name     string
+		data     []byte
+		options  []wav.Option
+		expected error
+	}{
+		{name: "valid", data: valid, options: []wav.Option{wav.StrictFrameCount()}},
+		{name: "truncated", data: truncated, options: []wav.Option{wav.StrictFrameCount()}, expected: wav.ErrFrameCount},
+		{name: "truncated without check", data: truncated},
+	}
+	for _, test := range tests {
+		r, err := wav.NewReader(bytes.NewReader(test.data), test.options...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		buf := signal.Allocator{Channels: 1, Length: 300, Capacity: 300}.Float64()
+		for err == nil {
+			_, err = r.Read(buf)
+		}
+		if test.expected == nil && err != io.EOF || test.expected != nil && !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, err)
+		}
+	}
+
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Source: wav.Source(bytes.NewReader(truncated), wav.StrictFrameCount()),
+		Sink:   wav.Sink(&buffer{}, signal.BitDepth16),
+	})
+	if err != nil {
+		t.Fatalf("unexpected pipe error: %v", err)
+	}
+	if err := pipe.Wait(p.Start(context.Background())); !errors.Is(err, wav.ErrFrameCount) {
+		t.Errorf("expected frame count error got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+// Timecode is SMPTE timecode. FPS is the nominal number of frames per
+// second. If DropFrame is set, the frame rate is FPS*1000/1001 and frame
+// numbers are dropped to stay in sync with the clock, as for 29.97 and
+// 59.94 fps. Drop-frame timecode requires FPS to be a multiple of 30.
+type Timecode struct {
+	Hours     int
+	Minutes   int
+	Seconds   int
+	Frames    int
+	FPS       int
+	DropFrame bool
+}
+
+// String returns timecode in "hh:mm:ss:ff" format, semicolon separates
+// frames of drop-frame timecode.
+func (tc Timecode) String() string {
+	separator := ':'
+	if tc.DropFrame {
+		separator = ';'
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%c%02d", tc.Hours, tc.Minutes, tc.Seconds, separator, tc.Frames)
+}
+
+// dropped returns the number of frame numbers dropped every minute.
+func (tc Timecode) dropped() int64 {
+	if !tc.DropFrame {
+		return 0
+	}
+	return int64(tc.FPS / 15)
+}
+
+// number returns the number of video frames since zero timecode.
+func (tc Timecode) number() int64 {
+	fps := int64(tc.FPS)
+	minutes := int64(tc.Hours*60 + tc.Minutes)
+	n := (minutes*60+int64(tc.Seconds))*fps + int64(tc.Frames)
+	return n - tc.dropped()*(minutes-minutes/10)
+}
+
+// videoFrameSize returns the numerator and denominator of the number of
+// samples per video frame.
+func videoFrameSize(sampleRate signal.Frequency, fps int, dropFrame bool) (int64, int64) {
+	if dropFrame {
+		return int64(sampleRate) * 1001, int64(fps) * 1000
+	}
+	return int64(sampleRate), int64(fps)
+}
+
+// FrameAtTimecode returns the first sample frame at or after the start of
+// provided timecode, counting from zero timecode.
+func FrameAtTimecode(tc Timecode, sampleRate signal.Frequency) int64 {
+	num, den := videoFrameSize(sampleRate, tc.FPS, tc.DropFrame)
+	return (tc.number()*num + den - 1) / den
+}
+
+// TimecodeAtFrame returns the timecode of video frame that contains
+// provided sample frame, counting from zero timecode. It's the reverse of
+// FrameAtTimecode.
+func TimecodeAtFrame(frame int64, sampleRate signal.Frequency, fps int, dropFrame bool) Timecode {
+	num, den := videoFrameSize(sampleRate, fps, dropFrame)
+	n := frame * den / num
+	tc := Timecode{FPS: fps, DropFrame: dropFrame}
+	if drop := tc.dropped(); drop > 0 {
+		perMinute := int64(fps)*60 - drop
+		perTenMinutes := perMinute*10 + drop
+		tens, rest := n/perTenMinutes, n%perTenMinutes
+		n += drop * 9 * tens
+		if rest > drop {
+			n += drop * ((rest - drop) / perMinute)
+		}
+	}
+	perSecond := int64(fps)
+	tc.Frames = int(n % perSecond)
+	tc.Seconds = int(n / perSecond % 60)
+	tc.Minutes = int(n / (perSecond * 60) % 60)
+	tc.Hours = int(n / (perSecond * 3600))
+	return tc
+}
+
+// SourceTimecodeRange reads wav data from start to end timecode as
+// SourceRange does. The zero point of the stream is the TimeReference of
+// its bext chunk, or zero timecode if the stream has no bext chunk.
+func SourceTimecodeRange(rs io.ReadSeeker, start, end Timecode, options ...Option) pipe.SourceAllocatorFunc {
+	return func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+		info, err := Probe(rs)
+		if err != nil {
+			return pipe.Source{}, err
+		}
+		b, err := ReadBext(rs)
+		if err != nil {
+			return pipe.Source{}, err
+		}
+		var reference int64
+		if b != nil {
+			reference = int64(b.TimeReference)
+		}
+		first := FrameAtTimecode(start, info.SampleRate) - reference
+		if first < 0 {
+			return pipe.Source{}, fmt.Errorf("timecode %v is before the start of stream", start)
+		}
+		last := FrameAtTimecode(end, info.SampleRate) - reference
+		return SourceRange(rs, first, last, options...)(mctx, bufferSize)
+	}
+}
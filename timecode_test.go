@@ -0,0 +1,91 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe/mutable"
+	"pipelined.dev/signal"
+)
+
+func TestFrameAtTimecode(t *testing.T) {
+	tests := []struct {
+		tc         wav.Timecode
+		sampleRate signal.Frequency
+		expected   int64
+	}{
+		{wav.Timecode{Hours: 1, FPS: 25}, 48000, 3600 * 48000},
+		{wav.Timecode{Seconds: 1, Frames: 12, FPS: 24}, 48000, 48000 + 24000},
+		// 00:01:00;02 is the 1800th frame of drop-frame timecode.
+		{wav.Timecode{Minutes: 1, Frames: 2, FPS: 30, DropFrame: true}, 48000, 1800 * 48048 / 30},
+		// 00:10:00;00 isn't dropped, the frame is rounded up.
+		{wav.Timecode{Minutes: 10, FPS: 30, DropFrame: true}, 48000, 28799972},
+		{wav.Timecode{Frames: 1, FPS: 30, DropFrame: true}, 44100, 1472},
+	}
+	for _, test := range tests {
+		if frame := wav.FrameAtTimecode(test.tc, test.sampleRate); frame != test.expected {
+			t.Errorf("%v: expected frame %d got %d", test.tc, test.expected, frame)
+		}
+	}
+}
+
+func TestTimecodeAtFrame(t *testing.T) {
+	rates := []struct {
+		fps       int
+		dropFrame bool
+	}{
+		{24, false},
+		{25, false},
+		{30, false},
+		{30, true},
+		{60, true},
+	}
+	for _, rate := range rates {
+		for _, tc := range []wav.Timecode{
+			{FPS: rate.fps},
+			{Minutes: 1, Frames: 4, FPS: rate.fps},
+			{Minutes: 9, Seconds: 59, Frames: rate.fps - 1, FPS: rate.fps},
+			{Minutes: 10, FPS: rate.fps},
+			{Hours: 23, Minutes: 59, Seconds: 59, Frames: rate.fps - 1, FPS: rate.fps},
+		} {
+			tc.DropFrame = rate.dropFrame
+			frame := wav.FrameAtTimecode(tc, 48000)
+			if result := wav.TimecodeAtFrame(frame, 48000, rate.fps, rate.dropFrame); result != tc {
+				t.Errorf("expected %v got %v", tc, result)
+			}
+			if result := wav.TimecodeAtFrame(frame+1, 48000, rate.fps, rate.dropFrame); result != tc {
+				t.Errorf("expected %v for the next sample got %v", tc, result)
+			}
+		}
+	}
+	if s := (wav.Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, FPS: 30, DropFrame: true}).String(); s != "01:02:03;04" {
+		t.Errorf("unexpected string %s", s)
+	}
+}
+
+func TestSourceTimecodeRange(t *testing.T) {
+	bext := make([]byte, 602)
+	// 00:00:01:00 at 25 fps.
+	binary.LittleEndian.PutUint64(bext[338:], 8000)
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 8000)),
+		chunk("bext", bext),
+		chunk("data", rampData(2000)),
+	)
+	start := wav.Timecode{Seconds: 1, Frames: 5, FPS: 25}
+	end := wav.Timecode{Seconds: 1, Frames: 6, FPS: 25}
+	var out buffer
+	transcode(t, wav.SourceTimecodeRange(bytes.NewReader(data), start, end), wav.Sink(&out, signal.BitDepth16))
+	if samples := int16Samples(out.data); !reflect.DeepEqual(samples, ramp(1600, 1920)) {
+		t.Errorf("unexpected samples %v", samples)
+	}
+
+	before := wav.Timecode{Frames: 24, FPS: 25}
+	if _, err := wav.SourceTimecodeRange(bytes.NewReader(data), before, end)(mutable.Context{}, bufferSize); err == nil {
+		t.Errorf("expected error for timecode before the start")
+	}
+}
@@ -0,0 +1,110 @@
+//go:build go1.16
+// +build go1.16
+
+package wav
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// Report is the result of ValidateDir.
+type Report struct {
+	Files []FileReport
+}
+
+// FileReport is the result of validation of a single file.
+type FileReport struct {
+	// Path of the file within the filesystem.
+	Path       string
+	Violations []Violation
+	// Err is the error that prevented validation, e.g. ErrInvalidWav.
+	Err error
+}
+
+// Passed returns true if file is valid and meets the profile.
+func (r FileReport) Passed() bool {
+	return r.Err == nil && len(r.Violations) == 0
+}
+
+// Passed returns true if all files passed the validation.
+func (r Report) Passed() bool {
+	for _, f := range r.Files {
+		if !f.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the reports of files that didn't pass the validation.
+func (r Report) Failed() []FileReport {
+	var failed []FileReport
+	for _, f := range r.Files {
+		if !f.Passed() {
+			failed = append(failed, f)
+		}
+	}
+	return failed
+}
+
+// ValidateDir validates all files with .wav and .bwf extensions in the
+// filesystem against provided profile. Files that can't be read or
+// probed don't stop the validation, their errors are reported in the
+// results. An error is returned only if the filesystem can't be walked.
+func ValidateDir(fsys fs.FS, p Profile) (Report, error) {
+	var report Report
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !wavExtension(name) {
+			return nil
+		}
+		violations, err := validateFile(fsys, name, p)
+		report.Files = append(report.Files, FileReport{
+			Path:       name,
+			Violations: violations,
+			Err:        err,
+		})
+		return nil
+	})
+	if err != nil {
+		return Report{}, fmt.Errorf("error walking filesystem: %w", err)
+	}
+	return report, nil
+}
+
+// wavExtension returns true if the file name has wav extension.
+func wavExtension(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".wav", ".bwf":
+		return true
+	}
+	return false
+}
+
+// validateFile validates a single file of the filesystem. Files that
+// can't seek are read into memory.
+func validateFile(fsys fs.FS, name string, p Profile) ([]Violation, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		rs = bytes.NewReader(data)
+	}
+	return ValidateProfile(rs, p)
+}
@@ -0,0 +1,61 @@
+//go:build go1.16
+// +build go1.16
+
+package wav_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestValidateDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"delivery/a.wav": {Data: riff(
+			chunk("fmt ", fmtPayload(2, 24, 6, 48000)),
+			chunk("data", make([]byte, 60)),
+		)},
+		"delivery/reels/B.WAV": {Data: riff(
+			chunk("fmt ", fmtPayload(2, 16, 4, 48000)),
+			chunk("data", make([]byte, 40)),
+		)},
+		"delivery/broken.wav": {Data: []byte("not wav")},
+		"delivery/notes.txt":  {Data: []byte("not validated")},
+	}
+	report, err := wav.ValidateDir(fsys, wav.Profile{
+		SampleRates: []signal.Frequency{48000},
+		BitDepths:   []signal.BitDepth{signal.BitDepth24},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Files) != 3 {
+		t.Fatalf("expected 3 files got %+v", report.Files)
+	}
+	if report.Passed() {
+		t.Errorf("expected failed report")
+	}
+	results := make(map[string]wav.FileReport)
+	for _, f := range report.Files {
+		results[f.Path] = f
+	}
+	if r := results["delivery/a.wav"]; !r.Passed() {
+		t.Errorf("expected passed file got %+v", r)
+	}
+	if r := results["delivery/reels/B.WAV"]; len(r.Violations) != 1 || r.Violations[0].Field != "BitDepths" {
+		t.Errorf("expected bit depth violation got %+v", r)
+	}
+	if r := results["delivery/broken.wav"]; r.Err != wav.ErrInvalidWav {
+		t.Errorf("expected invalid wav error got %+v", r)
+	}
+	if failed := report.Failed(); len(failed) != 2 {
+		t.Errorf("expected 2 failed files got %+v", failed)
+	}
+
+	if _, err := wav.ValidateDir(fstest.MapFS{}, wav.Profile{}); err != nil {
+		t.Errorf("unexpected error for empty filesystem: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package wav
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// ErrVerification is returned by Sink with verification when the written
+// stream doesn't match encoded data.
+var ErrVerification = errors.New("verification failed")
+
+// WithVerify makes Sink read the stream back when it's flushed and
+// verify that data chunk matches the encoded samples. The samples are
+// compared by size and CRC-32 checksum. WriteSeeker must implement
+// io.Reader, e.g. *os.File opened for reading and writing.
+func WithVerify() Option {
+	return func(o *options) {
+		o.verify = true
+	}
+}
+
+// verifier computes the checksum of encoded samples.
+type verifier struct {
+	checksum       hash.Hash32
+	size           int64
+	bytesPerSample int
+	formType       [4]byte
+	buf            []byte
+}
+
+func newVerifier(bytesPerSample int, formType [4]byte) *verifier {
+	return &verifier{
+		checksum:       crc32.NewIEEE(),
+		bytesPerSample: bytesPerSample,
+		formType:       formType,
+	}
+}
+
+// update adds the samples encoded as little-endian values.
+func (v *verifier) update(data []int) {
+	if v == nil {
+		return
+	}
+	v.buf = v.buf[:0]
+	for _, s := range data {
+		for b := 0; b < v.bytesPerSample; b++ {
+			v.buf = append(v.buf, byte(s>>(8*uint(b))))
+		}
+	}
+	v.checksum.Write(v.buf)
+	v.size += int64(len(v.buf))
+}
+
+// verify reads data chunk of the stream and compares it with encoded
+// samples. The stream is positioned at the end afterwards.
+func (v *verifier) verify(ws io.WriteSeeker) error {
+	rs, ok := ws.(io.ReadSeeker)
+	if !ok {
+		return fmt.Errorf("%w: stream isn't readable", ErrVerification)
+	}
+	// wave list is verified as a single data chunk.
+	o := newOptions(nil)
+	data, err := o.expandWaveList(rs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	c, err := readForm(data, v.formType)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	h, ok := c.find(dataID)
+	if !ok {
+		return fmt.Errorf("%w: data chunk not found", ErrVerification)
+	}
+	if int64(h.Size) != v.size {
+		return fmt.Errorf("%w: data size %d instead of %d", ErrVerification, h.Size, v.size)
+	}
+	if _, err := data.Seek(h.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking data chunk: %w", err)
+	}
+	checksum := crc32.NewIEEE()
+	if _, err := io.CopyN(checksum, data, v.size); err != nil {
+		return fmt.Errorf("%w: error reading data: %v", ErrVerification, err)
+	}
+	if checksum.Sum32() != v.checksum.Sum32() {
+		return fmt.Errorf("%w: data checksum mismatch", ErrVerification)
+	}
+	if _, err := rs.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("error seeking stream end: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package wav_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/pipe"
+	"pipelined.dev/signal"
+)
+
+// readableBuffer is in-memory io.ReadWriteSeeker. If corrupt is set, the
+// byte at offset 50 is flipped on write.
+type readableBuffer struct {
+	buffer
+	corrupt bool
+}
+
+func (b *readableBuffer) Read(p []byte) (int, error) {
+	if b.pos >= len(b.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, b.data[b.pos:])
+	b.pos += n
+	return n, nil
+}
+
+func (b *readableBuffer) Write(p []byte) (int, error) {
+	start := b.pos
+	n, err := b.buffer.Write(p)
+	if b.corrupt && start <= 50 && 50 < b.pos {
+		b.data[50] ^= 0xFF
+	}
+	return n, err
+}
+
+func TestVerify(t *testing.T) {
+	samples := make([]float64, 2*bufferSize+10)
+	for i := range samples {
+		samples[i] = float64(i%200)/100 - 1
+	}
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24, signal.BitDepth32} {
+		transcode(t, floatSource(44100, 2, samples), wav.Sink(&readableBuffer{}, bitDepth, wav.WithVerify(), wav.WithPreroll(time.Millisecond)))
+	}
+
+	run := func(ws io.WriteSeeker) error {
+		p, err := pipe.New(bufferSize, pipe.Line{
+			Source: floatSource(44100, 2, samples),
+			Sink:   wav.Sink(ws, signal.BitDepth16, wav.WithVerify()),
+		})
+		if err != nil {
+			return err
+		}
+		return pipe.Wait(p.Start(context.Background()))
+	}
+	if err := run(&readableBuffer{corrupt: true}); !errors.Is(err, wav.ErrVerification) {
+		t.Errorf("expected verification error got %v", err)
+	}
+	if err := run(&buffer{}); !errors.Is(err, wav.ErrVerification) {
+		t.Errorf("expected verification error for unreadable stream got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package wav
+
+import "fmt"
+
+// Warning is a non-fatal anomaly found in wav data. Files with warnings can
+// be decoded, but might need attention.
+type Warning struct {
+	// Offset is the position in bytes where the anomaly was found.
+	Offset  int64
+	Message string
+}
+
+// String returns the warning message with its offset.
+func (w Warning) String() string {
+	return fmt.Sprintf("offset %d: %s", w.Offset, w.Message)
+}
+
+// WithWarnings makes Source append non-fatal anomalies found in the file
+// to provided slice. Warnings are collected during Source allocation.
+// Sink appends the warnings of enabled checks on Close.
+func WithWarnings(warnings *[]Warning) Option {
+	return func(o *options) {
+		o.warnings = warnings
+	}
+}
+
+// warn appends the warning if warnings are collected.
+func (o *options) warn(offset int64, format string, args ...interface{}) {
+	if o.warnings == nil {
+		return
+	}
+	*o.warnings = append(*o.warnings, Warning{
+		Offset:  offset,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkContainer reports anomalies of the container layout.
+func (o *options) checkContainer(c container) {
+	if declared := int64(c.RIFFSize) + 8; declared != c.Size {
+		o.warn(4, "RIFF size %d doesn't match stream size %d", declared, c.Size)
+	}
+	for _, h := range c.chunks {
+		if !knownChunks[h.ID] {
+			o.warn(h.Offset-8, "unexpected %q chunk", h.ID[:])
+		}
+		if h.Offset+int64(h.Size) > c.Size {
+			o.warn(h.Offset-8, "%q chunk size %d exceeds stream size", h.ID[:], h.Size)
+		}
+	}
+	if fmtIdx, dataIdx := c.index(fmtID), c.index(dataID); fmtIdx > dataIdx && dataIdx != -1 {
+		o.warn(c.chunks[fmtIdx].Offset-8, "fmt chunk after data chunk")
+	}
+}
+
+// checkFormat reports anomalies of the fmt chunk.
+func (o *options) checkFormat(f format, c container) {
+	if blockAlign := int(f.Channels) * f.bytesPerSample(); f.linear() && int(f.BlockAlign) != blockAlign && o.container24 == nil {
+		o.warn(f.offset, "block align %d doesn't match %d channels of %d bits", f.BlockAlign, f.Channels, f.BitsPerSample)
+	}
+	if byteRate := f.SampleRate * uint32(f.BlockAlign); f.linear() && f.ByteRate != byteRate {
+		o.warn(f.offset, "byte rate %d doesn't match expected %d", f.ByteRate, byteRate)
+	}
+	if f.AudioFormat == formatExtensible && !f.extended() {
+		o.warn(f.offset, "extensible format has %d bytes of extension instead of %d, decoded as %d-bit PCM", len(f.Extension), extensibleSize, f.BitsPerSample)
+	}
+	if data, ok := c.find(dataID); ok && f.BlockAlign > 0 && data.Size%uint32(f.BlockAlign) != 0 {
+		o.warn(data.Offset-8, "data size %d isn't aligned to block align %d", data.Size, f.BlockAlign)
+	}
+}
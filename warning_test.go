@@ -0,0 +1,62 @@
+package wav_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+	"pipelined.dev/signal"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     func(t *testing.T) []byte
+		expected []string
+	}{
+		{
+			name: "valid",
+			data: func(t *testing.T) []byte {
+				data, err := ioutil.ReadFile(wavSample)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return data
+			},
+		},
+		{
+			name: "anomalies",
+			data: func(*testing.T) []byte {
+				return riff(
+					chunk("data", make([]byte, 7)),
+					chunk("abcd", []byte{1, 2}),
+					chunk("fmt ", fmtPayload(2, 16, 6, 44100)),
+				)
+			},
+			expected: []string{
+				`unexpected "abcd" chunk`,
+				"fmt chunk after data chunk",
+				"block align 6 doesn't match 2 channels of 16 bits",
+				"data size 7 isn't aligned to block align 6",
+			},
+		},
+	}
+	for _, test := range tests {
+		var warnings []wav.Warning
+		var out buffer
+		transcode(t,
+			wav.Source(bytes.NewReader(test.data(t)), wav.WithWarnings(&warnings)),
+			wav.Sink(&out, signal.BitDepth16),
+		)
+		if len(warnings) != len(test.expected) {
+			t.Fatalf("%s: expected %d warnings got: %v", test.name, len(test.expected), warnings)
+		}
+		for i := range warnings {
+			if !strings.Contains(warnings[i].Message, test.expected[i]) {
+				t.Errorf("%s: expected %q got %q", test.name, test.expected[i], warnings[i])
+			}
+		}
+	}
+}
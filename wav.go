@@ -6,9 +6,6 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/go-audio/audio"
-	"github.com/go-audio/wav"
-
 	"pipelined.dev/pipe"
 	"pipelined.dev/pipe/mutable"
 	"pipelined.dev/signal"
@@ -20,171 +17,112 @@ const wavOutFormat = 1
 // ErrInvalidWav is returned when wav file is not valid.
 var ErrInvalidWav = errors.New("invalid WAV")
 
-// Source reads wav data from ReadSeeker.
-func Source(rs io.ReadSeeker) pipe.SourceAllocatorFunc {
-	return func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
-		decoder := wav.NewDecoder(rs)
-		if !decoder.IsValidFile() {
-			return pipe.Source{}, ErrInvalidWav
-		}
-
-		channels := decoder.Format().NumChannels
-		bitDepth := signal.BitDepth(decoder.BitDepth)
+// validateBufferSize returns error if allocators can't make buffers of
+// provided size.
+func validateBufferSize(bufferSize int) error {
+	if bufferSize <= 0 {
+		return fmt.Errorf("invalid buffer size %d: must be positive", bufferSize)
+	}
+	return nil
+}
 
-		// PCM buffer for wav decoder.
-		pcm := audio.IntBuffer{
-			Format:         decoder.Format(),
-			SourceBitDepth: int(bitDepth),
-			Data:           make([]int, bufferSize*channels),
-		}
-		alloc := signal.Allocator{
-			Channels: channels,
-			Capacity: bufferSize,
-			Length:   bufferSize,
+// Source reads wav data from ReadSeeker. Wave list of data and slnt
+// chunks is decoded as continuous frames with silence expanded inline.
+func Source(rs io.ReadSeeker, options ...Option) pipe.SourceAllocatorFunc {
+	opts := newOptions(options)
+	return func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+		if err := validateBufferSize(bufferSize); err != nil {
+			return pipe.Source{}, err
 		}
-		// 8-bits wav audio is encoded as unsigned signal
-		var sourceFn pipe.SourceFunc
-		if bitDepth == signal.BitDepth8 {
-			sourceFn = sourceUnsigned(decoder, alloc.Uint8(bitDepth), pcm)
-		} else {
-			sourceFn = sourceSigned(decoder, alloc.Int64(bitDepth), pcm)
+		r, err := opts.newReader(rs, bufferSize)
+		if err != nil {
+			return pipe.Source{}, err
 		}
 		return pipe.Source{
-			SourceFunc: sourceFn,
+			SourceFunc: r.Read,
 			SignalProperties: pipe.SignalProperties{
-				SampleRate: signal.Frequency(decoder.SampleRate),
-				Channels:   channels,
+				SampleRate: r.format.SampleRate,
+				Channels:   r.format.Channels,
 			},
 		}, nil
 	}
 }
 
-func sourceSigned(decoder *wav.Decoder, signed signal.Signed, pcm audio.IntBuffer) pipe.SourceFunc {
-	return func(floating signal.Floating) (int, error) {
-		// read new buffer, io.EOF is never returned here.
-		read, err := decoder.PCMBuffer(&pcm)
-		if err != nil {
-			return 0, fmt.Errorf("error reading PCM buffer: %w", err)
-		}
-		if read == 0 {
-			return 0, io.EOF
-		}
-
-		if read != len(pcm.Data) {
-			read = signal.WriteInt(pcm.Data[:read], signed)
-		} else {
-			read = signal.WriteInt(pcm.Data, signed)
-		}
-
-		if read != floating.Length() {
-			return signal.SignedAsFloating(signed.Slice(0, read), floating), nil
-		}
-		return signal.SignedAsFloating(signed, floating), nil
-	}
-}
-
-func sourceUnsigned(decoder *wav.Decoder, unsigned signal.Unsigned, pcm audio.IntBuffer) pipe.SourceFunc {
-	return func(floating signal.Floating) (int, error) {
-		// read new buffer, io.EOF is never returned here.
-		read, err := decoder.PCMBuffer(&pcm)
-		if err != nil {
-			return 0, fmt.Errorf("error reading PCM buffer: %w", err)
-		}
-		if read == 0 {
-			return 0, io.EOF
-		}
-
-		for i := 0; i < read; i++ {
-			unsigned.SetSample(i, uint64(pcm.Data[i]))
-		}
-		if read != len(pcm.Data) {
-			return signal.UnsignedAsFloating(unsigned.Slice(0, signal.ChannelLength(read, unsigned.Channels())), floating), nil
-		}
-		return signal.UnsignedAsFloating(unsigned, floating), nil
-	}
-}
-
 // Sink writes wav data to WriteSeeker. BitDepth is output bit depth.
 // Supported values: 8, 16, 24 and 32.
-func Sink(ws io.WriteSeeker, bitDepth signal.BitDepth) pipe.SinkAllocatorFunc {
+//
+// Source decodes positive samples scaled by maximum signed value of bit
+// depth and negative ones by the value above it, so both full scale values
+// are exactly 1 and -1, and Sink scales them back the same way. When
+// Source is transcoded to Sink, up-conversion, e.g. 16 to 24 bits, is
+// lossless: transcoding back restores exact samples. Down-conversion
+// discards the low bits: output samples differ from input by less than
+// 1 LSB of output with default RoundTruncate and by at most 0.5 LSB with
+// other rounding modes. The same bit depth keeps every sample.
+func Sink(ws io.WriteSeeker, bitDepth signal.BitDepth, options ...Option) pipe.SinkAllocatorFunc {
+	opts := newOptions(options)
 	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
-		encoder := wav.NewEncoder(
-			ws,
-			int(props.SampleRate),
-			int(bitDepth),
-			props.Channels,
-			wavOutFormat,
-		)
-		// PCM buffer for write, refers data of ints buffer.
-		PCM := audio.IntBuffer{
-			Format: &audio.Format{
-				NumChannels: props.Channels,
-				SampleRate:  int(props.SampleRate),
-			},
-			SourceBitDepth: int(bitDepth),
-			Data:           make([]int, bufferSize*props.Channels),
+		if err := validateBufferSize(bufferSize); err != nil {
+			return pipe.Sink{}, err
+		}
+		f, err := opts.paddedFormat(Format{
+			SampleRate: props.SampleRate,
+			Channels:   props.Channels,
+			BitDepth:   bitDepth,
+		})
+		if err != nil {
+			return pipe.Sink{}, err
 		}
-
-		alloc := signal.Allocator{
-			Channels: props.Channels,
-			Capacity: bufferSize,
-			Length:   bufferSize,
+		var resampler *resampler
+		if opts.resample != 0 {
+			if resampler, err = newResampler(f, opts.resample, opts.resampleQuality); err != nil {
+				return pipe.Sink{}, err
+			}
+			f.SampleRate = opts.resample
+		}
+		w, err := opts.newWriter(ws, f, bufferSize)
+		if err != nil {
+			return pipe.Sink{}, err
 		}
-		// 8-bits wav audio is encoded as unsigned signal
-		var sinkFn pipe.SinkFunc
-		if bitDepth == signal.BitDepth8 {
-			sinkFn = sinkUnsigned(encoder, alloc.Uint8(bitDepth), PCM)
-		} else {
-			sinkFn = sinkSigned(encoder, alloc.Int64(bitDepth), PCM)
+		var padder *channelPadder
+		if f.Channels != props.Channels {
+			padder = newChannelPadder(f.Channels, bufferSize)
 		}
 		return pipe.Sink{
-			SinkFunc:  sinkFn,
-			FlushFunc: encoderFlusher(encoder),
+			SinkFunc: func(floats signal.Floating) error {
+				if padder != nil {
+					floats = padder.pad(floats)
+				}
+				if resampler != nil {
+					if floats = resampler.write(floats); floats.Length() == 0 {
+						return nil
+					}
+				}
+				_, err := w.Write(floats)
+				return err
+			},
+			FlushFunc: func(context.Context) error {
+				if resampler != nil {
+					if floats := resampler.flush(); floats.Length() > 0 {
+						if _, err := w.Write(floats); err != nil {
+							return err
+						}
+					}
+				}
+				return w.Close()
+			},
 		}, nil
 	}
 }
 
-func sinkSigned(encoder *wav.Encoder, ints signal.Signed, pcm audio.IntBuffer) pipe.SinkFunc {
-	return func(floats signal.Floating) error {
-		if n := signal.FloatingAsSigned(floats, ints); n != ints.Length() {
-			pcm.Data = pcm.Data[:ints.Channels()*n]
-			// defer because it must be done after write
-			defer func() {
-				pcm.Data = pcm.Data[:ints.Cap()]
-			}()
-		}
-		signal.ReadInt(ints, pcm.Data)
-		if err := encoder.Write(&pcm); err != nil {
-			return fmt.Errorf("error writing PCM buffer: %w", err)
-		}
-		return nil
-	}
-}
-
-func sinkUnsigned(encoder *wav.Encoder, uints signal.Unsigned, pcm audio.IntBuffer) pipe.SinkFunc {
-	return func(floats signal.Floating) error {
-		if n := signal.FloatingAsUnsigned(floats, uints); n != uints.Length() {
-			pcm.Data = pcm.Data[:uints.Channels()*n]
-			// defer because it must be done after write
-			defer func() {
-				pcm.Data = pcm.Data[:uints.Cap()]
-			}()
-		}
-		for i := 0; i < len(pcm.Data); i++ {
-			pcm.Data[i] = int(uints.Sample(i))
-		}
-		if err := encoder.Write(&pcm); err != nil {
-			return fmt.Errorf("error writing PCM buffer: %w", err)
-		}
-		return nil
-	}
-}
-
-func encoderFlusher(encoder *wav.Encoder) pipe.FlushFunc {
-	return func(context.Context) error {
-		if err := encoder.Close(); err != nil {
-			return fmt.Errorf("error flushing WAV encoder: %w", err)
-		}
-		return nil
+// SinkRateOverride writes wav data to WriteSeeker as Sink does, but
+// declares provided sample rate in the header instead of the rate of the
+// pipe. This only relabels the data: the samples are not resampled, so
+// the playback speed and pitch change accordingly.
+func SinkRateOverride(ws io.WriteSeeker, bitDepth signal.BitDepth, declaredRate signal.Frequency, options ...Option) pipe.SinkAllocatorFunc {
+	sink := Sink(ws, bitDepth, options...)
+	return func(mctx mutable.Context, bufferSize int, props pipe.SignalProperties) (pipe.Sink, error) {
+		props.SampleRate = declaredRate
+		return sink(mctx, bufferSize, props)
 	}
 }
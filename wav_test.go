@@ -1,13 +1,20 @@
 package wav_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"io"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"pipelined.dev/audio/wav"
 
 	"pipelined.dev/pipe"
+	"pipelined.dev/pipe/mutable"
 	"pipelined.dev/signal"
 )
 
@@ -58,3 +65,166 @@ func TestWavPipe(t *testing.T) {
 		}
 	}
 }
+
+// buffer is in-memory io.WriteSeeker.
+type buffer struct {
+	data []byte
+	pos  int
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	if end := b.pos + len(p); end > len(b.data) {
+		b.data = append(b.data, make([]byte, end-len(b.data))...)
+	}
+	n := copy(b.data[b.pos:], p)
+	b.pos += n
+	return n, nil
+}
+
+func (b *buffer) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += int64(b.pos)
+	case io.SeekEnd:
+		offset += int64(len(b.data))
+	}
+	b.pos = int(offset)
+	return offset, nil
+}
+
+// transcode runs the pipe with provided source and sink.
+func transcode(t *testing.T, source pipe.SourceAllocatorFunc, sink pipe.SinkAllocatorFunc) {
+	t.Helper()
+	p, err := pipe.New(bufferSize, pipe.Line{
+		Source: source,
+		Sink:   sink,
+	})
+	if err != nil {
+		t.Fatalf("unexpected pipe error: %v", err)
+	}
+	if err = pipe.Wait(p.Start(context.Background())); err != nil {
+		t.Fatalf("unexpected run error: %v", err)
+	}
+}
+
+// floatSource returns source that emits provided interleaved samples.
+func floatSource(sampleRate signal.Frequency, channels int, samples []float64) pipe.SourceAllocatorFunc {
+	return func(mctx mutable.Context, bufferSize int) (pipe.Source, error) {
+		pos := 0
+		return pipe.Source{
+			SourceFunc: func(out signal.Floating) (int, error) {
+				if pos == len(samples) {
+					return 0, io.EOF
+				}
+				n := signal.WriteFloat64(samples[pos:], out)
+				pos += n * channels
+				return n, nil
+			},
+			SignalProperties: pipe.SignalProperties{
+				SampleRate: sampleRate,
+				Channels:   channels,
+			},
+		}, nil
+	}
+}
+
+// int16Samples decodes 16-bit PCM samples of canonical wav file.
+func int16Samples(data []byte) []int16 {
+	pcm := data[44:]
+	samples := make([]int16, len(pcm)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(pcm[2*i:]))
+	}
+	return samples
+}
+
+// chunk returns RIFF chunk with provided identifier and payload.
+func chunk(id string, payload []byte) []byte {
+	c := make([]byte, 8, 8+len(payload)+1)
+	copy(c, id)
+	binary.LittleEndian.PutUint32(c[4:], uint32(len(payload)))
+	c = append(c, payload...)
+	if len(payload)%2 == 1 {
+		c = append(c, 0)
+	}
+	return c
+}
+
+// riff returns RIFF WAVE stream that contains provided chunks.
+func riff(chunks ...[]byte) []byte {
+	s := []byte("RIFF\x00\x00\x00\x00WAVE")
+	for _, c := range chunks {
+		s = append(s, c...)
+	}
+	binary.LittleEndian.PutUint32(s[4:], uint32(len(s)-8))
+	return s
+}
+
+// fmtPayload returns payload of PCM fmt chunk.
+func fmtPayload(channels, bitsPerSample, blockAlign uint16, sampleRate uint32) []byte {
+	p := make([]byte, 16)
+	binary.LittleEndian.PutUint16(p[0:], 1)
+	binary.LittleEndian.PutUint16(p[2:], channels)
+	binary.LittleEndian.PutUint32(p[4:], sampleRate)
+	binary.LittleEndian.PutUint32(p[8:], sampleRate*uint32(blockAlign))
+	binary.LittleEndian.PutUint16(p[12:], blockAlign)
+	binary.LittleEndian.PutUint16(p[14:], bitsPerSample)
+	return p
+}
+
+// chunkIDs returns identifiers of all chunks in RIFF WAVE stream.
+func chunkIDs(data []byte) []string {
+	var ids []string
+	for offset := 12; offset+8 <= len(data); {
+		ids = append(ids, string(data[offset:offset+4]))
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		offset += 8 + size + size%2
+	}
+	return ids
+}
+
+func TestSinkRateOverride(t *testing.T) {
+	samples := []float64{0.1, 0.2, 0.3, -0.4}
+	var sink, relabeled buffer
+	transcode(t, floatSource(44100, 2, samples), wav.Sink(&sink, signal.BitDepth16))
+	transcode(t, floatSource(44100, 2, samples), wav.SinkRateOverride(&relabeled, signal.BitDepth16, 48000))
+	if rate := binary.LittleEndian.Uint32(relabeled.data[24:]); rate != 48000 {
+		t.Errorf("expected sample rate 48000 got %d", rate)
+	}
+	if byteRate := binary.LittleEndian.Uint32(relabeled.data[28:]); byteRate != 48000*4 {
+		t.Errorf("expected byte rate %d got %d", 48000*4, byteRate)
+	}
+	if !reflect.DeepEqual(int16Samples(sink.data), int16Samples(relabeled.data)) {
+		t.Errorf("samples are changed by relabeling")
+	}
+}
+
+func TestInvalidBufferSize(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 44100)),
+		chunk("data", make([]byte, 20)),
+	)
+	props := pipe.SignalProperties{SampleRate: 44100, Channels: 1}
+	for _, size := range []int{0, -1} {
+		sources := map[string]pipe.SourceAllocatorFunc{
+			"Source":      wav.Source(bytes.NewReader(data)),
+			"SourceRange": wav.SourceRange(bytes.NewReader(data), 0, 5),
+			"SourceSine":  wav.SourceSine(440, 44100, 1, 10),
+		}
+		for name, source := range sources {
+			if _, err := source(mutable.Context{}, size); err == nil || !strings.Contains(err.Error(), "invalid buffer size") {
+				t.Errorf("%s: expected buffer size %d error got %v", name, size, err)
+			}
+		}
+		sinks := map[string]pipe.SinkAllocatorFunc{
+			"Sink":       wav.Sink(&buffer{}, signal.BitDepth16),
+			"SinkStream": wav.SinkStream(&bytes.Buffer{}, signal.BitDepth16),
+			"SinkRing":   wav.SinkRing(&buffer{}, signal.BitDepth16, time.Second),
+		}
+		for name, sink := range sinks {
+			if _, err := sink(mutable.Context{}, size, props); err == nil || !strings.Contains(err.Error(), "invalid buffer size") {
+				t.Errorf("%s: expected buffer size %d error got %v", name, size, err)
+			}
+		}
+	}
+}
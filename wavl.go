@@ -0,0 +1,156 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Wave list identifiers.
+var (
+	wavlID = [4]byte{'w', 'a', 'v', 'l'}
+	slntID = [4]byte{'s', 'l', 'n', 't'}
+)
+
+// expandWaveList returns the stream with wave list replaced by a single
+// data chunk. Wave list is LIST chunk of "wavl" type that alternates data
+// chunks and slnt chunks, which declare the number of silent frames. The
+// silence is expanded inline, so the frames are decoded as a continuous
+// stream. Streams without wave list are returned as is.
+func (o *options) expandWaveList(rs io.ReadSeeker) (io.ReadSeeker, error) {
+	c, err := readForm(rs, o.riffFormType())
+	if err != nil {
+		// invalid streams are reported by decoder.
+		return rs, nil
+	}
+	list, ok, err := c.findList(rs, wavlID)
+	if err != nil || !ok {
+		return rs, err
+	}
+	f, err := readFormat(rs, c)
+	if err != nil {
+		return rs, nil
+	}
+	pieces, err := readWaveList(rs, list)
+	if err != nil {
+		return nil, err
+	}
+
+	var fill byte
+	if f.BitsPerSample == 8 && f.AudioFormat == formatPCM && !o.signed8 && c.index(signed8ID) == -1 {
+		// silence of unsigned 8-bit samples.
+		fill = 0x80
+	}
+	var (
+		data              []segment
+		size              int64
+		dataChunks, slnts int
+	)
+	for _, h := range pieces {
+		s := segment{offset: h.Offset, size: int64(h.Size)}
+		if h.ID == slntID {
+			payload, err := readPayload(rs, h)
+			if err != nil {
+				return nil, err
+			}
+			if len(payload) < 4 {
+				return nil, fmt.Errorf("slnt chunk at offset %d is too short: %d bytes", h.Offset-8, len(payload))
+			}
+			frames := int64(binary.LittleEndian.Uint32(payload))
+			s = segment{size: frames * int64(f.BlockAlign), silence: true, fill: fill}
+			slnts++
+		} else {
+			dataChunks++
+		}
+		data = append(data, s)
+		size += s.size
+	}
+	o.warn(list.Offset-8, "wave list of %d data and %d slnt chunks expanded into data chunk", dataChunks, slnts)
+
+	var chunks []segment
+	for _, h := range c.chunks {
+		if h != list && h.ID != dataID {
+			chunks = append(chunks, c.chunkSegment(h))
+		}
+	}
+	header := make([]byte, 8)
+	copy(header, dataID[:])
+	binary.LittleEndian.PutUint32(header[4:], uint32(size))
+	chunks = append(append(chunks, segment{data: header}), data...)
+
+	var total int64
+	for _, s := range chunks {
+		total += s.len()
+	}
+	// expanded stream keeps the form type.
+	riff := riffSegment(total)
+	formType := o.riffFormType()
+	copy(riff.data[8:], formType[:])
+	return newVirtualStream(rs, append([]segment{riff}, chunks...)), nil
+}
+
+// waveListContainer returns the container of the stream with wave list
+// expanded into data chunk, so its frames can be counted. Containers with
+// data chunk or without wave list are returned as is.
+func waveListContainer(rs io.ReadSeeker, c container) (container, error) {
+	if _, ok := c.find(dataID); ok {
+		return c, nil
+	}
+	defer rs.Seek(0, io.SeekStart)
+	var o options
+	expanded, err := o.expandWaveList(rs)
+	if err != nil || expanded == rs {
+		return c, err
+	}
+	return readContainer(expanded)
+}
+
+// findList returns the first LIST chunk with provided list type.
+func (c container) findList(rs io.ReadSeeker, listType [4]byte) (chunkHeader, bool, error) {
+	defer rs.Seek(0, io.SeekStart)
+	for _, h := range c.chunks {
+		if h.ID != listID || h.Size < 4 {
+			continue
+		}
+		if _, err := rs.Seek(h.Offset, io.SeekStart); err != nil {
+			return chunkHeader{}, false, fmt.Errorf("error seeking LIST chunk: %w", err)
+		}
+		var t [4]byte
+		if _, err := io.ReadFull(rs, t[:]); err != nil {
+			return chunkHeader{}, false, fmt.Errorf("error reading LIST chunk: %w", err)
+		}
+		if t == listType {
+			return h, true, nil
+		}
+	}
+	return chunkHeader{}, false, nil
+}
+
+// readWaveList returns the data and slnt sub-chunks of wave list in the
+// order of the stream. Other sub-chunks are skipped.
+func readWaveList(rs io.ReadSeeker, list chunkHeader) ([]chunkHeader, error) {
+	defer rs.Seek(0, io.SeekStart)
+	var pieces []chunkHeader
+	end := list.Offset + int64(list.Size)
+	for offset := list.Offset + 4; offset+8 <= end; {
+		if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("error seeking wave list: %w", err)
+		}
+		var h struct {
+			ID   [4]byte
+			Size uint32
+		}
+		if err := binary.Read(rs, binary.LittleEndian, &h); err != nil {
+			return nil, fmt.Errorf("error reading wave list: %w", err)
+		}
+		header := chunkHeader{ID: h.ID, Size: h.Size, Offset: offset + 8}
+		if header.end() > end+1 {
+			return nil, fmt.Errorf("%q chunk at offset %d exceeds wave list", h.ID[:], offset)
+		}
+		if h.ID == dataID || h.ID == slntID {
+			pieces = append(pieces, header)
+		}
+		offset = header.end()
+	}
+	return pieces, nil
+}
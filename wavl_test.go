@@ -0,0 +1,79 @@
+package wav_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+// waveList returns LIST chunk of wavl type with provided sub-chunks.
+func waveList(chunks ...[]byte) []byte {
+	payload := []byte("wavl")
+	for _, c := range chunks {
+		payload = append(payload, c...)
+	}
+	return chunk("LIST", payload)
+}
+
+// slnt returns slnt chunk of provided number of silent frames.
+func slnt(frames uint32) []byte {
+	p := make([]byte, 4)
+	binary.LittleEndian.PutUint32(p, frames)
+	return chunk("slnt", p)
+}
+
+func TestWaveList(t *testing.T) {
+	data := riff(
+		chunk("fmt ", fmtPayload(1, 16, 2, 44100)),
+		waveList(chunk("data", rampData(10)), slnt(5), chunk("data", rampData(3))),
+	)
+	var warnings []wav.Warning
+	var out buffer
+	transcode(t, wav.Source(bytes.NewReader(data), wav.WithWarnings(&warnings)), wav.Sink(&out, signal.BitDepth16))
+	expected := append(append(ramp(0, 10), make([]int16, 5)...), ramp(0, 3)...)
+	if samples := int16Samples(out.data); !reflect.DeepEqual(expected, samples) {
+		t.Errorf("expected %v got %v", expected, samples)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "wave list of 2 data and 1 slnt chunks") {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	// silence of unsigned 8-bit samples.
+	unsigned := riff(
+		chunk("fmt ", fmtPayload(2, 8, 2, 8000)),
+		waveList(slnt(3), chunk("data", []byte{0xFF, 0x00})),
+	)
+	r, err := wav.NewReader(bytes.NewReader(unsigned))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	samples := readAll(t, r)
+	if !reflect.DeepEqual(samples[:6], make([]float64, 6)) || samples[6] != 1 || samples[7] != -1 {
+		t.Errorf("unexpected 8-bit samples %v", samples)
+	}
+
+	// frames of wave list are declared by its chunks.
+	info, err := wav.Probe(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Frames != 18 || info.DataSize != 36 {
+		t.Errorf("expected 18 frames of 36 bytes got %d frames of %d bytes", info.Frames, info.DataSize)
+	}
+	r, err = wav.NewReader(bytes.NewReader(data), wav.StrictFrameCount(), wav.WithMaxFrames(18))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if samples := readAll(t, r); len(samples) != 18 {
+		t.Errorf("expected 18 strict frames got %d", len(samples))
+	}
+	if _, err := wav.NewReader(bytes.NewReader(data), wav.WithMaxFrames(17)); err == nil {
+		t.Errorf("expected frame limit error for wave list")
+	}
+}
@@ -0,0 +1,277 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-audio/audio"
+
+	"pipelined.dev/signal"
+)
+
+// Writer encodes frames of wav stream without pipe. It accepts the same
+// options as Sink.
+type Writer struct {
+	encoder   *encoder
+	quantizer quantizer
+	// PCM buffer for write, its data is shared by all writes.
+	pcm audio.IntBuffer
+	// 8-bits wav audio is encoded as unsigned signal, unless signed8 is
+	// set.
+	signed   signal.Signed
+	unsigned signal.Unsigned
+	signed8  bool
+	float    bool
+	// number of silence frames to write before the first buffer.
+	preroll int
+	// number of written frames and exact length of the stream, nil if
+	// length isn't limited.
+	frames      int
+	exactLength *int
+	// number of frames between checkpoints and written after the last
+	// one, zero interval if checkpoints are disabled.
+	checkpointInterval int
+	sinceCheckpoint    int
+	events             *emitter
+	process            BufferFunc
+	counters           *Counters
+	verifier           *verifier
+	// checksum of encoded samples, nil if checksum isn't written.
+	checksum *verifier
+	// justification of 24-bit samples in 4-byte containers, nil if
+	// samples are packed in 3 bytes.
+	container24 *Justification
+	// detector of input bit depth, nil if upconversion isn't reported.
+	depth *depthDetector
+	meter *meter
+}
+
+// NewWriter returns a new writer of wav stream with provided format.
+// Nothing is written until the first frames or Close.
+func NewWriter(ws io.WriteSeeker, f Format, options ...Option) (*Writer, error) {
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	opts := newOptions(options)
+	return opts.newWriter(ws, f, 0)
+}
+
+// newWriter returns a new writer with buffers allocated for bufferSize
+// frames.
+func (o *options) newWriter(ws io.WriteSeeker, f Format, bufferSize int) (*Writer, error) {
+	o, err := o.minimized(f)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.validateChunks(); err != nil {
+		return nil, err
+	}
+	if err := o.validateFloat(f); err != nil {
+		return nil, err
+	}
+	if err := o.validateContainer(f); err != nil {
+		return nil, err
+	}
+	if err := o.validateSilenceChunks(); err != nil {
+		return nil, err
+	}
+	q, err := o.newQuantizer(f.Channels)
+	if err != nil {
+		return nil, err
+	}
+	w := Writer{
+		encoder:            o.newEncoder(ws, f),
+		quantizer:          q,
+		preroll:            f.SampleRate.Events(o.preroll),
+		exactLength:        o.exactLength,
+		checkpointInterval: f.SampleRate.Events(o.checkpoints),
+		signed8:            o.signed8,
+		float:              o.float,
+		counters:           o.counters,
+		pcm: audio.IntBuffer{
+			Format: &audio.Format{
+				NumChannels: f.Channels,
+				SampleRate:  int(f.SampleRate),
+			},
+			SourceBitDepth: int(f.BitDepth),
+		},
+	}
+	w.container24 = o.container24
+	w.allocate(bufferSize)
+	if o.verify {
+		w.verifier = newVerifier(o.bytesPerSample(f), o.riffFormType())
+	}
+	if o.checksum {
+		w.checksum = newVerifier(o.bytesPerSample(f), o.riffFormType())
+	}
+	if o.upconversion && !o.float {
+		w.depth = o.newDepthDetector(f.BitDepth)
+	}
+	w.meter = o.newMeter(f.Channels)
+	w.process = o.bufferFunc
+	w.events = o.newEmitter(ComponentSink)
+	w.events.start(f)
+	return &w, nil
+}
+
+// Format returns the format of encoded stream.
+func (w *Writer) Format() Format {
+	return w.encoder.format
+}
+
+// allocate makes the buffers fit provided number of frames.
+func (w *Writer) allocate(frames int) {
+	f := w.encoder.format
+	alloc := signal.Allocator{
+		Channels: f.Channels,
+		Capacity: frames,
+		Length:   frames,
+	}
+	w.pcm.Data = make([]int, frames*f.Channels)
+	if w.unsignedSamples() {
+		w.unsigned = alloc.Uint8(f.BitDepth)
+	} else {
+		w.signed = alloc.Int64(f.BitDepth)
+	}
+}
+
+// Write encodes frames of provided buffer. It returns the number of
+// frames written, which is less than provided if exact length is reached.
+// The buffer must have the same number of channels as the stream.
+func (w *Writer) Write(src signal.Floating) (int, error) {
+	if channels := w.encoder.format.Channels; src.Channels() != channels {
+		return 0, fmt.Errorf("buffer has %d channels instead of %d", src.Channels(), channels)
+	}
+	if err := w.writePreroll(); err != nil {
+		return 0, err
+	}
+	w.process.process(src, src.Length())
+	w.depth.update(src)
+	n, err := w.write(src)
+	if err != nil {
+		return n, err
+	}
+	w.meter.update(src, n)
+	w.events.buffer(n)
+	return n, w.checkpoint(n)
+}
+
+// writePreroll writes the silence that precedes the first buffer.
+func (w *Writer) writePreroll() error {
+	if w.preroll == 0 {
+		return nil
+	}
+	frames := w.preroll
+	w.preroll = 0
+	return w.writeSilence(frames)
+}
+
+// write encodes frames of provided buffer.
+func (w *Writer) write(src signal.Floating) (int, error) {
+	length := w.limit(src.Length())
+	if length == 0 {
+		return 0, nil
+	}
+	if length != src.Length() {
+		src = src.Slice(0, length)
+	}
+	if length > len(w.pcm.Data)/w.encoder.format.Channels {
+		w.allocate(length)
+	}
+	var (
+		n   int
+		err error
+	)
+	switch {
+	case w.float:
+		n, err = w.writeFloat(src)
+	case w.unsignedSamples():
+		n, err = w.writeUnsigned(src)
+	default:
+		n, err = w.writeSigned(src)
+	}
+	w.frames += n
+	w.counters.update(w.frames, w.encoder.WrittenBytes)
+	return n, err
+}
+
+func (w *Writer) writeSigned(floats signal.Floating) (int, error) {
+	ints := w.signed
+	n, err := w.quantizer.floatingAsSigned(floats, ints)
+	if err != nil {
+		return 0, err
+	}
+	data := w.pcm.Data[:ints.Channels()*n]
+	signal.ReadInt(ints, data)
+	if err := w.encode(data); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (w *Writer) writeUnsigned(floats signal.Floating) (int, error) {
+	uints := w.unsigned
+	n, err := w.quantizer.floatingAsUnsigned(floats, uints)
+	if err != nil {
+		return 0, err
+	}
+	data := w.pcm.Data[:uints.Channels()*n]
+	for i := range data {
+		data[i] = int(uints.Sample(i))
+	}
+	if err := w.encode(data); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// encode writes provided samples. The samples are wrapped into a new PCM
+// buffer, so the shared buffer is never resliced.
+func (w *Writer) encode(data []int) error {
+	w.pack(data)
+	pcm := audio.IntBuffer{
+		Format:         w.pcm.Format,
+		SourceBitDepth: w.pcm.SourceBitDepth,
+		Data:           data,
+	}
+	if err := w.encoder.write(&pcm); err != nil {
+		return fmt.Errorf("error writing PCM buffer: %w", err)
+	}
+	w.verifier.update(data)
+	w.checksum.update(data)
+	return nil
+}
+
+// Close finalizes the stream: writes trailing chunks and updates the
+// sizes in the header. The underlying WriteSeeker isn't closed.
+func (w *Writer) Close() error {
+	if err := w.writePreroll(); err != nil {
+		return err
+	}
+	if err := w.pad(); err != nil {
+		return err
+	}
+	if w.checksum != nil {
+		w.encoder.trailing = append(w.encoder.trailing, w.checksum.chunk())
+	}
+	if err := w.encoder.close(); err != nil {
+		return err
+	}
+	w.depth.report()
+	if w.verifier != nil {
+		if err := w.verifier.verify(w.encoder.ws); err != nil {
+			return err
+		}
+	}
+	if w.counters != nil {
+		// go-audio encoder counts the bytes of updated header.
+		size, err := w.encoder.ws.Seek(0, io.SeekEnd)
+		if err != nil {
+			return fmt.Errorf("error seeking stream end: %w", err)
+		}
+		w.counters.update(w.frames, int(size))
+	}
+	w.meter.flush()
+	w.events.end()
+	return nil
+}
@@ -0,0 +1,96 @@
+package wav_test
+
+import (
+	"bytes"
+	"testing"
+
+	"pipelined.dev/audio/wav"
+
+	"pipelined.dev/signal"
+)
+
+func TestWriter(t *testing.T) {
+	samples := []float64{0, 0.5, -0.5, 1, 0.25, -0.25, -1, 0, 0.75, -0.75}
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16} {
+		var sink buffer
+		transcode(t, floatSource(44100, 2, samples), wav.Sink(&sink, bitDepth))
+
+		var out buffer
+		w, err := wav.NewWriter(&out, wav.Format{SampleRate: 44100, Channels: 2, BitDepth: bitDepth})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// buffers of different size.
+		for pos, length := 0, 1; pos < len(samples)/2; pos, length = pos+length, length+1 {
+			if remaining := len(samples)/2 - pos; remaining < length {
+				length = remaining
+			}
+			buf := signal.Allocator{Channels: 2, Length: length, Capacity: length}.Float64()
+			signal.WriteFloat64(samples[pos*2:], buf)
+			n, err := w.Write(buf)
+			if err != nil {
+				t.Fatalf("unexpected write error: %v", err)
+			}
+			if n != length {
+				t.Fatalf("expected %d frames written got %d", length, n)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected close error: %v", err)
+		}
+		if !bytes.Equal(sink.data, out.data) {
+			t.Errorf("bit depth %d: writer output doesn't match sink:\n%v\n%v", bitDepth, sink.data, out.data)
+		}
+	}
+
+	mono := signal.Allocator{Channels: 1, Length: 1, Capacity: 1}.Float64()
+	w, _ := wav.NewWriter(&buffer{}, wav.Format{SampleRate: 44100, Channels: 2, BitDepth: signal.BitDepth16})
+	if _, err := w.Write(mono); err == nil {
+		t.Errorf("expected error for channels mismatch")
+	}
+	if _, err := wav.NewWriter(&buffer{}, wav.Format{SampleRate: 44100, Channels: 2, BitDepth: signal.BitDepth4}); err == nil {
+		t.Errorf("expected error for unsupported bit depth")
+	}
+}
+
+func TestSinkPartialBuffer(t *testing.T) {
+	const frames = 3*bufferSize + 7
+	samples := make([]float64, 2*frames)
+	for i := range samples {
+		samples[i] = float64(i%100)/100 - 0.5
+	}
+	for _, bitDepth := range []signal.BitDepth{signal.BitDepth8, signal.BitDepth16, signal.BitDepth24} {
+		var out buffer
+		transcode(t, floatSource(44100, 2, samples), wav.Sink(&out, bitDepth))
+		info, err := wav.Probe(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Frames != frames {
+			t.Errorf("%d bits: expected %d frames got %d", bitDepth, frames, info.Frames)
+		}
+		if size := int64(len(out.data)) - 44; size != info.DataSize {
+			t.Errorf("%d bits: expected file data size %d got %d", bitDepth, info.DataSize, size)
+		}
+
+		var decoded []float64
+		r, err := wav.NewReader(bytes.NewReader(out.data))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		buf := signal.Allocator{Channels: 2, Length: frames, Capacity: frames}.Float64()
+		n, err := r.Read(buf)
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		for i := 0; i < n*2; i++ {
+			decoded = append(decoded, buf.Sample(i))
+		}
+		if len(decoded) != len(samples) {
+			t.Fatalf("%d bits: expected %d samples got %d", bitDepth, len(samples), len(decoded))
+		}
+		if d := decoded[len(decoded)-1] - samples[len(samples)-1]; d > 0.01 || d < -0.01 {
+			t.Errorf("%d bits: expected last sample %v got %v", bitDepth, samples[len(samples)-1], decoded[len(decoded)-1])
+		}
+	}
+}